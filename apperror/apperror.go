@@ -0,0 +1,147 @@
+// Package apperror berisi error domain bertipe dan format respons error JSON yang sama untuk
+// semua endpoint. Repository dan service mengembalikan *Error (atau membungkusnya dengan %w),
+// handler cukup memanggil Write tanpa memetakan status sendiri.
+package apperror
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+)
+
+// Code adalah jenis error yang dikirim ke klien pada field "code".
+type Code string
+
+const (
+	CodeBadRequest           Code = "bad_request"
+	CodeUnauthorized         Code = "unauthorized"
+	CodeForbidden            Code = "forbidden"
+	CodeNotFound             Code = "not_found"
+	CodeMethodNotAllowed     Code = "method_not_allowed"
+	CodeConflict             Code = "conflict"
+	CodePreconditionFailed   Code = "precondition_failed"
+	CodeUnsupportedMediaType Code = "unsupported_media_type"
+	CodePayloadTooLarge      Code = "payload_too_large"
+	CodeValidation           Code = "validation_failed"
+	CodePreconditionRequired Code = "precondition_required"
+	CodeInsufficientStock    Code = "insufficient_stock"
+	CodeInternal             Code = "internal_error"
+)
+
+var statuses = map[Code]int{
+	CodeBadRequest:           http.StatusBadRequest,
+	CodeUnauthorized:         http.StatusUnauthorized,
+	CodeForbidden:            http.StatusForbidden,
+	CodeNotFound:             http.StatusNotFound,
+	CodeMethodNotAllowed:     http.StatusMethodNotAllowed,
+	CodeConflict:             http.StatusConflict,
+	CodePreconditionFailed:   http.StatusPreconditionFailed,
+	CodeUnsupportedMediaType: http.StatusUnsupportedMediaType,
+	CodePayloadTooLarge:      http.StatusRequestEntityTooLarge,
+	CodeValidation:           http.StatusUnprocessableEntity,
+	CodePreconditionRequired: http.StatusPreconditionRequired,
+	CodeInsufficientStock:    http.StatusConflict,
+	CodeInternal:             http.StatusInternalServerError,
+}
+
+// Status mengembalikan status HTTP untuk code tersebut.
+func (c Code) Status() int {
+	if status, ok := statuses[c]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// Error adalah error domain bertipe. Details berisi data tambahan untuk klien,
+// contoh daftar kesalahan field pada CodeValidation.
+type Error struct {
+	Code    Code
+	Message string
+	Details interface{}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// New membuat error dengan code dan pesan tersebut.
+func New(code Code, format string, args ...interface{}) *Error {
+	if len(args) > 0 {
+		format = fmt.Sprintf(format, args...)
+	}
+	return &Error{Code: code, Message: format}
+}
+
+func BadRequest(format string, args ...interface{}) *Error {
+	return New(CodeBadRequest, format, args...)
+}
+
+func Unauthorized(format string, args ...interface{}) *Error {
+	return New(CodeUnauthorized, format, args...)
+}
+
+func Forbidden(format string, args ...interface{}) *Error {
+	return New(CodeForbidden, format, args...)
+}
+
+func NotFound(format string, args ...interface{}) *Error {
+	return New(CodeNotFound, format, args...)
+}
+
+func Conflict(format string, args ...interface{}) *Error {
+	return New(CodeConflict, format, args...)
+}
+
+func InsufficientStock(format string, args ...interface{}) *Error {
+	return New(CodeInsufficientStock, format, args...)
+}
+
+// Validation membuat error validasi dengan details berisi kesalahan per field.
+func Validation(message string, details interface{}) *Error {
+	return &Error{Code: CodeValidation, Message: message, Details: details}
+}
+
+// MethodNotAllowed dipakai handler untuk method HTTP yang tidak didukung endpoint.
+var MethodNotAllowed = New(CodeMethodNotAllowed, "Method not allowed")
+
+// CodeOf mengembalikan code dari error bertipe di rantai err, atau CodeInternal.
+func CodeOf(err error) Code {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr.Code
+	}
+	return CodeInternal
+}
+
+// Response adalah format respons error semua endpoint.
+type Response struct {
+	Code      Code        `json:"code" example:"not_found"`
+	Message   string      `json:"message" example:"product not found"`
+	Details   interface{} `json:"details,omitempty"`
+	RequestID string      `json:"request_id,omitempty" example:"9f86d081884c7d65"`
+}
+
+// Write menulis err sebagai respons JSON. Pesan dari rantai error (termasuk konteks dari %w)
+// dikirim apa adanya untuk error bertipe; error lain dicatat di log dan dijawab 500 dengan pesan
+// umum agar detail internal tidak bocor. request_id diambil dari header X-Request-ID yang
+// dipasang middleware Logger.
+func Write(w http.ResponseWriter, err error) {
+	requestID := w.Header().Get("X-Request-ID")
+
+	response := Response{Code: CodeInternal, Message: "Internal server error", RequestID: requestID}
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		response.Code = appErr.Code
+		response.Message = err.Error()
+		response.Details = appErr.Details
+	} else {
+		log.Printf("[ERROR] %s %v", requestID, err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Del("ETag")
+	w.WriteHeader(response.Code.Status())
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,440 @@
+package backup
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"kasir-api/images"
+	"kasir-api/storage"
+	"mime"
+	"path"
+	"strings"
+	"time"
+)
+
+// FormatVersion dinaikkan bila susunan arsip berubah sehingga arsip lama tidak salah dibaca.
+const FormatVersion = 1
+
+const (
+	manifestName = "manifest.json"
+	dataDir      = "data/"
+	filesDir     = "files/"
+
+	// restoreBatchSize adalah jumlah baris per INSERT saat restore
+	restoreBatchSize = 500
+)
+
+// Tables berisi seluruh tabel data kasir, tabel induk lebih dulu supaya foreign key terpenuhi saat restore.
+// Tabel schema_migrations tidak ikut: database tujuan dimigrasi sendiri sebelum restore.
+var Tables = []string{
+	"outlets",
+	"categories",
+	"products",
+	"product_units",
+	"product_components",
+	"modifier_groups",
+	"modifiers",
+	"outlet_products",
+	"product_prices",
+	"customers",
+	"vouchers",
+	"gift_cards",
+	"users",
+	"user_outlets",
+	"transactions",
+	"transaction_details",
+	"transaction_detail_components",
+	"transaction_detail_modifiers",
+	"loyalty_ledger",
+	"redemptions",
+	"carts",
+	"cart_items",
+	"stock_transfers",
+	"stock_transfer_lines",
+	"stock_movements",
+	"sync_tombstones",
+	"audit_log",
+}
+
+// compositeKeyTables tidak punya kolom id SERIAL sehingga sequence-nya tidak perlu disetel ulang
+var compositeKeyTables = map[string]bool{
+	"product_units":                 true,
+	"product_components":            true,
+	"outlet_products":               true,
+	"user_outlets":                  true,
+	"stock_transfer_lines":          true,
+	"transaction_detail_components": true,
+	"transaction_detail_modifiers":  true,
+}
+
+// syncVersionTables memakai sequence bersama sync_version_seq
+var syncVersionTables = []string{"products", "categories", "outlet_products", "sync_tombstones"}
+
+var (
+	ErrInvalidArchive = errors.New("invalid backup archive")
+	ErrNotEmpty       = errors.New("target database is not empty")
+)
+
+// Manifest mendeskripsikan isi arsip: versi format, versi migrasi terakhir dan jumlah baris per tabel.
+type Manifest struct {
+	Format        int         `json:"format"`
+	SchemaVersion string      `json:"schema_version"`
+	CreatedAt     time.Time   `json:"created_at"`
+	Tables        []TableInfo `json:"tables"`
+	Files         int         `json:"files"`
+}
+
+type TableInfo struct {
+	Name string `json:"name"`
+	Rows int    `json:"rows"`
+}
+
+// Write menulis backup logis seluruh tabel ke w sebagai arsip zip: satu file JSON Lines per tabel
+// (satu baris JSON per record), foto produk dari store di folder files/ dan manifest.json.
+// Semua tabel dibaca dari satu snapshot sehingga transaksi yang masuk selama backup tidak
+// membuat data setengah jadi.
+func Write(db *sql.DB, store storage.Storage, w io.Writer) (*Manifest, error) {
+	tx, err := db.BeginTx(context.Background(), &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	manifest := &Manifest{Format: FormatVersion, CreatedAt: time.Now()}
+	if err := tx.QueryRow("SELECT COALESCE(MAX(version), '') FROM schema_migrations").Scan(&manifest.SchemaVersion); err != nil {
+		return nil, err
+	}
+
+	zw := zip.NewWriter(w)
+	for _, table := range Tables {
+		rows, err := dumpTable(tx, zw, table)
+		if err != nil {
+			return nil, fmt.Errorf("backup %s: %w", table, err)
+		}
+		manifest.Tables = append(manifest.Tables, TableInfo{Name: table, Rows: rows})
+	}
+
+	manifest.Files, err = dumpFiles(tx, store, zw)
+	if err != nil {
+		return nil, fmt.Errorf("backup files: %w", err)
+	}
+
+	f, err := zw.Create(manifestName)
+	if err != nil {
+		return nil, err
+	}
+	encoder := json.NewEncoder(f)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(manifest); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+// dumpTable menulis setiap baris tabel sebagai JSON dari row_to_json, sehingga semua tipe kolom
+// (timestamptz, jsonb, uuid) tersimpan dalam bentuk teks yang bisa dibaca kembali oleh Postgres.
+func dumpTable(tx *sql.Tx, zw *zip.Writer, table string) (int, error) {
+	f, err := zw.Create(dataDir + table + ".jsonl")
+	if err != nil {
+		return 0, err
+	}
+
+	rows, err := tx.Query("SELECT row_to_json(t)::text FROM " + table + " t")
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	count := 0
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			return 0, err
+		}
+		if _, err := io.WriteString(f, line+"\n"); err != nil {
+			return 0, err
+		}
+		count++
+	}
+	return count, rows.Err()
+}
+
+// dumpFiles menyalin foto produk (file asli dan thumbnail) yang dipakai produk di snapshot.
+// File yang sudah tidak ada di storage dilewati.
+func dumpFiles(tx *sql.Tx, store storage.Storage, zw *zip.Writer) (int, error) {
+	rows, err := tx.Query("SELECT image_key FROM products WHERE image_key IS NOT NULL")
+	if err != nil {
+		return 0, err
+	}
+	var keys []string
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		keys = append(keys, images.Keys(key)...)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, key := range keys {
+		object, err := store.Get(key)
+		if errors.Is(err, storage.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		f, err := zw.Create(filesDir + key)
+		if err == nil {
+			_, err = io.Copy(f, object.Body)
+		}
+		object.Body.Close()
+		if err != nil {
+			return 0, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+// Restore memuat arsip backup ke database yang sudah dimigrasi tetapi masih kosong, dan foto produk
+// ke store. Versi migrasi arsip harus sama dengan database tujuan. Seluruh data dimuat dalam satu
+// transaksi: jika ada yang gagal, database tujuan tetap kosong. Sequence id disetel ulang agar
+// data baru tidak bentrok.
+func Restore(db *sql.DB, store storage.Storage, r io.ReaderAt, size int64) (*Manifest, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+	}
+
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	manifest, err := readManifest(files)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var schemaVersion string
+	if err := tx.QueryRow("SELECT COALESCE(MAX(version), '') FROM schema_migrations").Scan(&schemaVersion); err != nil {
+		return nil, err
+	}
+	if schemaVersion != manifest.SchemaVersion {
+		return nil, fmt.Errorf("%w: archive schema version %q does not match database version %q", ErrInvalidArchive, manifest.SchemaVersion, schemaVersion)
+	}
+
+	if err := checkEmpty(tx); err != nil {
+		return nil, err
+	}
+	// Outlet bawaan dari migrasi diganti dengan outlet dari arsip
+	if _, err := tx.Exec("DELETE FROM outlets"); err != nil {
+		return nil, err
+	}
+
+	for _, info := range manifest.Tables {
+		f, ok := files[dataDir+info.Name+".jsonl"]
+		if !ok {
+			return nil, fmt.Errorf("%w: missing data for table %s", ErrInvalidArchive, info.Name)
+		}
+		rows, err := loadTable(tx, f, info.Name)
+		if err != nil {
+			return nil, fmt.Errorf("restore %s: %w", info.Name, err)
+		}
+		if rows != info.Rows {
+			return nil, fmt.Errorf("%w: table %s has %d rows, manifest says %d", ErrInvalidArchive, info.Name, rows, info.Rows)
+		}
+	}
+
+	if err := resetSequences(tx); err != nil {
+		return nil, err
+	}
+
+	if err := loadFiles(zr.File, store); err != nil {
+		return nil, fmt.Errorf("restore files: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// readManifest membaca manifest dan memastikan hanya berisi tabel yang dikenal, dalam urutan Tables.
+func readManifest(files map[string]*zip.File) (*Manifest, error) {
+	f, ok := files[manifestName]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s not found", ErrInvalidArchive, manifestName)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	var manifest Manifest
+	if err := json.NewDecoder(rc).Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+	}
+	if manifest.Format != FormatVersion {
+		return nil, fmt.Errorf("%w: unsupported format version %d", ErrInvalidArchive, manifest.Format)
+	}
+
+	position := make(map[string]int, len(Tables))
+	for i, table := range Tables {
+		position[table] = i
+	}
+	last := -1
+	for _, info := range manifest.Tables {
+		i, ok := position[info.Name]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown table %s", ErrInvalidArchive, info.Name)
+		}
+		if i <= last {
+			return nil, fmt.Errorf("%w: table %s out of order", ErrInvalidArchive, info.Name)
+		}
+		last = i
+	}
+	return &manifest, nil
+}
+
+// checkEmpty menolak restore bila database tujuan sudah berisi data. Satu-satunya baris yang
+// boleh ada adalah outlet bawaan (id 1) yang dibuat oleh migrasi.
+func checkEmpty(tx *sql.Tx) error {
+	for _, table := range Tables {
+		query := "SELECT EXISTS (SELECT 1 FROM " + table + ")"
+		if table == "outlets" {
+			query = "SELECT EXISTS (SELECT 1 FROM outlets WHERE id <> 1)"
+		}
+
+		var exists bool
+		if err := tx.QueryRow(query).Scan(&exists); err != nil {
+			return err
+		}
+		if exists {
+			return fmt.Errorf("%w: table %s already has data", ErrNotEmpty, table)
+		}
+	}
+	return nil
+}
+
+// loadTable membaca file JSON Lines dan memasukkannya per batch lewat json_populate_recordset,
+// sehingga kolom dicocokkan berdasarkan nama dan tipe dikonversi oleh Postgres.
+func loadTable(tx *sql.Tx, f *zip.File, table string) (int, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return 0, err
+	}
+	defer rc.Close()
+
+	query := "INSERT INTO " + table + " SELECT * FROM json_populate_recordset(NULL::" + table + ", $1)"
+	reader := bufio.NewReader(rc)
+	batch := make([][]byte, 0, restoreBatchSize)
+	count := 0
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		payload := append(append([]byte("["), bytes.Join(batch, []byte(","))...), ']')
+		if _, err := tx.Exec(query, string(payload)); err != nil {
+			return err
+		}
+		count += len(batch)
+		batch = batch[:0]
+		return nil
+	}
+
+	for {
+		line, err := reader.ReadBytes('\n')
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			if !json.Valid(line) {
+				return 0, fmt.Errorf("%w: invalid row in %s", ErrInvalidArchive, f.Name)
+			}
+			batch = append(batch, line)
+			if len(batch) == restoreBatchSize {
+				if err := flush(); err != nil {
+					return 0, err
+				}
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+	if err := flush(); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// loadFiles menyimpan isi folder files/ di arsip ke store dengan key yang sama.
+func loadFiles(files []*zip.File, store storage.Storage) error {
+	for _, f := range files {
+		key, ok := strings.CutPrefix(f.Name, filesDir)
+		if !ok || strings.HasSuffix(key, "/") {
+			continue
+		}
+		if !storage.ValidKey(key) {
+			return fmt.Errorf("%w: invalid file name %s", ErrInvalidArchive, f.Name)
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		err = store.Put(key, rc, mime.TypeByExtension(path.Ext(key)))
+		rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resetSequences menyetel sequence id setiap tabel dan sync_version_seq ke nilai setelah data terbesar.
+func resetSequences(tx *sql.Tx) error {
+	for _, table := range Tables {
+		if compositeKeyTables[table] {
+			continue
+		}
+		query := "SELECT setval(pg_get_serial_sequence('" + table + "', 'id'), COALESCE(MAX(id), 0) + 1, false) FROM " + table
+		if _, err := tx.Exec(query); err != nil {
+			return err
+		}
+	}
+
+	query := "SELECT setval('sync_version_seq', GREATEST(0"
+	for _, table := range syncVersionTables {
+		query += ", (SELECT MAX(sync_version) FROM " + table + ")"
+	}
+	query += ") + 1, false)"
+	_, err := tx.Exec(query)
+	return err
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"kasir-api/backup"
+	"kasir-api/database"
+	"kasir-api/exports"
+	"kasir-api/repositories"
+	"kasir-api/services"
+	"kasir-api/storage"
+	"os"
+	"time"
+)
+
+const commandUsage = `Pemakaian: kasir-api [perintah] [opsi]
+
+Tanpa perintah, kasir-api menjalankan server API.
+
+Perintah:
+  export-catalog  Ekspor katalog (kategori dan produk) ke JSON, CSV atau XLSX
+  backup          Backup logis seluruh data kasir ke arsip zip
+  restore FILE    Muat arsip backup ke database kosong
+
+Jalankan "kasir-api [perintah] -h" untuk melihat opsi tiap perintah.
+`
+
+// runCommand menjalankan perintah CLI dan mengembalikan exit code.
+func runCommand(config Config, args []string) int {
+	var err error
+	switch args[0] {
+	case "export-catalog":
+		err = exportCatalogCommand(config, args[1:])
+	case "backup":
+		err = backupCommand(config, args[1:])
+	case "restore":
+		err = restoreCommand(config, args[1:])
+	case "help", "-h", "--help":
+		fmt.Print(commandUsage)
+		return 0
+	default:
+		fmt.Fprintf(os.Stderr, "Perintah tidak dikenal: %s\n\n%s", args[0], commandUsage)
+		return 2
+	}
+
+	if err == flag.ErrHelp {
+		return 0
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Gagal:", err.Error())
+		return 1
+	}
+	return 0
+}
+
+// openCommandDB membuka database dari DB_CONN. schema diisi untuk memilih tenant pada mode multi-tenant.
+func openCommandDB(config Config, schema string) (*sql.DB, error) {
+	if schema != "" {
+		return database.OpenSchema(config.DBConn, schema)
+	}
+	return database.InitDB(config.DBConn)
+}
+
+// commandStorage membuka folder foto produk, dengan prefix schema pada mode multi-tenant seperti di server.
+func commandStorage(config Config, schema string) storage.Storage {
+	var store storage.Storage = storage.NewLocal(config.ImageDir)
+	if schema != "" {
+		store = storage.WithPrefix(store, schema)
+	}
+	return store
+}
+
+// createOutput membuka file tujuan, atau stdout jika path kosong atau "-".
+func createOutput(path string) (io.WriteCloser, error) {
+	if path == "" || path == "-" {
+		return os.Stdout, nil
+	}
+	return os.Create(path)
+}
+
+func exportCatalogCommand(config Config, args []string) error {
+	fs := flag.NewFlagSet("export-catalog", flag.ContinueOnError)
+	format := fs.String("format", exports.FormatJSON, "format keluaran: json, csv atau xlsx")
+	outletID := fs.Int("outlet", 1, "ID outlet untuk harga dan stok")
+	lang := fs.String("lang", exports.LangID, "bahasa judul kolom CSV/XLSX: id atau en")
+	output := fs.String("o", "", "file tujuan (default stdout)")
+	schema := fs.String("schema", "", "schema tenant pada mode multi-tenant")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *format != exports.FormatJSON && *format != exports.FormatCSV && *format != exports.FormatXLSX {
+		return fmt.Errorf("unsupported export format %q", *format)
+	}
+
+	db, err := openCommandDB(config, *schema)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	service := services.NewProductService(repositories.NewProductRepository(db))
+	catalog, err := service.ExportCatalog(*outletID)
+	if err != nil {
+		return err
+	}
+
+	out, err := createOutput(*output)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	if *format == exports.FormatJSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(catalog)
+	}
+
+	writer, err := exports.Open(out, *format, "Katalog", exports.CatalogColumns, *lang)
+	if err != nil {
+		return err
+	}
+	if err := exports.WriteCatalog(writer, catalog); err != nil {
+		return err
+	}
+	return writer.Close()
+}
+
+func backupCommand(config Config, args []string) error {
+	fs := flag.NewFlagSet("backup", flag.ContinueOnError)
+	output := fs.String("o", "kasir-backup-"+time.Now().Format("20060102-150405")+".zip", "file arsip tujuan")
+	schema := fs.String("schema", "", "schema tenant pada mode multi-tenant")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	db, err := openCommandDB(config, *schema)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	out, err := createOutput(*output)
+	if err != nil {
+		return err
+	}
+
+	manifest, err := backup.Write(db, commandStorage(config, *schema), out)
+	if err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+
+	total := 0
+	for _, t := range manifest.Tables {
+		total += t.Rows
+	}
+	fmt.Fprintf(os.Stderr, "Backup selesai: %d tabel, %d baris, %d file gambar, versi skema %s\n", len(manifest.Tables), total, manifest.Files, manifest.SchemaVersion)
+	return nil
+}
+
+func restoreCommand(config Config, args []string) error {
+	fs := flag.NewFlagSet("restore", flag.ContinueOnError)
+	schema := fs.String("schema", "", "schema tenant pada mode multi-tenant (tenant harus sudah dibuat)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("restore requires exactly one archive file")
+	}
+
+	file, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	db, err := openCommandDB(config, *schema)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	// Database tujuan dimigrasi lebih dulu supaya tabelnya sama dengan database asal
+	if err := database.Migrate(db); err != nil {
+		return err
+	}
+
+	manifest, err := backup.Restore(db, commandStorage(config, *schema), file, info.Size())
+	if err != nil {
+		return err
+	}
+
+	total := 0
+	for _, t := range manifest.Tables {
+		total += t.Rows
+	}
+	fmt.Fprintf(os.Stderr, "Restore selesai: %d tabel, %d baris, %d file gambar dari backup %s\n", len(manifest.Tables), total, manifest.Files, manifest.CreatedAt.Format(time.RFC3339))
+	return nil
+}
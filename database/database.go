@@ -3,6 +3,8 @@ package database
 import (
 	"database/sql"
 	"log"
+	"net/url"
+	"strings"
 
 	_ "github.com/lib/pq"
 )
@@ -28,3 +30,37 @@ func InitDB(connectionString string) (*sql.DB, error) {
 
 	return db, nil
 }
+
+// OpenSchema membuka pool koneksi untuk satu tenant. search_path hanya berisi schema tenant,
+// sehingga query tanpa nama schema tidak bisa membaca tabel tenant lain maupun schema public.
+func OpenSchema(connectionString string, schema string) (*sql.DB, error) {
+	if strings.HasPrefix(connectionString, "postgres://") || strings.HasPrefix(connectionString, "postgresql://") {
+		u, err := url.Parse(connectionString)
+		if err != nil {
+			return nil, err
+		}
+		query := u.Query()
+		query.Set("search_path", schema)
+		u.RawQuery = query.Encode()
+		connectionString = u.String()
+	} else {
+		connectionString += " search_path=" + schema
+	}
+
+	db, err := sql.Open("postgres", connectionString)
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Ping()
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	// Pool kecil per tenant agar total koneksi tetap wajar
+	db.SetMaxOpenConns(5)
+	db.SetMaxIdleConns(2)
+
+	return db, nil
+}
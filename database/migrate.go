@@ -0,0 +1,85 @@
+package database
+
+import (
+	"database/sql"
+	"embed"
+	"io/fs"
+	"log"
+	"sort"
+	"strings"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+//go:embed platform/*.sql
+var platformFiles embed.FS
+
+// Migrate menjalankan file SQL di folder migrations secara berurutan berdasarkan nama file.
+// Migrasi yang sudah dijalankan dicatat di tabel schema_migrations sehingga tidak diulang.
+// Pada mode multi-tenant, db adalah koneksi dengan search_path schema tenant.
+func Migrate(db *sql.DB) error {
+	return migrate(db, migrationFiles, "migrations", "schema_migrations")
+}
+
+// MigratePlatform menjalankan migrasi tabel platform multi-tenant (daftar tenant) di schema public.
+func MigratePlatform(db *sql.DB) error {
+	return migrate(db, platformFiles, "platform", "platform_migrations")
+}
+
+func migrate(db *sql.DB, files embed.FS, dir string, table string) error {
+	_, err := db.Exec(`CREATE TABLE IF NOT EXISTS ` + table + ` (
+		version TEXT PRIMARY KEY,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	)`)
+	if err != nil {
+		return err
+	}
+
+	names, err := fs.Glob(files, dir+"/*.sql")
+	if err != nil {
+		return err
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		version := strings.TrimSuffix(strings.TrimPrefix(name, dir+"/"), ".sql")
+
+		var exists bool
+		err := db.QueryRow("SELECT EXISTS (SELECT 1 FROM "+table+" WHERE version = $1)", version).Scan(&exists)
+		if err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+
+		content, err := files.ReadFile(name)
+		if err != nil {
+			return err
+		}
+
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(string(content)); err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		if _, err := tx.Exec("INSERT INTO "+table+" (version) VALUES ($1)", version); err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+
+		log.Println("Migration applied:", dir+"/"+version)
+	}
+
+	return nil
+}
@@ -0,0 +1,2 @@
+-- Jumlah cetak struk, cetakan kedua dan seterusnya ditandai sebagai salinan
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS print_count INT NOT NULL DEFAULT 0;
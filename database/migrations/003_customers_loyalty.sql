@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS customers (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    phone VARCHAR(32) UNIQUE,
+    email VARCHAR(255),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS customer_id INT REFERENCES customers(id);
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS discount_amount INT NOT NULL DEFAULT 0;
+CREATE INDEX IF NOT EXISTS idx_transactions_customer_id ON transactions(customer_id);
+
+-- Buku poin: earn bernilai positif, redeem dan expire bernilai negatif
+CREATE TABLE IF NOT EXISTS loyalty_ledger (
+    id SERIAL PRIMARY KEY,
+    customer_id INT NOT NULL REFERENCES customers(id),
+    transaction_id INT REFERENCES transactions(id),
+    type VARCHAR(16) NOT NULL CHECK (type IN ('earn', 'redeem', 'expire')),
+    points INT NOT NULL,
+    expires_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_loyalty_ledger_customer_id ON loyalty_ledger(customer_id);
@@ -0,0 +1,43 @@
+-- Voucher potongan harga. type fixed: value dalam rupiah, type percent: value dalam persen.
+-- product_ids dan category_ids kosong berarti berlaku untuk semua produk.
+CREATE TABLE IF NOT EXISTS vouchers (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(64) NOT NULL UNIQUE,
+    type VARCHAR(16) NOT NULL CHECK (type IN ('fixed', 'percent')),
+    value INT NOT NULL CHECK (value > 0),
+    max_discount INT,
+    min_spend INT NOT NULL DEFAULT 0,
+    usage_limit INT,
+    used_count INT NOT NULL DEFAULT 0,
+    product_ids INT[] NOT NULL DEFAULT '{}',
+    category_ids INT[] NOT NULL DEFAULT '{}',
+    starts_at TIMESTAMPTZ,
+    expires_at TIMESTAMPTZ,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Kartu hadiah bersaldo, dipakai sebagai alat bayar
+CREATE TABLE IF NOT EXISTS gift_cards (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(64) NOT NULL UNIQUE,
+    initial_balance INT NOT NULL CHECK (initial_balance > 0),
+    balance INT NOT NULL CHECK (balance >= 0),
+    expires_at TIMESTAMPTZ,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS redemptions (
+    id SERIAL PRIMARY KEY,
+    transaction_id INT NOT NULL REFERENCES transactions(id),
+    voucher_id INT REFERENCES vouchers(id),
+    gift_card_id INT REFERENCES gift_cards(id),
+    amount INT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK ((voucher_id IS NULL) <> (gift_card_id IS NULL))
+);
+CREATE INDEX IF NOT EXISTS idx_redemptions_voucher_id ON redemptions(voucher_id);
+CREATE INDEX IF NOT EXISTS idx_redemptions_gift_card_id ON redemptions(gift_card_id);
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS gift_card_amount INT NOT NULL DEFAULT 0;
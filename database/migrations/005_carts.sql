@@ -0,0 +1,22 @@
+-- Keranjang di server: open -> held -> open (resume), lalu checked_out atau abandoned.
+-- reserved_until diisi jika keranjang memesan stok sementara (soft reservation).
+CREATE TABLE IF NOT EXISTS carts (
+    id SERIAL PRIMARY KEY,
+    status VARCHAR(16) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'held', 'checked_out', 'abandoned')),
+    terminal VARCHAR(64) NOT NULL DEFAULT '',
+    note TEXT NOT NULL DEFAULT '',
+    customer_id INT REFERENCES customers(id),
+    reserve BOOLEAN NOT NULL DEFAULT FALSE,
+    reserved_until TIMESTAMPTZ,
+    transaction_id INT REFERENCES transactions(id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_carts_status ON carts(status);
+
+CREATE TABLE IF NOT EXISTS cart_items (
+    cart_id INT NOT NULL REFERENCES carts(id) ON DELETE CASCADE,
+    product_id INT NOT NULL REFERENCES products(id),
+    quantity INT NOT NULL CHECK (quantity > 0),
+    PRIMARY KEY (cart_id, product_id)
+);
@@ -0,0 +1,50 @@
+-- Transaksi offline dari terminal POS: client_uuid menjamin upload ulang tidak tercatat dua kali
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS client_uuid UUID UNIQUE;
+
+-- Versi perubahan untuk delta sync. Satu sequence dipakai bersama sehingga cursor berlaku
+-- untuk produk, kategori dan data yang dihapus sekaligus.
+CREATE SEQUENCE IF NOT EXISTS sync_version_seq;
+
+ALTER TABLE products ADD COLUMN IF NOT EXISTS sync_version BIGINT NOT NULL DEFAULT nextval('sync_version_seq');
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS sync_version BIGINT NOT NULL DEFAULT nextval('sync_version_seq');
+CREATE INDEX IF NOT EXISTS idx_products_sync_version ON products(sync_version);
+CREATE INDEX IF NOT EXISTS idx_categories_sync_version ON categories(sync_version);
+
+CREATE TABLE IF NOT EXISTS sync_tombstones (
+    id SERIAL PRIMARY KEY,
+    entity VARCHAR(32) NOT NULL,
+    entity_id INT NOT NULL,
+    sync_version BIGINT NOT NULL DEFAULT nextval('sync_version_seq'),
+    deleted_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_sync_tombstones_sync_version ON sync_tombstones(sync_version);
+
+CREATE OR REPLACE FUNCTION bump_sync_version() RETURNS TRIGGER AS $$
+BEGIN
+    NEW.sync_version := nextval('sync_version_seq');
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION record_sync_tombstone() RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO sync_tombstones (entity, entity_id) VALUES (TG_ARGV[0], OLD.id);
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS products_sync_version ON products;
+CREATE TRIGGER products_sync_version BEFORE UPDATE ON products
+    FOR EACH ROW EXECUTE FUNCTION bump_sync_version();
+
+DROP TRIGGER IF EXISTS categories_sync_version ON categories;
+CREATE TRIGGER categories_sync_version BEFORE UPDATE ON categories
+    FOR EACH ROW EXECUTE FUNCTION bump_sync_version();
+
+DROP TRIGGER IF EXISTS products_sync_tombstone ON products;
+CREATE TRIGGER products_sync_tombstone AFTER DELETE ON products
+    FOR EACH ROW EXECUTE FUNCTION record_sync_tombstone('product');
+
+DROP TRIGGER IF EXISTS categories_sync_tombstone ON categories;
+CREATE TRIGGER categories_sync_tombstone AFTER DELETE ON categories
+    FOR EACH ROW EXECUTE FUNCTION record_sync_tombstone('category');
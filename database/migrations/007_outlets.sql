@@ -0,0 +1,56 @@
+-- Multi-outlet: produk menjadi katalog bersama, stok dan harga khusus disimpan per outlet.
+CREATE TABLE IF NOT EXISTS outlets (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(32) NOT NULL UNIQUE,
+    name VARCHAR(255) NOT NULL,
+    address TEXT NOT NULL DEFAULT '',
+    phone VARCHAR(32) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Outlet bawaan (id 1) menampung data yang sudah ada sebelum multi-outlet
+INSERT INTO outlets (id, code, name) VALUES (1, 'PUSAT', 'Outlet Pusat') ON CONFLICT (id) DO NOTHING;
+SELECT setval(pg_get_serial_sequence('outlets', 'id'), GREATEST((SELECT MAX(id) FROM outlets), 1));
+
+-- price NULL berarti outlet memakai harga katalog
+CREATE TABLE IF NOT EXISTS outlet_products (
+    outlet_id INT NOT NULL REFERENCES outlets(id) ON DELETE CASCADE,
+    product_id INT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    stock INT NOT NULL DEFAULT 0,
+    price INT,
+    sync_version BIGINT NOT NULL DEFAULT nextval('sync_version_seq'),
+    PRIMARY KEY (outlet_id, product_id)
+);
+CREATE INDEX IF NOT EXISTS idx_outlet_products_sync_version ON outlet_products(outlet_id, sync_version);
+
+DROP TRIGGER IF EXISTS outlet_products_sync_version ON outlet_products;
+CREATE TRIGGER outlet_products_sync_version BEFORE UPDATE ON outlet_products
+    FOR EACH ROW EXECUTE FUNCTION bump_sync_version();
+
+INSERT INTO outlet_products (outlet_id, product_id, stock)
+    SELECT 1, id, stock FROM products
+    ON CONFLICT (outlet_id, product_id) DO NOTHING;
+ALTER TABLE products DROP COLUMN IF EXISTS stock;
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS outlet_id INT NOT NULL DEFAULT 1 REFERENCES outlets(id);
+ALTER TABLE transactions ALTER COLUMN outlet_id DROP DEFAULT;
+CREATE INDEX IF NOT EXISTS idx_transactions_outlet_created_at ON transactions(outlet_id, created_at);
+
+ALTER TABLE carts ADD COLUMN IF NOT EXISTS outlet_id INT NOT NULL DEFAULT 1 REFERENCES outlets(id);
+ALTER TABLE carts ALTER COLUMN outlet_id DROP DEFAULT;
+
+-- Pengguna masuk dengan API key masing-masing (disimpan sebagai hash SHA-256).
+-- owner bisa mengakses semua outlet, cashier hanya outlet yang ditugaskan.
+CREATE TABLE IF NOT EXISTS users (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    role VARCHAR(16) NOT NULL DEFAULT 'cashier' CHECK (role IN ('owner', 'cashier')),
+    api_key_hash CHAR(64) NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS user_outlets (
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    outlet_id INT NOT NULL REFERENCES outlets(id) ON DELETE CASCADE,
+    PRIMARY KEY (user_id, outlet_id)
+);
@@ -0,0 +1,45 @@
+-- Buku stok: setiap perubahan stok outlet dicatat sebagai mutasi bertanda (+ masuk, - keluar)
+CREATE TABLE IF NOT EXISTS stock_movements (
+    id SERIAL PRIMARY KEY,
+    outlet_id INT NOT NULL REFERENCES outlets(id),
+    product_id INT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    quantity INT NOT NULL,
+    type VARCHAR(24) NOT NULL CHECK (type IN ('sale', 'adjustment', 'transfer_out', 'transfer_in')),
+    transaction_id INT REFERENCES transactions(id),
+    transfer_id INT,
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_stock_movements_outlet_product ON stock_movements(outlet_id, product_id, created_at);
+
+-- Saldo awal buku stok dari stok yang sudah ada
+INSERT INTO stock_movements (outlet_id, product_id, quantity, type, note)
+    SELECT outlet_id, product_id, stock, 'adjustment', 'saldo awal' FROM outlet_products WHERE stock <> 0;
+
+-- Transfer stok: requested -> shipped -> received / received_with_discrepancy, atau cancelled sebelum dikirim
+CREATE TABLE IF NOT EXISTS stock_transfers (
+    id SERIAL PRIMARY KEY,
+    from_outlet_id INT NOT NULL REFERENCES outlets(id),
+    to_outlet_id INT NOT NULL REFERENCES outlets(id),
+    status VARCHAR(32) NOT NULL DEFAULT 'requested'
+        CHECK (status IN ('requested', 'shipped', 'received', 'received_with_discrepancy', 'cancelled')),
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    shipped_at TIMESTAMPTZ,
+    received_at TIMESTAMPTZ,
+    CHECK (from_outlet_id <> to_outlet_id)
+);
+CREATE INDEX IF NOT EXISTS idx_stock_transfers_status ON stock_transfers(status);
+
+CREATE TABLE IF NOT EXISTS stock_transfer_lines (
+    transfer_id INT NOT NULL REFERENCES stock_transfers(id) ON DELETE CASCADE,
+    product_id INT NOT NULL REFERENCES products(id),
+    quantity INT NOT NULL CHECK (quantity > 0),
+    quantity_shipped INT,
+    quantity_received INT,
+    PRIMARY KEY (transfer_id, product_id)
+);
+
+ALTER TABLE stock_movements DROP CONSTRAINT IF EXISTS stock_movements_transfer_id_fkey;
+ALTER TABLE stock_movements ADD CONSTRAINT stock_movements_transfer_id_fkey
+    FOREIGN KEY (transfer_id) REFERENCES stock_transfers(id);
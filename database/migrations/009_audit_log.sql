@@ -0,0 +1,34 @@
+-- Jejak audit setiap perubahan data lewat API. Tidak ada foreign key supaya jejak tetap ada
+-- walaupun data yang diubah (produk, pengguna) sudah dihapus.
+CREATE TABLE IF NOT EXISTS audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    actor VARCHAR(255) NOT NULL,
+    user_id INT,
+    action VARCHAR(16) NOT NULL CHECK (action IN ('create', 'update', 'delete')),
+    entity VARCHAR(32) NOT NULL,
+    entity_id INT NOT NULL,
+    before JSONB,
+    after JSONB,
+    changes JSONB,
+    ip VARCHAR(64) NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL DEFAULT '',
+    request_id VARCHAR(64) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_audit_log_entity ON audit_log(entity, entity_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_audit_log_created_at ON audit_log(created_at);
+
+-- Audit log hanya boleh ditambah: UPDATE, DELETE dan TRUNCATE ditolak
+CREATE OR REPLACE FUNCTION reject_audit_log_change() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_log_append_only ON audit_log;
+CREATE TRIGGER audit_log_append_only BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW EXECUTE FUNCTION reject_audit_log_change();
+
+DROP TRIGGER IF EXISTS audit_log_no_truncate ON audit_log;
+CREATE TRIGGER audit_log_no_truncate BEFORE TRUNCATE ON audit_log
+    FOR EACH STATEMENT EXECUTE FUNCTION reject_audit_log_change();
@@ -0,0 +1,19 @@
+-- Produk tidak lagi dihapus permanen: deleted_at menyembunyikannya dari katalog dan checkout,
+-- sementara transaksi dan laporan lama tetap bisa membaca nama produk.
+ALTER TABLE products ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+CREATE INDEX IF NOT EXISTS idx_products_deleted_at ON products(deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- Terminal offline tetap menerima tombstone saat produk dihapus. Saat dipulihkan, sync_version
+-- produk naik lagi sehingga produk dikirim ulang pada delta sync berikutnya.
+CREATE OR REPLACE FUNCTION record_soft_delete_tombstone() RETURNS TRIGGER AS $$
+BEGIN
+    IF OLD.deleted_at IS NULL AND NEW.deleted_at IS NOT NULL THEN
+        INSERT INTO sync_tombstones (entity, entity_id) VALUES (TG_ARGV[0], NEW.id);
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS products_soft_delete_tombstone ON products;
+CREATE TRIGGER products_soft_delete_tombstone AFTER UPDATE OF deleted_at ON products
+    FOR EACH ROW EXECUTE FUNCTION record_soft_delete_tombstone('product');
@@ -0,0 +1,24 @@
+-- Riwayat harga katalog. applied_at kosong berarti harga terjadwal yang belum berlaku;
+-- scheduler menyalin harga yang sudah jatuh tempo ke products.price.
+CREATE TABLE IF NOT EXISTS product_prices (
+    id SERIAL PRIMARY KEY,
+    product_id INT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    price INT NOT NULL CHECK (price >= 0),
+    effective_from TIMESTAMPTZ NOT NULL,
+    applied_at TIMESTAMPTZ,
+    note TEXT NOT NULL DEFAULT '',
+    created_by VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_product_prices_product ON product_prices(product_id, effective_from);
+CREATE INDEX IF NOT EXISTS idx_product_prices_pending ON product_prices(effective_from) WHERE applied_at IS NULL;
+
+-- Harga saat ini menjadi awal riwayat
+INSERT INTO product_prices (product_id, price, effective_from, applied_at, note)
+    SELECT id, price, NOW(), NOW(), 'harga awal' FROM products
+    WHERE NOT EXISTS (SELECT 1 FROM product_prices pp WHERE pp.product_id = products.id);
+
+-- Harga satuan saat transaksi terjadi, tidak ikut berubah saat harga produk diganti
+ALTER TABLE transaction_details ADD COLUMN IF NOT EXISTS unit_price INT;
+UPDATE transaction_details SET unit_price = CASE WHEN quantity > 0 THEN subtotal / quantity ELSE 0 END WHERE unit_price IS NULL;
+ALTER TABLE transaction_details ALTER COLUMN unit_price SET NOT NULL;
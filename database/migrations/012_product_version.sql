@@ -0,0 +1,13 @@
+-- Versi baris produk untuk optimistic concurrency (ETag / If-Match). Naik setiap kali produk diubah.
+ALTER TABLE products ADD COLUMN IF NOT EXISTS version INT NOT NULL DEFAULT 1;
+
+CREATE OR REPLACE FUNCTION bump_row_version() RETURNS TRIGGER AS $$
+BEGIN
+    NEW.version := OLD.version + 1;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS products_row_version ON products;
+CREATE TRIGGER products_row_version BEFORE UPDATE ON products
+    FOR EACH ROW EXECUTE FUNCTION bump_row_version();
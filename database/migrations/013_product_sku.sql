@@ -0,0 +1,5 @@
+-- SKU (kode barang) untuk impor massal: impor memperbarui produk dengan SKU yang sama.
+-- SKU boleh kosong dan hanya unik di antara produk yang belum dihapus.
+ALTER TABLE products ADD COLUMN IF NOT EXISTS sku VARCHAR(64);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_products_sku ON products(sku) WHERE sku IS NOT NULL AND deleted_at IS NULL;
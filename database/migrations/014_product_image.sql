@@ -0,0 +1,3 @@
+-- Foto produk untuk layar pelanggan dan menu. image_key adalah key file asli di storage;
+-- thumbnail disimpan di key turunan sehingga cukup satu kolom.
+ALTER TABLE products ADD COLUMN IF NOT EXISTS image_key VARCHAR(255);
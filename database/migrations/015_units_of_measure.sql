@@ -0,0 +1,30 @@
+-- Satuan produk. Stok dan semua hitungan stok memakai satuan dasar produk (products.unit);
+-- product_units berisi satuan jual lain dengan faktor konversi ke satuan dasar, contoh box = 12 pcs.
+ALTER TABLE products ADD COLUMN IF NOT EXISTS unit VARCHAR(16) NOT NULL DEFAULT 'pcs';
+
+CREATE TABLE IF NOT EXISTS product_units (
+    product_id INT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    unit VARCHAR(16) NOT NULL,
+    factor NUMERIC(14, 3) NOT NULL CHECK (factor > 0),
+    price INT CHECK (price >= 0),
+    PRIMARY KEY (product_id, unit)
+);
+
+-- Kuantitas pecahan (misalnya 0,75 kg) dengan tiga angka desimal
+ALTER TABLE outlet_products ALTER COLUMN stock TYPE NUMERIC(14, 3);
+ALTER TABLE stock_movements ALTER COLUMN quantity TYPE NUMERIC(14, 3);
+ALTER TABLE stock_transfer_lines
+    ALTER COLUMN quantity TYPE NUMERIC(14, 3),
+    ALTER COLUMN quantity_shipped TYPE NUMERIC(14, 3),
+    ALTER COLUMN quantity_received TYPE NUMERIC(14, 3);
+
+-- Item keranjang dan detail transaksi menyimpan satuan jual; NULL pada keranjang berarti satuan dasar
+ALTER TABLE cart_items ALTER COLUMN quantity TYPE NUMERIC(14, 3);
+ALTER TABLE cart_items ADD COLUMN IF NOT EXISTS unit VARCHAR(16);
+
+-- base_quantity adalah kuantitas dalam satuan dasar, dipakai laporan dan buku stok
+ALTER TABLE transaction_details ALTER COLUMN quantity TYPE NUMERIC(14, 3);
+ALTER TABLE transaction_details ADD COLUMN IF NOT EXISTS unit VARCHAR(16) NOT NULL DEFAULT 'pcs';
+ALTER TABLE transaction_details ADD COLUMN IF NOT EXISTS base_quantity NUMERIC(14, 3);
+UPDATE transaction_details SET base_quantity = quantity WHERE base_quantity IS NULL;
+ALTER TABLE transaction_details ALTER COLUMN base_quantity SET NOT NULL;
@@ -0,0 +1,21 @@
+-- Paket (bundle) dan resep: produk tanpa stok sendiri yang mengurangi stok komponennya saat dijual.
+-- quantity dalam satuan dasar komponen per satu satuan dasar produk, contoh 0,018 kg kopi per latte.
+ALTER TABLE products ADD COLUMN IF NOT EXISTS type VARCHAR(16) NOT NULL DEFAULT 'standard'
+    CHECK (type IN ('standard', 'bundle', 'recipe'));
+
+CREATE TABLE IF NOT EXISTS product_components (
+    product_id INT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    component_id INT NOT NULL REFERENCES products(id),
+    quantity NUMERIC(14, 3) NOT NULL CHECK (quantity > 0),
+    PRIMARY KEY (product_id, component_id),
+    CHECK (product_id <> component_id)
+);
+CREATE INDEX IF NOT EXISTS idx_product_components_component ON product_components(component_id);
+
+-- Komponen yang terpakai per detail transaksi, dipakai laporan pemakaian bahan
+CREATE TABLE IF NOT EXISTS transaction_detail_components (
+    transaction_detail_id INT NOT NULL REFERENCES transaction_details(id) ON DELETE CASCADE,
+    product_id INT NOT NULL REFERENCES products(id),
+    quantity NUMERIC(14, 3) NOT NULL,
+    PRIMARY KEY (transaction_detail_id, product_id)
+);
@@ -0,0 +1,35 @@
+-- Modifier: pilihan tambahan produk seperti extra shot, kurang gula atau susu oat. Pembeli memilih
+-- antara min_select dan max_select modifier dari setiap kelompok; price ditambahkan ke harga item.
+CREATE TABLE IF NOT EXISTS modifier_groups (
+    id SERIAL PRIMARY KEY,
+    product_id INT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    min_select INT NOT NULL DEFAULT 0 CHECK (min_select >= 0),
+    max_select INT NOT NULL CHECK (max_select >= 1 AND max_select >= min_select),
+    position INT NOT NULL DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS idx_modifier_groups_product ON modifier_groups(product_id);
+
+CREATE TABLE IF NOT EXISTS modifiers (
+    id SERIAL PRIMARY KEY,
+    group_id INT NOT NULL REFERENCES modifier_groups(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    price INT NOT NULL DEFAULT 0 CHECK (price >= 0),
+    position INT NOT NULL DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS idx_modifiers_group ON modifiers(group_id);
+
+-- Modifier yang dipilih pada item keranjang
+ALTER TABLE cart_items ADD COLUMN IF NOT EXISTS modifier_ids INT[] NOT NULL DEFAULT '{}';
+
+-- Modifier per detail transaksi dengan nama dan harga saat checkout, dipakai laporan popularitas modifier
+CREATE TABLE IF NOT EXISTS transaction_detail_modifiers (
+    transaction_detail_id INT NOT NULL REFERENCES transaction_details(id) ON DELETE CASCADE,
+    modifier_id INT REFERENCES modifiers(id) ON DELETE SET NULL,
+    group_name VARCHAR(255) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    price INT NOT NULL,
+    position INT NOT NULL DEFAULT 0,
+    PRIMARY KEY (transaction_detail_id, group_name, name)
+);
+CREATE INDEX IF NOT EXISTS idx_transaction_detail_modifiers_modifier ON transaction_detail_modifiers(modifier_id);
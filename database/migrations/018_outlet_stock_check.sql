@@ -0,0 +1,12 @@
+-- Stok outlet tidak boleh minus. Checkout mengurangi stok dengan UPDATE bersyarat stock >= jumlah,
+-- constraint ini menjaga agar jalur lain pun tidak bisa menjual melebihi stok.
+
+-- Checkout lama bisa membuat stok minus. Stok tersebut dinolkan dulu dan selisihnya dicatat di buku
+-- stok sebagai penyesuaian, agar constraint di bawah bisa dipasang.
+INSERT INTO stock_movements (outlet_id, product_id, quantity, type, note)
+    SELECT outlet_id, product_id, -stock, 'adjustment', 'stok minus dinolkan'
+    FROM outlet_products WHERE stock < 0;
+UPDATE outlet_products SET stock = 0 WHERE stock < 0;
+
+ALTER TABLE outlet_products DROP CONSTRAINT IF EXISTS outlet_products_stock_check;
+ALTER TABLE outlet_products ADD CONSTRAINT outlet_products_stock_check CHECK (stock >= 0);
@@ -0,0 +1,6 @@
+-- Item keranjang mendapat id baris sendiri sehingga produk yang sama bisa masuk beberapa kali
+-- dengan satuan atau modifier berbeda.
+ALTER TABLE cart_items ADD COLUMN IF NOT EXISTS id SERIAL;
+ALTER TABLE cart_items DROP CONSTRAINT IF EXISTS cart_items_pkey;
+ALTER TABLE cart_items ADD PRIMARY KEY (id);
+CREATE INDEX IF NOT EXISTS idx_cart_items_cart ON cart_items(cart_id, product_id);
@@ -0,0 +1,11 @@
+-- Daftar tenant pada mode multi-tenant. Data setiap tenant ada di schema sendiri (schema_name),
+-- dan API key pemilik tenant disimpan sebagai hash SHA-256.
+CREATE TABLE IF NOT EXISTS public.tenants (
+    id SERIAL PRIMARY KEY,
+    slug VARCHAR(32) NOT NULL UNIQUE,
+    name VARCHAR(255) NOT NULL,
+    schema_name VARCHAR(63) NOT NULL UNIQUE,
+    api_key_hash CHAR(64) NOT NULL UNIQUE,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
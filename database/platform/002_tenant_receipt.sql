@@ -0,0 +1,6 @@
+-- Identitas toko di struk setiap tenant. Nama toko memakai kolom name; receipt_footer kosong berarti
+-- footer bawaan RECEIPT_FOOTER.
+ALTER TABLE public.tenants ADD COLUMN IF NOT EXISTS store_address TEXT NOT NULL DEFAULT '';
+ALTER TABLE public.tenants ADD COLUMN IF NOT EXISTS store_phone VARCHAR(32) NOT NULL DEFAULT '';
+ALTER TABLE public.tenants ADD COLUMN IF NOT EXISTS store_npwp VARCHAR(32) NOT NULL DEFAULT '';
+ALTER TABLE public.tenants ADD COLUMN IF NOT EXISTS receipt_footer TEXT NOT NULL DEFAULT '';
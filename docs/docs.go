@@ -15,9 +15,96 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/api/audit": {
+            "get": {
+                "description": "Mengambil jejak perubahan data (produk, harga/stok outlet, transaksi, outlet, pengguna), terbaru lebih dulu. Setiap entri berisi pelaku, aksi, data sebelum/sesudah, field yang berubah, IP, user agent dan request ID. Khusus pemilik (owner)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "audit"
+                ],
+                "summary": "Get Audit Log",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter entitas (product, product_price, outlet_product, transaction, outlet, user, category, voucher, gift_card, customer)",
+                        "name": "entity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter ID entitas",
+                        "name": "entity_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter aksi (create, update, delete)",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter nama pelaku",
+                        "name": "actor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2026-01-01",
+                        "description": "Tanggal awal (Format: YYYY-MM-DD)",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2026-02-01",
+                        "description": "Tanggal akhir (Format: YYYY-MM-DD)",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Jumlah maksimal entri (default 100, maks 1000)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.AuditEntry"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid query",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Owner access required",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get audit log",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
         "/api/checkout": {
             "post": {
-                "description": "Melakukan checkout barang: format data yang harus diisi { items: [ { product_id, quantity } ]}",
+                "description": "Melakukan checkout barang: format data yang harus diisi { items: [ { product_id, quantity, unit, modifiers } ]}. unit opsional (default satuan dasar produk) dan harus satuan dasar atau salah satu units produk; quantity boleh pecahan untuk kg dan liter, contoh 0.75. modifiers berisi id modifier yang dipilih, sesuai batas min/max setiap kelompok modifier produk; harga modifier ditambahkan ke harga satuan dan modifier tersimpan di detail transaksi. Produk yang sama boleh muncul di beberapa item dengan modifier berbeda. Paket dan resep mengurangi stok komponennya, dan komponen yang terpakai dicatat di detail transaksi (components). Opsional: customer_id atau customer_phone untuk mencatat pelanggan dan poin, redeem_points untuk menukar poin sebagai potongan harga, voucher_code untuk voucher dan gift_card_code untuk membayar dengan kartu hadiah",
                 "consumes": [
                     "application/json"
                 ],
@@ -49,98 +136,97 @@ const docTemplate = `{
                     "400": {
                         "description": "Invalid request body",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
                         }
                     },
                     "500": {
                         "description": "Failed to create checkout",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/apperror.Response"
                         }
                     }
                 }
             }
         },
-        "/api/kategori": {
+        "/api/gambar/{key}": {
             "get": {
-                "description": "Mengambil semua data kategori produk (Challange (Optional))",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Mengambil file foto produk atau thumbnail-nya dari URL pada field image produk. Tidak memerlukan API key. Setiap unggahan mendapat URL baru, sehingga file boleh di-cache selamanya oleh browser dan CDN",
                 "produces": [
-                    "application/json"
+                    "image/jpeg",
+                    "image/png",
+                    "image/webp"
                 ],
                 "tags": [
-                    "category",
                     "produk"
                 ],
-                "summary": "Get All Categories",
+                "summary": "Get Image",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Key file, contoh produk/12/3f9a1c2b-md.jpg",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Categories"
-                            }
+                            "type": "file"
                         }
                     },
-                    "500": {
-                        "description": "Failed to get categories",
+                    "304": {
+                        "description": "Not Modified",
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "404": {
+                        "description": "File not found",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
                     }
                 }
             }
         },
-        "/api/produk": {
+        "/api/gift-card": {
             "get": {
-                "description": "Mengambil semua data produk. Terdapat opsi untuk mendapatkan detail kategori produk",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Mengambil semua kartu hadiah beserta sisa saldonya",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "produk"
-                ],
-                "summary": "Get All Products",
-                "parameters": [
-                    {
-                        "type": "boolean",
-                        "description": "Tampilkan Detail Kategori Produk",
-                        "name": "details",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Tampilkan Detail Kategori Produk Berdasarkan Pencarian Nama",
-                        "name": "name",
-                        "in": "query"
-                    }
+                    "gift-card"
                 ],
+                "summary": "Get All Gift Cards",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.Product"
+                                "$ref": "#/definitions/models.GiftCard"
                             }
                         }
                     },
                     "500": {
-                        "description": "Failed to get products",
+                        "description": "Failed to get gift cards",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/apperror.Response"
                         }
                     }
                 }
             },
             "post": {
-                "description": "Menambahkan data produk baru, data yang perlu diisi: { category_id, name, price, stock }",
+                "description": "Menerbitkan kartu hadiah bersaldo: { code, initial_balance, expires_at }. Kode dibuat otomatis jika kosong. Khusus pemilik (owner)",
                 "consumes": [
                     "application/json"
                 ],
@@ -148,17 +234,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "produk"
+                    "gift-card"
                 ],
-                "summary": "Create New Product",
+                "summary": "Issue Gift Card",
                 "parameters": [
                     {
-                        "description": "New Product Data",
-                        "name": "product",
+                        "description": "New Gift Card Data",
+                        "name": "gift_card",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Product"
+                            "$ref": "#/definitions/models.GiftCard"
                         }
                     }
                 ],
@@ -166,168 +252,143 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.Product"
+                            "$ref": "#/definitions/models.GiftCard"
                         }
                     },
                     "400": {
                         "description": "Invalid request body",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Owner access required",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Gift card code already exists",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
                         }
                     },
                     "500": {
-                        "description": "Failed to create product",
+                        "description": "Failed to create gift card",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/apperror.Response"
                         }
                     }
                 }
             }
         },
-        "/api/produk/{id}": {
+        "/api/gift-card/{code}": {
             "get": {
-                "description": "Mengambil data produk berdasarkan ID. Terdapat opsi untuk mendapatkan detail kategori produk",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Mengambil kartu hadiah berdasarkan kode, termasuk sisa saldo",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "produk"
+                    "gift-card"
                 ],
-                "summary": "Get Product by ID",
+                "summary": "Get Gift Card by Code",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Product ID",
-                        "name": "id",
+                        "type": "string",
+                        "description": "Gift Card Code",
+                        "name": "code",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "boolean",
-                        "default": false,
-                        "description": "Tampilkan Detail Kategori Produk",
-                        "name": "details",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Product"
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid product ID",
-                        "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.GiftCard"
                         }
                     },
                     "404": {
-                        "description": "Product not found",
+                        "description": "Gift card not found",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/apperror.Response"
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "Memperbarui data produk berdasarkan ID, data yang dapat diubah: { category_id, name, price, stock }",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/api/gift-card/{code}/riwayat": {
+            "get": {
+                "description": "Mengambil riwayat pemakaian kartu hadiah pada transaksi",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "produk"
+                    "gift-card"
                 ],
-                "summary": "Update Product by ID",
+                "summary": "Get Gift Card Redemption History",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Product ID",
-                        "name": "id",
+                        "type": "string",
+                        "description": "Gift Card Code",
+                        "name": "code",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Updated Product Data",
-                        "name": "product",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.Product"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Product"
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid request body",
-                        "schema": {
-                            "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Redemption"
+                            }
                         }
                     },
-                    "500": {
-                        "description": "Failed to update product",
+                    "404": {
+                        "description": "Gift card not found",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/apperror.Response"
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Menghapus data produk berdasarkan ID",
+            }
+        },
+        "/api/kategori": {
+            "get": {
+                "description": "Mengambil semua data kategori produk (Challange (Optional))",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
+                    "category",
                     "produk"
                 ],
-                "summary": "Delete Product by ID",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Product ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
+                "summary": "Get All Categories",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Categories"
                             }
                         }
                     },
-                    "400": {
-                        "description": "Invalid product ID",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
                     "500": {
-                        "description": "Failed to delete product",
+                        "description": "Failed to get categories",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/apperror.Response"
                         }
                     }
                 }
-            }
-        },
-        "/api/report": {
-            "get": {
-                "description": "Mengambil laporan data transaksi penjualan barang berdasarkan tanggal yang dipilih",
+            },
+            "post": {
+                "description": "Menambahkan kategori produk baru, data yang perlu diisi: { name }. Khusus pemilik (owner)",
                 "consumes": [
                     "application/json"
                 ],
@@ -335,22 +396,81 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "report"
+                    "category"
                 ],
-                "summary": "Get Transaction Report By Selected Date",
+                "summary": "Create Category",
                 "parameters": [
                     {
-                        "type": "string",
-                        "example": "2026-01-01",
-                        "description": "Tanggal awal (Format: YYYY-MM-DD)",
-                        "name": "start_date",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "example": "2026-02-01",
-                        "description": "Tanggal akhir (Format: YYYY-MM-DD)",
-                        "name": "end_date",
+                        "description": "New Category Data",
+                        "name": "category",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Categories"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Categories"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "API Key Required",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Owner access required",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to create category",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/keranjang": {
+            "get": {
+                "description": "Mengambil keranjang berdasarkan status. Tanpa filter, mengembalikan keranjang open dan held sehingga semua terminal bisa melihat keranjang yang ditahan",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "keranjang"
+                ],
+                "summary": "Get Carts",
+                "parameters": [
+                    {
+                        "enum": [
+                            "open",
+                            "held",
+                            "checked_out",
+                            "abandoned"
+                        ],
+                        "type": "string",
+                        "description": "Status keranjang",
+                        "name": "status",
                         "in": "query"
                     }
                 ],
@@ -360,93 +480,4925 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.Report"
+                                "$ref": "#/definitions/models.Cart"
                             }
                         }
                     },
+                    "400": {
+                        "description": "Invalid query",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
                     "500": {
-                        "description": "Failed to get report",
+                        "description": "Failed to get carts",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Membuat keranjang baru: { terminal, note, customer_id, reserve }. Jika reserve true, stok item di keranjang dipesan sementara dan kedaluwarsa otomatis; stok yang dipesan tidak bisa dijual lewat checkout langsung, sinkronisasi offline maupun keranjang lain",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "keranjang"
+                ],
+                "summary": "Create Cart",
+                "parameters": [
+                    {
+                        "description": "New Cart Data",
+                        "name": "cart",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Cart"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Cart"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to create cart",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/keranjang/{id}": {
+            "get": {
+                "description": "Mengambil keranjang beserta item, harga terkini dan stok tersedia",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "keranjang"
+                ],
+                "summary": "Get Cart by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Cart ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Cart"
+                        }
+                    },
+                    "404": {
+                        "description": "Cart not found",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/keranjang/{id}/abandon": {
+            "post": {
+                "description": "Membatalkan keranjang dan melepas reservasi stoknya",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "keranjang"
+                ],
+                "summary": "Abandon Cart",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Cart ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Cart"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update cart",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/keranjang/{id}/checkout": {
+            "post": {
+                "description": "Mengubah keranjang menjadi transaksi. Opsional: { customer_phone, redeem_points, voucher_code, gift_card_code }",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "keranjang"
+                ],
+                "summary": "Checkout Cart",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Cart ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Checkout Options",
+                        "name": "options",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.CartCheckoutRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Transaction"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to checkout cart",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/keranjang/{id}/hold": {
+            "post": {
+                "description": "Menahan (parkir) keranjang open agar kasir bisa melayani pelanggan berikutnya",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "keranjang"
+                ],
+                "summary": "Hold Cart",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Cart ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Cart"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update cart",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/keranjang/{id}/items": {
+            "post": {
+                "description": "Menambah produk ke keranjang: { product_id, quantity, unit, modifiers }. unit kosong berarti satuan dasar produk. modifiers berisi id modifier yang dipilih dan diperiksa terhadap batas min/max setiap kelompok modifier produk. Jumlah ditambahkan ke baris produk dengan satuan dan modifier yang sama, satuan atau modifier lain menjadi baris baru. Stok divalidasi langsung",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "keranjang"
+                ],
+                "summary": "Add Cart Item",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Cart ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Cart Item",
+                        "name": "item",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CheckoutItem"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Cart"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update cart",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
                         }
                     }
                 }
             }
         },
-        "/api/report/hari-ini": {
-            "get": {
-                "description": "Mengambil laporan data transaksi penjualan barang khusus hari ini",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "report"
-                ],
-                "summary": "Get Today's Transaction Report",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Report"
-                            }
-                        }
-                    },
-                    "500": {
-                        "description": "Failed to get report",
-                        "schema": {
-                            "type": "string"
-                        }
+        "/api/keranjang/{id}/items/{item_id}": {
+            "put": {
+                "description": "Mengganti jumlah baris keranjang (id item dari GET keranjang): { quantity, unit, modifiers }. unit kosong mempertahankan satuan item dan modifiers yang tidak dikirim mempertahankan modifier item ([] menghapus semua modifier). Jumlah 0 menghapus item",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "keranjang"
+                ],
+                "summary": "Update Cart Item",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Cart ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Cart Item ID",
+                        "name": "item_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Cart Item",
+                        "name": "item",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CheckoutItem"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Cart"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update cart",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Menghapus baris dari keranjang",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "keranjang"
+                ],
+                "summary": "Remove Cart Item",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Cart ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Cart Item ID",
+                        "name": "item_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Cart"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update cart",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/keranjang/{id}/resume": {
+            "post": {
+                "description": "Melanjutkan keranjang yang ditahan, dari terminal mana pun",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "keranjang"
+                ],
+                "summary": "Resume Cart",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Cart ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Cart"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update cart",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/outlet": {
+            "get": {
+                "description": "Mengambil semua outlet/cabang",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "outlet"
+                ],
+                "summary": "Get All Outlets",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Outlet"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get outlets",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Menambahkan outlet baru: { code, name, address, phone }. Khusus pemilik (owner)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "outlet"
+                ],
+                "summary": "Create Outlet",
+                "parameters": [
+                    {
+                        "description": "New Outlet Data",
+                        "name": "outlet",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Outlet"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Outlet"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Owner access required",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to create outlet",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/outlet/{id}": {
+            "get": {
+                "description": "Mengambil data outlet berdasarkan ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "outlet"
+                ],
+                "summary": "Get Outlet by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Outlet ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Outlet"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid outlet ID",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Outlet not found",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Memperbarui data outlet: { code, name, address, phone }. Khusus pemilik (owner)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "outlet"
+                ],
+                "summary": "Update Outlet by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Outlet ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated Outlet Data",
+                        "name": "outlet",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Outlet"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Outlet"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Owner access required",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update outlet",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/outlet/{id}/produk": {
+            "get": {
+                "description": "Mengambil stok dan harga seluruh produk katalog di outlet. price_override null berarti outlet memakai harga katalog",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "outlet"
+                ],
+                "summary": "Get Outlet Products",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Outlet ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.OutletProduct"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Outlet not found",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/outlet/{id}/produk/{product_id}": {
+            "put": {
+                "description": "Mengatur stok dan harga khusus produk di outlet: { stock, price_override }. stock kosong mempertahankan stok, price_override null kembali ke harga katalog. Khusus pemilik (owner)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "outlet"
+                ],
+                "summary": "Set Outlet Product Stock and Price",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Outlet ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "product_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Stok dan harga khusus",
+                        "name": "product",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.OutletProductUpdate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.OutletProduct"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Owner access required",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update outlet product",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/pelanggan": {
+            "get": {
+                "description": "Mengambil semua data pelanggan. Terdapat opsi pencarian berdasarkan nama, nomor telepon atau email",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pelanggan"
+                ],
+                "summary": "Get All Customers",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cari nama, telepon atau email",
+                        "name": "search",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Customer"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get customers",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Menambahkan pelanggan baru, data yang perlu diisi: { name, phone, email }. Nomor telepon disimpan dalam format 62xxxxxxxx",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pelanggan"
+                ],
+                "summary": "Create New Customer",
+                "parameters": [
+                    {
+                        "description": "New Customer Data",
+                        "name": "customer",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Customer"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Customer"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Phone already used by another customer",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to create customer",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/pelanggan/{id}": {
+            "get": {
+                "description": "Mengambil data pelanggan berdasarkan ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pelanggan"
+                ],
+                "summary": "Get Customer by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Customer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Customer"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid customer ID",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Customer not found",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Memperbarui data pelanggan berdasarkan ID, data yang dapat diubah: { name, phone, email }",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pelanggan"
+                ],
+                "summary": "Update Customer by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Customer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated Customer Data",
+                        "name": "customer",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Customer"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Customer"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Phone already used by another customer",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update customer",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/pelanggan/{id}/poin": {
+            "get": {
+                "description": "Mengambil saldo poin pelanggan beserta buku poin (earn, redeem, expire). Poin yang kedaluwarsa dicatat saat saldo dihitung",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pelanggan"
+                ],
+                "summary": "Get Customer Points",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Customer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.LoyaltyBalance"
+                        }
+                    },
+                    "404": {
+                        "description": "Customer not found",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/pelanggan/{id}/transaksi": {
+            "get": {
+                "description": "Mengambil riwayat belanja pelanggan, transaksi terbaru lebih dulu",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pelanggan"
+                ],
+                "summary": "Get Customer Purchase History",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Customer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Transaction"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Customer not found",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/pengguna": {
+            "get": {
+                "description": "Mengambil semua pengguna beserta outlet yang ditugaskan. Khusus pemilik (owner)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pengguna"
+                ],
+                "summary": "Get All Users",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.User"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Owner access required",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get users",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Menambahkan pengguna: { name, role, outlet_ids }. role owner atau cashier (default cashier). API key hanya ditampilkan sekali pada respons ini. Khusus pemilik (owner)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pengguna"
+                ],
+                "summary": "Create User",
+                "parameters": [
+                    {
+                        "description": "New User Data",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to create user",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/pengguna/{id}": {
+            "get": {
+                "description": "Mengambil data pengguna berdasarkan ID. Khusus pemilik (owner)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pengguna"
+                ],
+                "summary": "Get User by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Memperbarui nama, peran dan outlet pengguna: { name, role, outlet_ids }. Khusus pemilik (owner)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pengguna"
+                ],
+                "summary": "Update User by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated User Data",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update user",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Menghapus pengguna, API key-nya langsung tidak berlaku. Khusus pemilik (owner)",
+                "tags": [
+                    "pengguna"
+                ],
+                "summary": "Delete User by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to delete user",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/pengguna/{id}/api-key": {
+            "post": {
+                "description": "Membuat API key baru untuk pengguna, key lama langsung tidak berlaku. Key baru hanya ditampilkan sekali. Khusus pemilik (owner)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pengguna"
+                ],
+                "summary": "Rotate User API Key",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to rotate API key",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/platform/tenant": {
+            "get": {
+                "description": "Mengambil semua tenant pada mode multi-tenant. Memerlukan PLATFORM_API_KEY pada header X-Api-Key",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "platform"
+                ],
+                "summary": "Get All Tenants",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Tenant"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API Key",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get tenants",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Mendaftarkan tenant baru: { slug, name }, serta address, phone, npwp dan receipt_footer opsional untuk struk (name menjadi nama toko di struk). Schema database tenant dibuat dan dimigrasi, lalu API key pemilik tenant ditampilkan sekali pada respons ini. Tenant diakses lewat subdomain {slug}.BASE_DOMAIN, header X-Tenant-ID, atau API key pemilik tenant",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "platform"
+                ],
+                "summary": "Provision Tenant",
+                "parameters": [
+                    {
+                        "description": "New Tenant Data",
+                        "name": "tenant",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Tenant"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Tenant"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to provision tenant",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/platform/tenant/{id}": {
+            "get": {
+                "description": "Mengambil data tenant berdasarkan ID. Memerlukan PLATFORM_API_KEY",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "platform"
+                ],
+                "summary": "Get Tenant by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tenant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Tenant"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid tenant ID",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Tenant not found",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Memperbarui nama, status aktif dan identitas struk tenant: { name, active, address, phone, npwp, receipt_footer }, field yang tidak dikirim tidak diubah. Tenant nonaktif ditolak dengan 403. Memerlukan PLATFORM_API_KEY",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "platform"
+                ],
+                "summary": "Update Tenant by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tenant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated Tenant Data",
+                        "name": "tenant",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.TenantUpdate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Tenant"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Tenant not found",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/platform/tenant/{id}/api-key": {
+            "post": {
+                "description": "Membuat API key pemilik tenant yang baru, key lama langsung tidak berlaku. Key baru hanya ditampilkan sekali. Memerlukan PLATFORM_API_KEY",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "platform"
+                ],
+                "summary": "Rotate Tenant API Key",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tenant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Tenant"
+                        }
+                    },
+                    "404": {
+                        "description": "Tenant not found",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/produk": {
+            "get": {
+                "description": "Mengambil semua data produk. Terdapat opsi untuk mendapatkan detail kategori produk",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "produk"
+                ],
+                "summary": "Get All Products",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Tampilkan Detail Kategori Produk",
+                        "name": "details",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tampilkan Detail Kategori Produk Berdasarkan Pencarian Nama",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID outlet untuk stok dan harga (default outlet bawaan)",
+                        "name": "outlet_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Product"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get products",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Menambahkan data produk baru, data yang perlu diisi: { category_id, name, price, stock }, sku opsional. Produk masuk ke katalog bersama, price adalah harga katalog dan stock dicatat di outlet yang dipilih (X-Outlet-ID). unit adalah satuan dasar (pcs, kg, liter atau box, default pcs); price dan stock dalam satuan dasar, pecahan hanya untuk kg dan liter. units opsional berisi satuan jual lain, contoh [{ \"unit\": \"box\", \"factor\": 12, \"price\": 100000 }]; tanpa price harganya price dikali factor. type opsional: standard (default), bundle (paket dengan harga sendiri) atau recipe (resep); bundle dan recipe wajib mengisi components, contoh [{ \"product_id\": 3, \"quantity\": 0.25 }], dengan quantity dalam satuan dasar komponen per satu satuan dasar produk. Komponen harus produk standard. Stok paket dan resep tidak diisi, melainkan dihitung dari stok komponen dan stok komponen berkurang saat checkout. modifier_groups opsional berisi kelompok pilihan tambahan, contoh [{ \"name\": \"Susu\", \"min\": 0, \"max\": 1, \"modifiers\": [{ \"name\": \"Oat\", \"price\": 5000 }] }]; pembeli memilih min sampai max modifier per kelompok dan price ditambahkan ke harga per satuan jual. Khusus pemilik (owner)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "produk"
+                ],
+                "summary": "Create New Product",
+                "parameters": [
+                    {
+                        "description": "New Product Data",
+                        "name": "product",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Product"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Product"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "API Key Required",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Owner access required",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to create product",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/produk/impor": {
+            "post": {
+                "description": "Impor produk massal dari CSV (pemisah koma atau titik koma) atau XLSX (sheet pertama). Baris pertama berisi judul kolom: sku, name/nama, price/harga wajib; stock/stok (dalam satuan dasar produk, titik atau koma sebagai pemisah desimal tanpa pemisah ribuan) dan category/kategori opsional. Judul lain bisa dipetakan lewat columns, contoh {\"Kode Barang\":\"sku\",\"Harga Jual\":\"price\"}. Produk dengan SKU yang sama diperbarui, selain itu dibuat baru; kategori dicari berdasarkan nama dan dibuat jika belum ada. Stok dicatat di outlet yang dipilih (X-Outlet-ID). dry_run=true hanya memvalidasi dan melaporkan per baris tanpa menyimpan. Impor disimpan per 100 baris dalam satu transaksi. Khusus pemilik (owner)",
+                "consumes": [
+                    "multipart/form-data",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "produk"
+                ],
+                "summary": "Import Products",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "File CSV atau XLSX (multipart)",
+                        "name": "file",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pemetaan judul kolom ke field dalam JSON (untuk body langsung pakai query columns)",
+                        "name": "columns",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Validasi saja tanpa menyimpan",
+                        "name": "dry_run",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProductImportReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid import file",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Owner access required",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/produk/katalog": {
+            "get": {
+                "description": "Mengekspor katalog (kategori dan produk aktif) dengan harga dan stok outlet yang dipilih (X-Outlet-ID). Format JSON berisi kategori dan produk; CSV atau XLSX berisi satu baris per produk dengan judul kolom yang sama dengan impor produk, sehingga bisa diimpor kembali di server lain",
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "produk"
+                ],
+                "summary": "Export Catalog",
+                "parameters": [
+                    {
+                        "enum": [
+                            "json",
+                            "csv",
+                            "xlsx"
+                        ],
+                        "type": "string",
+                        "default": "json",
+                        "description": "Format keluaran",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "id",
+                            "en"
+                        ],
+                        "type": "string",
+                        "default": "id",
+                        "description": "Bahasa judul kolom",
+                        "name": "lang",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Catalog"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to export catalog",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/produk/massal": {
+            "post": {
+                "description": "Mengubah banyak produk sekaligus dalam satu transaksi. target memilih produk berdasarkan ids, category_id (0 untuk tanpa kategori) dan/atau name (kriteria digabung dengan AND), atau all: true untuk semua produk. operation.type: set_price, adjust_price_percent (contoh value 5 untuk +5%, dibulatkan ke rupiah), adjust_price_amount, set_stock, adjust_stock (paket dan resep dilewati karena stoknya mengikuti komponen) atau set_category. Harga yang diubah adalah harga katalog, stok adalah stok di outlet yang dipilih (X-Outlet-ID). Setiap perubahan dicatat di riwayat harga, buku stok dan audit log. preview=true menampilkan nilai sebelum dan sesudah tanpa menyimpan. Khusus pemilik (owner)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "produk"
+                ],
+                "summary": "Bulk Update Products",
+                "parameters": [
+                    {
+                        "description": "Target dan operasi",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.BulkProductRequest"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Hitung perubahan tanpa menyimpan",
+                        "name": "preview",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.BulkProductResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Owner access required",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/produk/terhapus": {
+            "get": {
+                "description": "Mengambil produk yang sudah dihapus (soft delete), terbaru lebih dulu. Stok dan harga dari outlet yang dipilih (X-Outlet-ID). Khusus pemilik (owner)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "produk"
+                ],
+                "summary": "Get Deleted Products",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Product"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Owner access required",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get deleted products",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/produk/{id}": {
+            "get": {
+                "description": "Mengambil data produk berdasarkan ID. Terdapat opsi untuk mendapatkan detail kategori produk. Respons berisi header ETag untuk If-Match pada PUT/DELETE; If-None-Match dengan ETag yang sama menghasilkan 304",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "produk"
+                ],
+                "summary": "Get Product by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Tampilkan Detail Kategori Produk",
+                        "name": "details",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag dari respons sebelumnya",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Product"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid product ID",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Product not found",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Memperbarui data produk berdasarkan ID, data yang dapat diubah: { sku, category_id, name, price, stock, unit, units, type, components, modifier_groups }. unit atau type kosong serta units dan modifier_groups yang tidak dikirim tidak diubah; components yang tidak dikirim tetap untuk paket dan resep. Kelompok dan modifier yang membawa id diperbarui dengan id tetap, yang tanpa id ditambahkan dan yang tidak dikirim dihapus. stock diabaikan untuk paket dan resep. price mengubah harga katalog, stock mengubah stok di outlet yang dipilih (X-Outlet-ID). Wajib mengirim If-Match berisi ETag dari GET /api/produk/{id}; jika produk sudah diubah pihak lain, respons 412. Khusus pemilik (owner)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "produk"
+                ],
+                "summary": "Update Product by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag produk yang sedang diedit",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated Product Data",
+                        "name": "product",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Product"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Product"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Owner access required",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "412": {
+                        "description": "Product has been modified",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match header required",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update product",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Menghapus produk berdasarkan ID (soft delete). Produk hilang dari katalog dan checkout, tetapi tetap tercatat di transaksi dan laporan lama, dan bisa dipulihkan lewat /api/produk/{id}/pulihkan. Wajib mengirim If-Match berisi ETag produk. Khusus pemilik (owner)",
+                "tags": [
+                    "produk"
+                ],
+                "summary": "Delete Product by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag produk yang akan dihapus",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid product ID",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Owner access required",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "412": {
+                        "description": "Product has been modified",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match header required",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to delete product",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Mengubah sebagian data produk: hanya field yang dikirim yang berubah. Content-Type application/merge-patch+json (atau application/json) untuk JSON Merge Patch, contoh { \"price\": 12000 }, dan application/json-patch+json untuk JSON Patch, contoh [{ \"op\": \"replace\", \"path\": \"/stock\", \"value\": 5 }]. Field yang bisa diubah: sku, name, price, stock, unit, units, type, components, modifier_groups, category_id (null untuk menghapus SKU, units, modifier_groups atau kategori). If-Match opsional; jika dikirim dan produk sudah diubah pihak lain, respons 412. Khusus pemilik (owner)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "produk"
+                ],
+                "summary": "Patch Product by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag produk yang sedang diedit",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Merge patch atau daftar operasi JSON Patch",
+                        "name": "patch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Product"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid patch",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Owner access required",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Product not found",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Patch test failed",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "412": {
+                        "description": "Product has been modified",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Content-Type",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/produk/{id}/gambar": {
+            "post": {
+                "description": "Mengunggah foto produk lewat multipart (field image) atau langsung sebagai body. Hanya JPEG, PNG atau WebP, maksimal 5 MB; jenis file diperiksa dari isinya. Thumbnail sm (160px), md (320px) dan lg (640px) dibuat otomatis. Foto lama diganti. Khusus pemilik (owner)",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "produk"
+                ],
+                "summary": "Upload Product Image",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Foto produk",
+                        "name": "image",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Product"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid image file",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Owner access required",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Product not found",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "413": {
+                        "description": "Image too large",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported image type",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Menghapus foto produk beserta thumbnail-nya. Khusus pemilik (owner)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "produk"
+                ],
+                "summary": "Delete Product Image",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Product"
+                        }
+                    },
+                    "403": {
+                        "description": "Owner access required",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Product not found",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/produk/{id}/harga": {
+            "get": {
+                "description": "Mengambil riwayat harga katalog produk beserta harga terjadwal (applied_at kosong), yang berlaku paling akhir lebih dulu",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "produk"
+                ],
+                "summary": "Get Product Price History",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ProductPrice"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Product not found",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Menjadwalkan harga katalog baru: { price, effective_from, note }. effective_from berformat YYYY-MM-DD (awal hari di zona waktu toko) atau RFC3339 dan harus di masa depan. Harga berlaku otomatis pada waktunya. Khusus pemilik (owner)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "produk"
+                ],
+                "summary": "Schedule Product Price",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Scheduled Price",
+                        "name": "price",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SchedulePriceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProductPrice"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Owner access required",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Product not found",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/produk/{id}/harga/{price_id}": {
+            "delete": {
+                "description": "Membatalkan harga terjadwal yang belum berlaku. Riwayat harga yang sudah berlaku tidak bisa dihapus. Khusus pemilik (owner)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "produk"
+                ],
+                "summary": "Cancel Scheduled Price",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Scheduled Price ID",
+                        "name": "price_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Owner access required",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Scheduled price not found or already applied",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/produk/{id}/pulihkan": {
+            "post": {
+                "description": "Memulihkan produk yang sudah dihapus sehingga kembali muncul di katalog dan bisa dijual. Khusus pemilik (owner)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "produk"
+                ],
+                "summary": "Restore Deleted Product",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Product"
+                        }
+                    },
+                    "403": {
+                        "description": "Owner access required",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Product not found",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Product is not deleted",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/report": {
+            "get": {
+                "description": "Mengambil laporan data transaksi penjualan barang berdasarkan tanggal yang dipilih",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "report"
+                ],
+                "summary": "Get Transaction Report By Selected Date",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "2026-01-01",
+                        "description": "Tanggal awal (Format: YYYY-MM-DD)",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2026-02-01",
+                        "description": "Tanggal akhir (Format: YYYY-MM-DD)",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv",
+                            "xlsx"
+                        ],
+                        "type": "string",
+                        "default": "json",
+                        "description": "Format keluaran",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "id",
+                            "en"
+                        ],
+                        "type": "string",
+                        "default": "id",
+                        "description": "Bahasa judul kolom ekspor",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID outlet (default outlet bawaan)",
+                        "name": "outlet_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Report"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get report",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/report/compare": {
+            "get": {
+                "description": "Membandingkan laporan pada rentang tanggal dengan periode pembanding (periode sebelumnya dengan panjang sama, minggu lalu, bulan lalu atau tahun lalu). Berisi selisih absolut dan persentase untuk pendapatan, jumlah transaksi, rata-rata belanja dan jumlah terjual per produk",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "report"
+                ],
+                "summary": "Compare Report With Previous Period",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "2026-01-01",
+                        "description": "Tanggal awal (Format: YYYY-MM-DD)",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2026-02-01",
+                        "description": "Tanggal akhir (Format: YYYY-MM-DD)",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "previous",
+                            "last_week",
+                            "last_month",
+                            "last_year"
+                        ],
+                        "type": "string",
+                        "default": "previous",
+                        "description": "Periode pembanding",
+                        "name": "compare",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ReportComparison"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid query",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get report",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/report/hari-ini": {
+            "get": {
+                "description": "Mengambil laporan data transaksi penjualan barang khusus hari ini",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "report"
+                ],
+                "summary": "Get Today's Transaction Report",
+                "parameters": [
+                    {
+                        "enum": [
+                            "json",
+                            "csv",
+                            "xlsx"
+                        ],
+                        "type": "string",
+                        "default": "json",
+                        "description": "Format keluaran",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "id",
+                            "en"
+                        ],
+                        "type": "string",
+                        "default": "id",
+                        "description": "Bahasa judul kolom ekspor",
+                        "name": "lang",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Report"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get report",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/report/konsolidasi": {
+            "get": {
+                "description": "Laporan gabungan semua outlet beserta rincian pendapatan per outlet dan 10 produk terlaris. Khusus pemilik (owner)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "report"
+                ],
+                "summary": "Get Consolidated Report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "2026-01-01",
+                        "description": "Tanggal awal (Format: YYYY-MM-DD)",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2026-02-01",
+                        "description": "Tanggal akhir (Format: YYYY-MM-DD)",
+                        "name": "end_date",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ConsolidatedReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid query",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Owner access required",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get report",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/report/modifier": {
+            "get": {
+                "description": "Menghitung popularitas modifier (misalnya extra shot atau susu oat): berapa item transaksi yang memilihnya, jumlah kuantitas item tersebut dan pendapatan dari harga modifier. Modifier dikelompokkan berdasarkan nama kelompok dan nama saat transaksi",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "report"
+                ],
+                "summary": "Get Modifier Popularity Report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "2026-01-01",
+                        "description": "Tanggal awal (Format: YYYY-MM-DD)",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2026-02-01",
+                        "description": "Tanggal akhir (Format: YYYY-MM-DD)",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv",
+                            "xlsx"
+                        ],
+                        "type": "string",
+                        "default": "json",
+                        "description": "Format keluaran",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "id",
+                            "en"
+                        ],
+                        "type": "string",
+                        "default": "id",
+                        "description": "Bahasa judul kolom ekspor",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID outlet (default outlet bawaan)",
+                        "name": "outlet_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ModifierReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid query",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get report",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/report/produk": {
+            "get": {
+                "description": "Mengambil jumlah terjual per produk (dalam satuan dasar produk) beserta jumlah komponen yang terpakai oleh paket dan resep yang terjual. Pada ekspor, kolom Jenis berisi \"terjual\" atau \"komponen\"",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "report"
+                ],
+                "summary": "Get Product and Component Report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "2026-01-01",
+                        "description": "Tanggal awal (Format: YYYY-MM-DD)",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2026-02-01",
+                        "description": "Tanggal akhir (Format: YYYY-MM-DD)",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv",
+                            "xlsx"
+                        ],
+                        "type": "string",
+                        "default": "json",
+                        "description": "Format keluaran",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "id",
+                            "en"
+                        ],
+                        "type": "string",
+                        "default": "id",
+                        "description": "Bahasa judul kolom ekspor",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID outlet (default outlet bawaan)",
+                        "name": "outlet_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProductReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid query",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get report",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/report/timeseries": {
+            "get": {
+                "description": "Mengambil deret waktu pendapatan, jumlah transaksi dan jumlah barang terjual per jam, hari, minggu atau bulan. Hari dihitung berdasarkan zona waktu toko (TIMEZONE), bucket tanpa transaksi bernilai nol",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "report"
+                ],
+                "summary": "Get Sales Time Series",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "2026-01-01",
+                        "description": "Tanggal awal (Format: YYYY-MM-DD)",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2026-02-01",
+                        "description": "Tanggal akhir (Format: YYYY-MM-DD)",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "hour",
+                            "day",
+                            "week",
+                            "month"
+                        ],
+                        "type": "string",
+                        "default": "day",
+                        "description": "Interval bucket",
+                        "name": "interval",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv",
+                            "xlsx"
+                        ],
+                        "type": "string",
+                        "default": "json",
+                        "description": "Format keluaran",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "id",
+                            "en"
+                        ],
+                        "type": "string",
+                        "default": "id",
+                        "description": "Bahasa judul kolom ekspor",
+                        "name": "lang",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TimeSeriesReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid query",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get report",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/stok/mutasi": {
+            "get": {
+                "description": "Mengambil buku stok outlet yang dipilih (X-Outlet-ID): penjualan, penyesuaian, transfer keluar dan masuk. Quantity positif berarti stok masuk",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stok"
+                ],
+                "summary": "Get Stock Ledger",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Filter produk",
+                        "name": "product_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2026-01-01",
+                        "description": "Tanggal awal (Format: YYYY-MM-DD)",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2026-02-01",
+                        "description": "Tanggal akhir (Format: YYYY-MM-DD)",
+                        "name": "end_date",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.StockMovement"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid query",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get stock ledger",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/sync/changes": {
+            "get": {
+                "description": "Mengambil perubahan produk, harga dan kategori sejak cursor, termasuk data yang dihapus. Tanpa cursor mengembalikan seluruh katalog; ulangi dengan cursor dari respons selama has_more bernilai true",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sync"
+                ],
+                "summary": "Get Catalogue Changes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cursor dari respons sebelumnya",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Jumlah perubahan maksimal (default 500, maks 2000)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SyncChanges"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid query",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get changes",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/sync/transaksi": {
+            "post": {
+                "description": "Mengunggah transaksi yang dibuat terminal saat offline. Setiap transaksi wajib memiliki client_uuid; transaksi diproses berurutan dan idempoten, created_at dari terminal dipertahankan dan dipakai untuk harga serta masa berlaku voucher dan kartu hadiah; created_at di masa depan atau lebih dari 30 hari lalu ditolak. Hasil per transaksi: applied, duplicate atau rejected",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sync"
+                ],
+                "summary": "Sync Offline Transactions",
+                "parameters": [
+                    {
+                        "description": "Batch transaksi offline",
+                        "name": "batch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SyncBatchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SyncBatchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to sync transactions",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/transaksi": {
+            "get": {
+                "description": "Mengambil daftar transaksi beserta detailnya berdasarkan rentang tanggal (zona waktu toko). Dapat diekspor ke CSV atau XLSX melalui parameter format atau header Accept, satu baris per detail transaksi",
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "transaksi"
+                ],
+                "summary": "List Transactions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "2026-01-01",
+                        "description": "Tanggal awal (Format: YYYY-MM-DD)",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2026-02-01",
+                        "description": "Tanggal akhir (Format: YYYY-MM-DD)",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv",
+                            "xlsx"
+                        ],
+                        "type": "string",
+                        "default": "json",
+                        "description": "Format keluaran",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "id",
+                            "en"
+                        ],
+                        "type": "string",
+                        "default": "id",
+                        "description": "Bahasa judul kolom ekspor",
+                        "name": "lang",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Transaction"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid query",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get transactions",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/transaksi/{id}": {
+            "get": {
+                "description": "Mengambil data transaksi beserta detailnya berdasarkan ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transaksi"
+                ],
+                "summary": "Get Transaction by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Transaction ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Transaction"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid transaction ID",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Transaction not found",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/transaksi/{id}/receipt": {
+            "get": {
+                "description": "Mencetak struk transaksi sebagai teks lebar tetap (kertas 58mm/80mm), byte stream ESC/POS untuk printer thermal (dengan potong kertas dan buka laci kas) atau PDF. Cetakan kedua dan seterusnya ditandai sebagai salinan",
+                "produces": [
+                    "text/plain",
+                    "application/octet-stream",
+                    "application/pdf"
+                ],
+                "tags": [
+                    "transaksi"
+                ],
+                "summary": "Print Transaction Receipt",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Transaction ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "text",
+                            "escpos",
+                            "pdf"
+                        ],
+                        "type": "string",
+                        "default": "text",
+                        "description": "Format struk",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            58,
+                            80
+                        ],
+                        "type": "integer",
+                        "default": 58,
+                        "description": "Lebar kertas (mm)",
+                        "name": "paper",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Pratinjau, tidak dihitung sebagai cetak",
+                        "name": "preview",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": true,
+                        "description": "Buka laci kas (hanya ESC/POS, cetakan pertama)",
+                        "name": "drawer",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Receipt",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid receipt option",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Transaction not found",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/transfer": {
+            "get": {
+                "description": "Mengambil transfer stok yang keluar dari atau masuk ke outlet yang dipilih (X-Outlet-ID)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transfer"
+                ],
+                "summary": "Get Stock Transfers",
+                "parameters": [
+                    {
+                        "enum": [
+                            "requested",
+                            "shipped",
+                            "received",
+                            "received_with_discrepancy",
+                            "cancelled"
+                        ],
+                        "type": "string",
+                        "description": "Status transfer",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.StockTransfer"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid query",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get transfers",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Membuat permintaan transfer stok: { from_outlet_id, to_outlet_id, note, lines: [{ product_id, quantity }] }. Stok belum berubah sampai transfer dikirim",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transfer"
+                ],
+                "summary": "Create Stock Transfer",
+                "parameters": [
+                    {
+                        "description": "New Transfer Data",
+                        "name": "transfer",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.StockTransfer"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.StockTransfer"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to create transfer",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/transfer/{id}": {
+            "get": {
+                "description": "Mengambil dokumen transfer beserta jumlah diminta, dikirim, diterima dan selisihnya",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transfer"
+                ],
+                "summary": "Get Stock Transfer by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Transfer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.StockTransfer"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid transfer ID",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Transfer not found",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/transfer/{id}/batal": {
+            "post": {
+                "description": "Membatalkan transfer yang belum dikirim",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transfer"
+                ],
+                "summary": "Cancel Stock Transfer",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Transfer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.StockTransfer"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to cancel transfer",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/transfer/{id}/kirim": {
+            "post": {
+                "description": "Mengirim transfer dari outlet asal. Stok asal berkurang dan tercatat di buku stok; barang terlihat sebagai in_transit di outlet tujuan. Opsional: { lines: [{ product_id, quantity }] } untuk jumlah kirim yang berbeda dari permintaan",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transfer"
+                ],
+                "summary": "Ship Stock Transfer",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Transfer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Jumlah dikirim",
+                        "name": "lines",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.TransferQuantities"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.StockTransfer"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to ship transfer",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/transfer/{id}/terima": {
+            "post": {
+                "description": "Menerima transfer di outlet tujuan. Stok tujuan bertambah dan tercatat di buku stok. Opsional: { lines: [{ product_id, quantity }] } untuk jumlah yang benar-benar diterima; selisih membuat status received_with_discrepancy",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transfer"
+                ],
+                "summary": "Receive Stock Transfer",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Transfer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Jumlah diterima",
+                        "name": "lines",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.TransferQuantities"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.StockTransfer"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to receive transfer",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/voucher": {
+            "get": {
+                "description": "Mengambil semua voucher beserta jumlah pemakaiannya",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "voucher"
+                ],
+                "summary": "Get All Vouchers",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Voucher"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get vouchers",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Menerbitkan voucher: { code, type (fixed/percent), value, max_discount, min_spend, usage_limit, product_ids, category_ids, starts_at, expires_at, active }. Kode dibuat otomatis jika kosong. Khusus pemilik (owner)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "voucher"
+                ],
+                "summary": "Create New Voucher",
+                "parameters": [
+                    {
+                        "description": "New Voucher Data",
+                        "name": "voucher",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Voucher"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Voucher"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Owner access required",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Voucher code already exists",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to create voucher",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/voucher/{code}": {
+            "get": {
+                "description": "Mengambil voucher berdasarkan kode",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "voucher"
+                ],
+                "summary": "Get Voucher by Code",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Voucher Code",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Voucher"
+                        }
+                    },
+                    "404": {
+                        "description": "Voucher not found",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/voucher/{code}/riwayat": {
+            "get": {
+                "description": "Mengambil riwayat pemakaian voucher pada transaksi",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "voucher"
+                ],
+                "summary": "Get Voucher Redemption History",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Voucher Code",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Redemption"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Voucher not found",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "apperror.Code": {
+            "type": "string",
+            "enum": [
+                "bad_request",
+                "unauthorized",
+                "forbidden",
+                "not_found",
+                "method_not_allowed",
+                "conflict",
+                "precondition_failed",
+                "unsupported_media_type",
+                "payload_too_large",
+                "validation_failed",
+                "precondition_required",
+                "insufficient_stock",
+                "internal_error"
+            ],
+            "x-enum-varnames": [
+                "CodeBadRequest",
+                "CodeUnauthorized",
+                "CodeForbidden",
+                "CodeNotFound",
+                "CodeMethodNotAllowed",
+                "CodeConflict",
+                "CodePreconditionFailed",
+                "CodeUnsupportedMediaType",
+                "CodePayloadTooLarge",
+                "CodeValidation",
+                "CodePreconditionRequired",
+                "CodeInsufficientStock",
+                "CodeInternal"
+            ]
+        },
+        "apperror.Response": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/apperror.Code"
+                        }
+                    ],
+                    "example": "not_found"
+                },
+                "details": {},
+                "message": {
+                    "type": "string",
+                    "example": "product not found"
+                },
+                "request_id": {
+                    "type": "string",
+                    "example": "9f86d081884c7d65"
+                }
+            }
+        },
+        "models.AuditChange": {
+            "type": "object",
+            "properties": {
+                "from": {},
+                "to": {}
+            }
+        },
+        "models.AuditEntry": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "actor": {
+                    "type": "string"
+                },
+                "after": {
+                    "type": "object"
+                },
+                "before": {
+                    "type": "object"
+                },
+                "changes": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/models.AuditChange"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "entity": {
+                    "type": "string"
+                },
+                "entity_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "ip": {
+                    "type": "string"
+                },
+                "request_id": {
+                    "type": "string"
+                },
+                "user_agent": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.BulkOperation": {
+            "type": "object",
+            "properties": {
+                "type": {
+                    "type": "string",
+                    "example": "adjust_price_percent"
+                },
+                "value": {
+                    "type": "number",
+                    "example": 5
+                }
+            }
+        },
+        "models.BulkProductChange": {
+            "type": "object",
+            "properties": {
+                "after": {
+                    "type": "number",
+                    "example": 10500
+                },
+                "before": {
+                    "type": "number",
+                    "example": 10000
+                },
+                "field": {
+                    "type": "string",
+                    "example": "price"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.BulkProductRequest": {
+            "type": "object",
+            "properties": {
+                "note": {
+                    "type": "string"
+                },
+                "operation": {
+                    "$ref": "#/definitions/models.BulkOperation"
+                },
+                "target": {
+                    "$ref": "#/definitions/models.BulkTarget"
+                }
+            }
+        },
+        "models.BulkProductResult": {
+            "type": "object",
+            "properties": {
+                "changed": {
+                    "type": "integer"
+                },
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BulkProductChange"
+                    }
+                },
+                "matched": {
+                    "type": "integer"
+                },
+                "preview": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.BulkTarget": {
+            "type": "object",
+            "properties": {
+                "all": {
+                    "type": "boolean"
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Cart": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "customer_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CartItem"
+                    }
+                },
+                "note": {
+                    "type": "string"
+                },
+                "outlet_id": {
+                    "type": "integer"
+                },
+                "reserve": {
+                    "type": "boolean"
+                },
+                "reserved_until": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "terminal": {
+                    "type": "string"
+                },
+                "total_amount": {
+                    "type": "integer"
+                },
+                "transaction_id": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CartCheckoutRequest": {
+            "type": "object",
+            "properties": {
+                "customer_phone": {
+                    "type": "string"
+                },
+                "gift_card_code": {
+                    "type": "string"
+                },
+                "redeem_points": {
+                    "type": "integer"
+                },
+                "voucher_code": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CartItem": {
+            "type": "object",
+            "properties": {
+                "available_stock": {
+                    "type": "number"
+                },
+                "base_quantity": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "modifiers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SelectedModifier"
+                    }
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "number"
+                },
+                "subtotal": {
+                    "type": "integer"
+                },
+                "unit": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Catalog": {
+            "type": "object",
+            "properties": {
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Categories"
+                    }
+                },
+                "exported_at": {
+                    "type": "string"
+                },
+                "outlet_id": {
+                    "type": "integer"
+                },
+                "products": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Product"
+                    }
+                }
+            }
+        },
+        "models.Categories": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CheckoutItem": {
+            "type": "object",
+            "properties": {
+                "modifiers": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "number"
+                },
+                "unit": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CheckoutRequest": {
+            "type": "object",
+            "properties": {
+                "customer_id": {
+                    "type": "integer"
+                },
+                "customer_phone": {
+                    "type": "string"
+                },
+                "gift_card_code": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CheckoutItem"
+                    }
+                },
+                "redeem_points": {
+                    "type": "integer"
+                },
+                "voucher_code": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ComponentUsage": {
+            "type": "object",
+            "properties": {
+                "nama": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "qty_terpakai": {
+                    "type": "number"
+                },
+                "satuan": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ConsolidatedReport": {
+            "type": "object",
+            "properties": {
+                "end_date": {
+                    "type": "string"
+                },
+                "outlets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OutletReport"
+                    }
+                },
+                "produk_terlaris": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ProductSales"
+                    }
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "total_revenue": {
+                    "type": "number"
+                },
+                "total_transaksi": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Customer": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Delta": {
+            "type": "object",
+            "properties": {
+                "absolute": {
+                    "type": "number"
+                },
+                "percent": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.FieldError": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "min"
+                },
+                "field": {
+                    "type": "string",
+                    "example": "price"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "price must not be negative"
+                }
+            }
+        },
+        "models.GiftCard": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "balance": {
+                    "type": "integer"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "initial_balance": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.LoyaltyBalance": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "type": "integer"
+                },
+                "customer_id": {
+                    "type": "integer"
+                },
+                "ledger": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.LoyaltyEntry"
+                    }
+                },
+                "point_value": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.LoyaltyEntry": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "customer_id": {
+                    "type": "integer"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "points": {
+                    "type": "integer"
+                },
+                "transaction_id": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Modifier": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ModifierGroup": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "max": {
+                    "type": "integer"
+                },
+                "min": {
+                    "type": "integer"
+                },
+                "modifiers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Modifier"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ModifierReport": {
+            "type": "object",
+            "properties": {
+                "end_date": {
+                    "type": "string"
+                },
+                "modifier": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ModifierSales"
+                    }
+                },
+                "start_date": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ModifierSales": {
+            "type": "object",
+            "properties": {
+                "dipilih": {
+                    "type": "integer"
+                },
+                "grup": {
+                    "type": "string"
+                },
+                "nama": {
+                    "type": "string"
+                },
+                "qty_terjual": {
+                    "type": "number"
+                },
+                "total_tambahan": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Outlet": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.OutletProduct": {
+            "type": "object",
+            "properties": {
+                "base_price": {
+                    "type": "integer"
+                },
+                "in_transit": {
+                    "type": "number"
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "price_override": {
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "stock": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.OutletProductUpdate": {
+            "type": "object",
+            "properties": {
+                "price_override": {
+                    "type": "integer"
+                },
+                "stock": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.OutletReport": {
+            "type": "object",
+            "properties": {
+                "outlet_id": {
+                    "type": "integer"
+                },
+                "outlet_name": {
+                    "type": "string"
+                },
+                "total_revenue": {
+                    "type": "number"
+                },
+                "total_transaksi": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.PeriodSummary": {
+            "type": "object",
+            "properties": {
+                "average_basket": {
+                    "type": "number"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "total_revenue": {
+                    "type": "number"
+                },
+                "total_transaksi": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Product": {
+            "type": "object",
+            "properties": {
+                "category_id": {
+                    "type": "integer"
+                },
+                "category_name": {
+                    "type": "string"
+                },
+                "components": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ProductComponent"
+                    }
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "image": {
+                    "$ref": "#/definitions/models.ProductImage"
+                },
+                "modifier_groups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ModifierGroup"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "sku": {
+                    "type": "string"
+                },
+                "stock": {
+                    "type": "number"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "unit": {
+                    "type": "string"
+                },
+                "units": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ProductUnit"
+                    }
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ProductComparison": {
+            "type": "object",
+            "properties": {
+                "delta": {
+                    "$ref": "#/definitions/models.Delta"
+                },
+                "nama": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "qty_current": {
+                    "type": "number"
+                },
+                "qty_previous": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.ProductComponent": {
+            "type": "object",
+            "properties": {
+                "product_id": {
+                    "type": "integer"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "number"
+                },
+                "unit": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ProductImage": {
+            "type": "object",
+            "properties": {
+                "thumbnails": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ProductImportReport": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "integer"
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ProductImportResult"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "updated": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ProductImportResult": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "example": "create"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FieldError"
+                    }
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "row": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "sku": {
+                    "type": "string",
+                    "example": "KOPI-001"
+                }
+            }
+        },
+        "models.ProductPrice": {
+            "type": "object",
+            "properties": {
+                "applied_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "effective_from": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ProductReport": {
+            "type": "object",
+            "properties": {
+                "end_date": {
+                    "type": "string"
+                },
+                "komponen_terpakai": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ComponentUsage"
+                    }
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "terjual": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ProductSales"
+                    }
+                }
+            }
+        },
+        "models.ProductSales": {
+            "type": "object",
+            "properties": {
+                "nama": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "qty_terjual": {
+                    "type": "number"
+                },
+                "satuan": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ProductUnit": {
+            "type": "object",
+            "properties": {
+                "factor": {
+                    "type": "number"
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "unit": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Redemption": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "transaction_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Report": {
+            "type": "object",
+            "properties": {
+                "produk_terlaris": {
+                    "type": "object",
+                    "properties": {
+                        "nama": {
+                            "type": "string"
+                        },
+                        "qty_terjual": {
+                            "type": "number"
+                        }
+                    }
+                },
+                "total_revenue": {
+                    "type": "number"
+                },
+                "total_transaksi": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ReportComparison": {
+            "type": "object",
+            "properties": {
+                "average_basket": {
+                    "$ref": "#/definitions/models.Delta"
+                },
+                "compare_to": {
+                    "type": "string"
+                },
+                "current": {
+                    "$ref": "#/definitions/models.PeriodSummary"
+                },
+                "previous": {
+                    "$ref": "#/definitions/models.PeriodSummary"
+                },
+                "produk": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ProductComparison"
+                    }
+                },
+                "timezone": {
+                    "type": "string"
+                },
+                "total_revenue": {
+                    "$ref": "#/definitions/models.Delta"
+                },
+                "total_transaksi": {
+                    "$ref": "#/definitions/models.Delta"
+                }
+            }
+        },
+        "models.SchedulePriceRequest": {
+            "type": "object",
+            "properties": {
+                "effective_from": {
+                    "type": "string",
+                    "example": "2026-11-01"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SelectedModifier": {
+            "type": "object",
+            "properties": {
+                "group": {
+                    "type": "string"
+                },
+                "modifier_id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.StockMovement": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "outlet_id": {
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "number"
+                },
+                "transaction_id": {
+                    "type": "integer"
+                },
+                "transfer_id": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.StockTransfer": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "from_outlet_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.StockTransferLine"
+                    }
+                },
+                "note": {
+                    "type": "string"
+                },
+                "received_at": {
+                    "type": "string"
+                },
+                "shipped_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "to_outlet_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.StockTransferLine": {
+            "type": "object",
+            "properties": {
+                "discrepancy": {
+                    "type": "number"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "number"
+                },
+                "quantity_received": {
+                    "type": "number"
+                },
+                "quantity_shipped": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.SyncBatchRequest": {
+            "type": "object",
+            "properties": {
+                "transactions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SyncTransaction"
+                    }
+                }
+            }
+        },
+        "models.SyncBatchResponse": {
+            "type": "object",
+            "properties": {
+                "applied": {
+                    "type": "integer"
+                },
+                "duplicate": {
+                    "type": "integer"
+                },
+                "rejected": {
+                    "type": "integer"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SyncResult"
+                    }
+                }
+            }
+        },
+        "models.SyncChanges": {
+            "type": "object",
+            "properties": {
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Categories"
+                    }
+                },
+                "cursor": {
+                    "type": "string"
+                },
+                "deleted": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SyncTombstone"
+                    }
+                },
+                "has_more": {
+                    "type": "boolean"
+                },
+                "products": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Product"
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "models.Categories": {
+        },
+        "models.SyncResult": {
             "type": "object",
             "properties": {
-                "id": {
-                    "type": "integer"
+                "client_uuid": {
+                    "type": "string"
                 },
-                "name": {
+                "reason": {
+                    "type": "string"
+                },
+                "status": {
                     "type": "string"
+                },
+                "transaction_id": {
+                    "type": "integer"
                 }
             }
         },
-        "models.CheckoutItem": {
+        "models.SyncTombstone": {
             "type": "object",
             "properties": {
-                "product_id": {
-                    "type": "integer"
+                "deleted_at": {
+                    "type": "string"
                 },
-                "quantity": {
+                "entity": {
+                    "type": "string"
+                },
+                "id": {
                     "type": "integer"
                 }
             }
         },
-        "models.CheckoutRequest": {
+        "models.SyncTransaction": {
             "type": "object",
             "properties": {
+                "client_uuid": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "customer_id": {
+                    "type": "integer"
+                },
+                "customer_phone": {
+                    "type": "string"
+                },
+                "gift_card_code": {
+                    "type": "string"
+                },
                 "items": {
                     "type": "array",
                     "items": {
                         "$ref": "#/definitions/models.CheckoutItem"
                     }
+                },
+                "redeem_points": {
+                    "type": "integer"
+                },
+                "voucher_code": {
+                    "type": "string"
                 }
             }
         },
-        "models.Product": {
+        "models.Tenant": {
             "type": "object",
             "properties": {
-                "category_id": {
-                    "type": "integer"
+                "active": {
+                    "type": "boolean"
                 },
-                "category_name": {
+                "address": {
+                    "description": "Identitas toko di struk tenant; nama toko memakai Name dan ReceiptFooter kosong berarti\nfooter bawaan",
+                    "type": "string"
+                },
+                "api_key": {
+                    "type": "string"
+                },
+                "created_at": {
                     "type": "string"
                 },
                 "id": {
@@ -455,27 +5407,54 @@ const docTemplate = `{
                 "name": {
                     "type": "string"
                 },
-                "price": {
-                    "type": "number"
+                "npwp": {
+                    "type": "string"
                 },
-                "stock": {
-                    "type": "integer"
+                "phone": {
+                    "type": "string"
+                },
+                "receipt_footer": {
+                    "type": "string"
+                },
+                "schema": {
+                    "type": "string"
+                },
+                "slug": {
+                    "type": "string"
                 }
             }
         },
-        "models.Report": {
+        "models.TenantUpdate": {
             "type": "object",
             "properties": {
-                "produk_terlaris": {
-                    "type": "object",
-                    "properties": {
-                        "nama": {
-                            "type": "string"
-                        },
-                        "qty_terjual": {
-                            "type": "integer"
-                        }
-                    }
+                "active": {
+                    "type": "boolean"
+                },
+                "address": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "npwp": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "receipt_footer": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.TimeSeriesPoint": {
+            "type": "object",
+            "properties": {
+                "bucket": {
+                    "type": "string"
+                },
+                "qty_terjual": {
+                    "type": "number"
                 },
                 "total_revenue": {
                     "type": "number"
@@ -485,32 +5464,101 @@ const docTemplate = `{
                 }
             }
         },
+        "models.TimeSeriesReport": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TimeSeriesPoint"
+                    }
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "interval": {
+                    "type": "string"
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "timezone": {
+                    "type": "string"
+                }
+            }
+        },
         "models.Transaction": {
             "type": "object",
             "properties": {
+                "amount_due": {
+                    "type": "integer"
+                },
                 "created_at": {
                     "type": "string"
                 },
+                "customer_id": {
+                    "type": "integer"
+                },
                 "details": {
                     "type": "array",
                     "items": {
                         "$ref": "#/definitions/models.TransactionDetail"
                     }
                 },
+                "discount_amount": {
+                    "type": "integer"
+                },
+                "gift_card_amount": {
+                    "type": "integer"
+                },
+                "gift_card_code": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "integer"
                 },
+                "outlet_id": {
+                    "type": "integer"
+                },
+                "points_earned": {
+                    "type": "integer"
+                },
+                "points_redeemed": {
+                    "type": "integer"
+                },
                 "total_amount": {
                     "type": "integer"
+                },
+                "voucher_code": {
+                    "type": "string"
+                },
+                "voucher_discount": {
+                    "type": "integer"
                 }
             }
         },
         "models.TransactionDetail": {
             "type": "object",
             "properties": {
+                "base_quantity": {
+                    "type": "number"
+                },
+                "components": {
+                    "description": "Components berisi stok komponen yang terpakai jika produk adalah paket atau resep",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ProductComponent"
+                    }
+                },
                 "id": {
                     "type": "integer"
                 },
+                "modifiers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SelectedModifier"
+                    }
+                },
                 "product_id": {
                     "type": "integer"
                 },
@@ -518,13 +5566,109 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "quantity": {
-                    "type": "integer"
+                    "type": "number"
                 },
                 "subtotal": {
                     "type": "integer"
                 },
                 "transaction_id": {
                     "type": "integer"
+                },
+                "unit": {
+                    "type": "string"
+                },
+                "unit_price": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.TransferQuantities": {
+            "type": "object",
+            "properties": {
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CheckoutItem"
+                    }
+                }
+            }
+        },
+        "models.User": {
+            "type": "object",
+            "properties": {
+                "api_key": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "outlet_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "role": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Voucher": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "category_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "code": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "max_discount": {
+                    "type": "integer"
+                },
+                "min_spend": {
+                    "type": "integer"
+                },
+                "product_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "starts_at": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "usage_limit": {
+                    "type": "integer"
+                },
+                "used_count": {
+                    "type": "integer"
+                },
+                "value": {
+                    "type": "integer"
                 }
             }
         }
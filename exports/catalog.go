@@ -0,0 +1,28 @@
+package exports
+
+import "kasir-api/models"
+
+// CatalogColumns memakai judul kolom yang dikenali impor produk, sehingga file hasil ekspor
+// bisa langsung diimpor kembali di server lain.
+var CatalogColumns = []Column{
+	{ID: "SKU", EN: "SKU", Kind: Text, Width: 16},
+	{ID: "Nama", EN: "Name", Kind: Text, Width: 30},
+	{ID: "Harga", EN: "Price", Kind: Rupiah, Width: 14},
+	{ID: "Stok", EN: "Stock", Kind: Quantity, Width: 10},
+	{ID: "Satuan", EN: "Unit", Kind: Text, Width: 8},
+	{ID: "Kategori", EN: "Category", Kind: Text, Width: 20},
+}
+
+// WriteCatalog menulis satu baris per produk. Kategori ikut tercatat lewat nama kategori produk.
+func WriteCatalog(writer Writer, catalog *models.Catalog) error {
+	for _, p := range catalog.Products {
+		category := ""
+		if p.CategoryName != nil {
+			category = *p.CategoryName
+		}
+		if err := writer.WriteRow(p.SKU, p.Name, p.Price, p.Stock, p.Unit, category); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package exports
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type csvWriter struct {
+	w       *csv.Writer
+	flusher http.Flusher
+	columns []Column
+}
+
+func newCSVWriter(w io.Writer, columns []Column, lang string) (*csvWriter, error) {
+	// BOM UTF-8 agar Excel membaca nama produk dengan benar
+	if _, err := w.Write([]byte("\xEF\xBB\xBF")); err != nil {
+		return nil, err
+	}
+
+	cw := &csvWriter{w: csv.NewWriter(w), columns: columns}
+	if f, ok := w.(http.Flusher); ok {
+		cw.flusher = f
+	}
+
+	header := make([]string, len(columns))
+	for i, c := range columns {
+		header[i] = c.Title(lang)
+	}
+	if err := cw.w.Write(header); err != nil {
+		return nil, err
+	}
+	return cw, nil
+}
+
+func (cw *csvWriter) WriteRow(values ...interface{}) error {
+	record := make([]string, len(cw.columns))
+	for i, c := range cw.columns {
+		if i < len(values) {
+			record[i] = formatCSV(c.Kind, values[i])
+		}
+	}
+	if err := cw.w.Write(record); err != nil {
+		return err
+	}
+	cw.w.Flush()
+	if cw.flusher != nil {
+		cw.flusher.Flush()
+	}
+	return cw.w.Error()
+}
+
+func (cw *csvWriter) Close() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+// Nominal rupiah ditulis sebagai bilangan bulat tanpa pemisah ribuan
+// supaya tetap terbaca sebagai angka di spreadsheet apa pun. Teks yang menyerupai rumus diberi
+// awalan '.
+func formatCSV(kind ColumnKind, value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return escapeFormula(v)
+	case time.Time:
+		if kind == Date {
+			return v.Format("2006-01-02")
+		}
+		return v.Format("2006-01-02 15:04:05")
+	case float64:
+		if kind == Rupiah || kind == Integer {
+			return strconv.FormatFloat(v, 'f', 0, 64)
+		}
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return fmt.Sprint(value)
+}
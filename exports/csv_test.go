@@ -0,0 +1,35 @@
+package exports
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatCSV(t *testing.T) {
+	cases := []struct {
+		name  string
+		kind  ColumnKind
+		value interface{}
+		want  string
+	}{
+		{"plain text", Text, "Kopi Susu", "Kopi Susu"},
+		{"formula", Text, "=HYPERLINK(\"http://x\")", "'=HYPERLINK(\"http://x\")"},
+		{"plus sign", Text, "+62812", "'+62812"},
+		{"minus sign", Text, "-1+1", "'-1+1"},
+		{"at sign", Text, "@SUM(A1)", "'@SUM(A1)"},
+		{"tab", Text, "\t=1", "'\t=1"},
+		{"empty text", Text, "", ""},
+		{"negative rupiah stays a number", Rupiah, float64(-1500), "-1500"},
+		{"quantity", Quantity, 0.75, "0.75"},
+		{"date", Date, time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC), "2024-03-05"},
+		{"nil", Text, nil, ""},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := formatCSV(tc.kind, tc.value); got != tc.want {
+				t.Fatalf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package exports
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Format keluaran yang didukung
+const (
+	FormatJSON = "json"
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+const (
+	ContentTypeCSV  = "text/csv; charset=utf-8"
+	ContentTypeXLSX = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+)
+
+// Bahasa judul kolom
+const (
+	LangID = "id"
+	LangEN = "en"
+)
+
+type ColumnKind int
+
+const (
+	Text ColumnKind = iota
+	Integer
+	Rupiah
+	Date
+	DateTime
+	// Quantity adalah kuantitas dengan maksimal tiga angka desimal, contoh 0,75 kg
+	Quantity
+)
+
+// Column mendefinisikan satu kolom ekspor beserta judulnya dalam dua bahasa.
+type Column struct {
+	ID    string
+	EN    string
+	Kind  ColumnKind
+	Width float64
+}
+
+func (c Column) Title(lang string) string {
+	if lang == LangEN {
+		return c.EN
+	}
+	return c.ID
+}
+
+// Writer menulis baris satu per satu langsung ke response, tanpa menampung seluruh data di memori.
+type Writer interface {
+	WriteRow(values ...interface{}) error
+	Close() error
+}
+
+// Negotiate menentukan format dari query format=, lalu header Accept. Default json.
+func Negotiate(r *http.Request) string {
+	switch strings.ToLower(r.URL.Query().Get("format")) {
+	case FormatCSV:
+		return FormatCSV
+	case FormatXLSX:
+		return FormatXLSX
+	case FormatJSON:
+		return FormatJSON
+	}
+
+	accept := r.Header.Get("Accept")
+	switch {
+	case strings.Contains(accept, "text/csv"):
+		return FormatCSV
+	case strings.Contains(accept, "spreadsheetml.sheet"):
+		return FormatXLSX
+	}
+	return FormatJSON
+}
+
+// Language menentukan bahasa judul kolom dari query lang=, lalu header Accept-Language. Default Indonesia.
+func Language(r *http.Request) string {
+	lang := strings.ToLower(r.URL.Query().Get("lang"))
+	if lang == "" {
+		lang = strings.ToLower(r.Header.Get("Accept-Language"))
+	}
+	if strings.HasPrefix(lang, LangEN) {
+		return LangEN
+	}
+	return LangID
+}
+
+// NewWriter menyiapkan header response (Content-Type dan nama file) lalu mengembalikan
+// Writer untuk format csv atau xlsx. Judul kolom langsung ditulis sebagai baris pertama.
+func NewWriter(w http.ResponseWriter, format string, filename string, sheet string, columns []Column, lang string) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		w.Header().Set("Content-Type", ContentTypeCSV)
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.csv"`, filename))
+	case FormatXLSX:
+		w.Header().Set("Content-Type", ContentTypeXLSX)
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.xlsx"`, filename))
+	default:
+		return nil, fmt.Errorf("unsupported export format %q", format)
+	}
+	return Open(w, format, sheet, columns, lang)
+}
+
+// Open mengembalikan Writer csv atau xlsx yang menulis ke w, misalnya file pada perintah CLI.
+func Open(w io.Writer, format string, sheet string, columns []Column, lang string) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w, columns, lang)
+	case FormatXLSX:
+		return newXLSXWriter(w, sheet, columns, lang)
+	}
+	return nil, fmt.Errorf("unsupported export format %q", format)
+}
+
+// escapeFormula memberi awalan ' pada teks yang diawali =, +, -, @, tab atau carriage return,
+// agar nama produk atau pelanggan tidak dijalankan sebagai rumus saat file dibuka di spreadsheet.
+func escapeFormula(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+// wallClock membuang informasi zona agar jam yang tertulis di spreadsheet sama dengan jam lokal toko.
+func wallClock(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
+}
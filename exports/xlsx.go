@@ -0,0 +1,109 @@
+package exports
+
+import (
+	"io"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Format angka rupiah tanpa desimal, pemisah ribuan mengikuti locale Excel pengguna
+const rupiahNumFmt = `"Rp"#,##0;-"Rp"#,##0`
+
+type xlsxWriter struct {
+	out     io.Writer
+	file    *excelize.File
+	stream  *excelize.StreamWriter
+	columns []Column
+	styles  map[ColumnKind]int
+	row     int
+}
+
+// Baris ditulis lewat StreamWriter excelize yang memindahkan data ke file sementara
+// saat ukurannya besar, sehingga ekspor besar tidak ditampung di memori.
+func newXLSXWriter(w io.Writer, sheet string, columns []Column, lang string) (*xlsxWriter, error) {
+	file := excelize.NewFile()
+	if err := file.SetSheetName("Sheet1", sheet); err != nil {
+		return nil, err
+	}
+
+	stream, err := file.NewStreamWriter(sheet)
+	if err != nil {
+		return nil, err
+	}
+
+	xw := &xlsxWriter{out: w, file: file, stream: stream, columns: columns, styles: map[ColumnKind]int{}}
+
+	numFmt := rupiahNumFmt
+	formats := map[ColumnKind]*excelize.Style{
+		// Format teks (@) agar isi sel tetap teks dan tidak dibaca sebagai rumus
+		Text:     {NumFmt: 49},
+		Integer:  {NumFmt: 3},
+		Rupiah:   {CustomNumFmt: &numFmt},
+		Date:     {NumFmt: 14},
+		DateTime: {NumFmt: 22},
+		// General menampilkan desimal hanya jika ada, contoh 12 atau 0,75
+		Quantity: {NumFmt: 0},
+	}
+	for kind, style := range formats {
+		id, err := file.NewStyle(style)
+		if err != nil {
+			return nil, err
+		}
+		xw.styles[kind] = id
+	}
+
+	headerStyle, err := file.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]interface{}, len(columns))
+	for i, c := range columns {
+		width := c.Width
+		if width == 0 {
+			width = 16
+		}
+		if err := stream.SetColWidth(i+1, i+1, width); err != nil {
+			return nil, err
+		}
+		header[i] = excelize.Cell{StyleID: headerStyle, Value: c.Title(lang)}
+	}
+
+	if err := xw.setRow(header); err != nil {
+		return nil, err
+	}
+	return xw, nil
+}
+
+func (xw *xlsxWriter) WriteRow(values ...interface{}) error {
+	cells := make([]interface{}, len(xw.columns))
+	for i, c := range xw.columns {
+		if i >= len(values) {
+			continue
+		}
+		value := values[i]
+		if t, ok := value.(time.Time); ok {
+			value = wallClock(t)
+		}
+		cells[i] = excelize.Cell{StyleID: xw.styles[c.Kind], Value: value}
+	}
+	return xw.setRow(cells)
+}
+
+func (xw *xlsxWriter) setRow(cells []interface{}) error {
+	xw.row++
+	cell, err := excelize.CoordinatesToCellName(1, xw.row)
+	if err != nil {
+		return err
+	}
+	return xw.stream.SetRow(cell, cells)
+}
+
+func (xw *xlsxWriter) Close() error {
+	defer xw.file.Close()
+	if err := xw.stream.Flush(); err != nil {
+		return err
+	}
+	return xw.file.Write(xw.out)
+}
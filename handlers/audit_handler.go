@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"encoding/json"
+	"kasir-api/apperror"
+	"kasir-api/services"
+	"net/http"
+)
+
+type AuditHandler struct {
+	service *services.AuditService
+}
+
+func NewAuditHandler(service *services.AuditService) *AuditHandler {
+	return &AuditHandler{service: service}
+}
+
+// GET /api/audit
+// @Summary      Get Audit Log
+// @Description  Mengambil jejak perubahan data (produk, harga/stok outlet, transaksi, outlet, pengguna), terbaru lebih dulu. Setiap entri berisi pelaku, aksi, data sebelum/sesudah, field yang berubah, IP, user agent dan request ID. Khusus pemilik (owner)
+// @Tags         audit
+// @Produce      json
+// @Param        entity      query     string  false  "Filter entitas (product, product_price, outlet_product, transaction, outlet, user, category, voucher, gift_card, customer)"
+// @Param        entity_id   query     int     false  "Filter ID entitas"
+// @Param        action      query     string  false  "Filter aksi (create, update, delete)"
+// @Param        actor       query     string  false  "Filter nama pelaku"
+// @Param        start_date  query     string  false  "Tanggal awal (Format: YYYY-MM-DD)" example(2026-01-01)
+// @Param        end_date    query     string  false  "Tanggal akhir (Format: YYYY-MM-DD)" example(2026-02-01)
+// @Param        limit       query     int     false  "Jumlah maksimal entri (default 100, maks 1000)"
+// @Success      200      {array}   models.AuditEntry
+// @Failure      400      {object}  apperror.Response "Invalid query"
+// @Failure      403      {object}  apperror.Response "Owner access required"
+// @Failure      500      {object}  apperror.Response "Failed to get audit log"
+// @Router       /api/audit [get]
+func (h *AuditHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		apperror.Write(w, apperror.MethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	entries, err := h.service.GetAll(query.Get("entity"), query.Get("entity_id"), query.Get("action"), query.Get("actor"), query.Get("start_date"), query.Get("end_date"), query.Get("limit"))
+	if err != nil {
+		apperror.Write(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(entries)
+}
@@ -0,0 +1,300 @@
+package handlers
+
+import (
+	"encoding/json"
+	"kasir-api/apperror"
+	"kasir-api/middlewares"
+	"kasir-api/models"
+	"kasir-api/services"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type CartHandler struct {
+	service *services.CartService
+}
+
+func NewCartHandler(service *services.CartService) *CartHandler {
+	return &CartHandler{service: service}
+}
+
+// GET /api/keranjang
+// @Summary      Get Carts
+// @Description  Mengambil keranjang berdasarkan status. Tanpa filter, mengembalikan keranjang open dan held sehingga semua terminal bisa melihat keranjang yang ditahan
+// @Tags         keranjang
+// @Produce      json
+// @Param        status  query     string false  "Status keranjang" Enums(open, held, checked_out, abandoned)
+// @Success      200      {array}   models.Cart
+// @Failure      400      {object}  apperror.Response "Invalid query"
+// @Failure      500      {object}  apperror.Response "Failed to get carts"
+// @Router       /api/keranjang [get]
+func (h *CartHandler) HandleCarts(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetAll(w, r)
+	case http.MethodPost:
+		h.Create(w, r)
+	default:
+		apperror.Write(w, apperror.MethodNotAllowed)
+	}
+}
+
+func (h *CartHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	carts, err := h.service.GetAll(middlewares.OutletID(r), r.URL.Query().Get("status"))
+	if err != nil {
+		apperror.Write(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(carts)
+}
+
+// POST /api/keranjang
+// @Summary Create Cart
+// @Description Membuat keranjang baru: { terminal, note, customer_id, reserve }. Jika reserve true, stok item di keranjang dipesan sementara dan kedaluwarsa otomatis; stok yang dipesan tidak bisa dijual lewat checkout langsung, sinkronisasi offline maupun keranjang lain
+// @Accept json
+// @Tags   keranjang
+// @Produce json
+// @Param cart body models.Cart true "New Cart Data"
+// @Success 201 {object} models.Cart
+// @Failure 400 {object} apperror.Response "Invalid request body"
+// @Failure 500 {object} apperror.Response "Failed to create cart"
+// @Router /api/keranjang [post]
+func (h *CartHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var cart models.Cart
+	err := json.NewDecoder(r.Body).Decode(&cart)
+	if err != nil {
+		apperror.Write(w, errInvalidBody)
+		return
+	}
+
+	err = h.service.Create(middlewares.OutletID(r), &cart)
+	if err != nil {
+		apperror.Write(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(cart)
+}
+
+// HandleCartByID melayani /api/keranjang/{id}, /items, /items/{product_id} dan aksi hold, resume, abandon, checkout.
+func (h *CartHandler) HandleCartByID(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/keranjang/"), "/")
+	parts := strings.Split(path, "/")
+
+	id, err := strconv.Atoi(parts[0])
+	if err != nil {
+		apperror.Write(w, apperror.BadRequest("Invalid cart ID"))
+		return
+	}
+
+	switch {
+	case len(parts) == 1 && r.Method == http.MethodGet:
+		h.GetByID(w, r, id)
+	case len(parts) == 2 && parts[1] == "items" && r.Method == http.MethodPost:
+		h.AddItem(w, r, id)
+	case len(parts) == 3 && parts[1] == "items":
+		itemID, err := strconv.Atoi(parts[2])
+		if err != nil {
+			apperror.Write(w, apperror.BadRequest("Invalid cart item ID"))
+			return
+		}
+		switch r.Method {
+		case http.MethodPut:
+			h.UpdateItem(w, r, id, itemID)
+		case http.MethodDelete:
+			h.RemoveItem(w, r, id, itemID)
+		default:
+			apperror.Write(w, apperror.MethodNotAllowed)
+		}
+	case len(parts) == 2 && r.Method == http.MethodPost:
+		switch parts[1] {
+		case "hold":
+			h.Hold(w, r, id)
+		case "resume":
+			h.Resume(w, r, id)
+		case "abandon":
+			h.Abandon(w, r, id)
+		case "checkout":
+			h.Checkout(w, r, id)
+		default:
+			apperror.Write(w, errRouteNotFound)
+		}
+	default:
+		apperror.Write(w, apperror.MethodNotAllowed)
+	}
+}
+
+// GET /api/keranjang/{id}
+// @Summary      Get Cart by ID
+// @Description  Mengambil keranjang beserta item, harga terkini dan stok tersedia
+// @Tags         keranjang
+// @Produce      json
+// @Param        id       path      int   true   "Cart ID"
+// @Success      200      {object}  models.Cart
+// @Failure      404      {object}  apperror.Response "Cart not found"
+// @Router       /api/keranjang/{id} [get]
+func (h *CartHandler) GetByID(w http.ResponseWriter, r *http.Request, id int) {
+	cart, err := h.service.GetByID(middlewares.OutletID(r), id)
+	if err != nil {
+		apperror.Write(w, err)
+		return
+	}
+	writeCart(w, cart)
+}
+
+// POST /api/keranjang/{id}/items
+// @Summary Add Cart Item
+// @Description Menambah produk ke keranjang: { product_id, quantity, unit, modifiers }. unit kosong berarti satuan dasar produk. modifiers berisi id modifier yang dipilih dan diperiksa terhadap batas min/max setiap kelompok modifier produk. Jumlah ditambahkan ke baris produk dengan satuan dan modifier yang sama, satuan atau modifier lain menjadi baris baru. Stok divalidasi langsung
+// @Accept json
+// @Tags   keranjang
+// @Produce json
+// @Param id   path int                 true "Cart ID"
+// @Param item body models.CheckoutItem true "Cart Item"
+// @Success 200 {object} models.Cart
+// @Failure 400 {object} apperror.Response "Invalid request body"
+// @Failure 500 {object} apperror.Response "Failed to update cart"
+// @Router /api/keranjang/{id}/items [post]
+func (h *CartHandler) AddItem(w http.ResponseWriter, r *http.Request, id int) {
+	var item models.CheckoutItem
+	err := json.NewDecoder(r.Body).Decode(&item)
+	if err != nil {
+		apperror.Write(w, errInvalidBody)
+		return
+	}
+
+	cart, err := h.service.AddItem(middlewares.OutletID(r), id, item)
+	h.writeResult(w, cart, err)
+}
+
+// PUT /api/keranjang/{id}/items/{item_id}
+// @Summary Update Cart Item
+// @Description Mengganti jumlah baris keranjang (id item dari GET keranjang): { quantity, unit, modifiers }. unit kosong mempertahankan satuan item dan modifiers yang tidak dikirim mempertahankan modifier item ([] menghapus semua modifier). Jumlah 0 menghapus item
+// @Accept json
+// @Tags   keranjang
+// @Produce json
+// @Param id      path int                 true "Cart ID"
+// @Param item_id path int                 true "Cart Item ID"
+// @Param item    body models.CheckoutItem true "Cart Item"
+// @Success 200 {object} models.Cart
+// @Failure 400 {object} apperror.Response "Invalid request body"
+// @Failure 500 {object} apperror.Response "Failed to update cart"
+// @Router /api/keranjang/{id}/items/{item_id} [put]
+func (h *CartHandler) UpdateItem(w http.ResponseWriter, r *http.Request, id int, itemID int) {
+	var item models.CheckoutItem
+	err := json.NewDecoder(r.Body).Decode(&item)
+	if err != nil {
+		apperror.Write(w, errInvalidBody)
+		return
+	}
+
+	cart, err := h.service.UpdateItem(middlewares.OutletID(r), id, itemID, item.Quantity, item.Unit, item.Modifiers)
+	h.writeResult(w, cart, err)
+}
+
+// DELETE /api/keranjang/{id}/items/{item_id}
+// @Summary Remove Cart Item
+// @Description Menghapus baris dari keranjang
+// @Tags   keranjang
+// @Produce json
+// @Param id      path int true "Cart ID"
+// @Param item_id path int true "Cart Item ID"
+// @Success 200 {object} models.Cart
+// @Failure 500 {object} apperror.Response "Failed to update cart"
+// @Router /api/keranjang/{id}/items/{item_id} [delete]
+func (h *CartHandler) RemoveItem(w http.ResponseWriter, r *http.Request, id int, itemID int) {
+	cart, err := h.service.RemoveItem(middlewares.OutletID(r), id, itemID)
+	h.writeResult(w, cart, err)
+}
+
+// POST /api/keranjang/{id}/hold
+// @Summary Hold Cart
+// @Description Menahan (parkir) keranjang open agar kasir bisa melayani pelanggan berikutnya
+// @Tags   keranjang
+// @Produce json
+// @Param id path int true "Cart ID"
+// @Success 200 {object} models.Cart
+// @Failure 500 {object} apperror.Response "Failed to update cart"
+// @Router /api/keranjang/{id}/hold [post]
+func (h *CartHandler) Hold(w http.ResponseWriter, r *http.Request, id int) {
+	cart, err := h.service.Hold(middlewares.OutletID(r), id)
+	h.writeResult(w, cart, err)
+}
+
+// POST /api/keranjang/{id}/resume
+// @Summary Resume Cart
+// @Description Melanjutkan keranjang yang ditahan, dari terminal mana pun
+// @Tags   keranjang
+// @Produce json
+// @Param id path int true "Cart ID"
+// @Success 200 {object} models.Cart
+// @Failure 500 {object} apperror.Response "Failed to update cart"
+// @Router /api/keranjang/{id}/resume [post]
+func (h *CartHandler) Resume(w http.ResponseWriter, r *http.Request, id int) {
+	cart, err := h.service.Resume(middlewares.OutletID(r), id)
+	h.writeResult(w, cart, err)
+}
+
+// POST /api/keranjang/{id}/abandon
+// @Summary Abandon Cart
+// @Description Membatalkan keranjang dan melepas reservasi stoknya
+// @Tags   keranjang
+// @Produce json
+// @Param id path int true "Cart ID"
+// @Success 200 {object} models.Cart
+// @Failure 500 {object} apperror.Response "Failed to update cart"
+// @Router /api/keranjang/{id}/abandon [post]
+func (h *CartHandler) Abandon(w http.ResponseWriter, r *http.Request, id int) {
+	cart, err := h.service.Abandon(middlewares.OutletID(r), id)
+	h.writeResult(w, cart, err)
+}
+
+// POST /api/keranjang/{id}/checkout
+// @Summary Checkout Cart
+// @Description Mengubah keranjang menjadi transaksi. Opsional: { customer_phone, redeem_points, voucher_code, gift_card_code }
+// @Accept json
+// @Tags   keranjang
+// @Produce json
+// @Param id      path int                        true  "Cart ID"
+// @Param options body models.CartCheckoutRequest false "Checkout Options"
+// @Success 201 {object} models.Transaction
+// @Failure 400 {object} apperror.Response "Invalid request body"
+// @Failure 500 {object} apperror.Response "Failed to checkout cart"
+// @Router /api/keranjang/{id}/checkout [post]
+func (h *CartHandler) Checkout(w http.ResponseWriter, r *http.Request, id int) {
+	var opts models.CartCheckoutRequest
+	if r.ContentLength != 0 {
+		err := json.NewDecoder(r.Body).Decode(&opts)
+		if err != nil {
+			apperror.Write(w, errInvalidBody)
+			return
+		}
+	}
+
+	transaction, err := h.service.Checkout(middlewares.OutletID(r), id, opts, middlewares.Audit(r))
+	if err != nil {
+		apperror.Write(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(transaction)
+}
+
+func (h *CartHandler) writeResult(w http.ResponseWriter, cart *models.Cart, err error) {
+	if err != nil {
+		apperror.Write(w, err)
+		return
+	}
+	writeCart(w, cart)
+}
+
+func writeCart(w http.ResponseWriter, cart *models.Cart) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(cart)
+}
@@ -0,0 +1,201 @@
+package handlers
+
+import (
+	"encoding/json"
+	"kasir-api/apperror"
+	"kasir-api/middlewares"
+	"kasir-api/models"
+	"kasir-api/services"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type CustomerHandler struct {
+	service *services.CustomerService
+}
+
+func NewCustomerHandler(service *services.CustomerService) *CustomerHandler {
+	return &CustomerHandler{service: service}
+}
+
+// GET /api/pelanggan
+// @Summary      Get All Customers
+// @Description  Mengambil semua data pelanggan. Terdapat opsi pencarian berdasarkan nama, nomor telepon atau email
+// @Tags         pelanggan
+// @Produce      json
+// @Param        search  query     string false  "Cari nama, telepon atau email"
+// @Success      200      {array}   models.Customer
+// @Failure      500      {object}  apperror.Response "Failed to get customers"
+// @Router       /api/pelanggan [get]
+func (h *CustomerHandler) HandleCustomers(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetAll(w, r)
+	case http.MethodPost:
+		h.Create(w, r)
+	default:
+		apperror.Write(w, apperror.MethodNotAllowed)
+	}
+}
+
+// GET /api/pelanggan/{id}
+// @Summary      Get Customer by ID
+// @Description  Mengambil data pelanggan berdasarkan ID
+// @Tags         pelanggan
+// @Produce      json
+// @Param        id       path      int   true   "Customer ID"
+// @Success      200      {object}  models.Customer
+// @Failure      400      {object}  apperror.Response "Invalid customer ID"
+// @Failure      404      {object}  apperror.Response "Customer not found"
+// @Router       /api/pelanggan/{id} [get]
+func (h *CustomerHandler) HandleCustomerByID(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/pelanggan/"), "/")
+	idStr, action, _ := strings.Cut(path, "/")
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		apperror.Write(w, apperror.BadRequest("Invalid customer ID"))
+		return
+	}
+
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		h.GetByID(w, r, id)
+	case action == "" && r.Method == http.MethodPut:
+		h.Update(w, r, id)
+	case action == "transaksi" && r.Method == http.MethodGet:
+		h.GetTransactions(w, r, id)
+	case action == "poin" && r.Method == http.MethodGet:
+		h.GetPoints(w, r, id)
+	case action == "" || action == "transaksi" || action == "poin":
+		apperror.Write(w, apperror.MethodNotAllowed)
+	default:
+		apperror.Write(w, errRouteNotFound)
+	}
+}
+
+func (h *CustomerHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	customers, err := h.service.GetAll(r.URL.Query().Get("search"))
+	if err != nil {
+		apperror.Write(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(customers)
+}
+
+// POST /api/pelanggan
+// @Summary Create New Customer
+// @Description Menambahkan pelanggan baru, data yang perlu diisi: { name, phone, email }. Nomor telepon disimpan dalam format 62xxxxxxxx
+// @Accept json
+// @Tags   pelanggan
+// @Produce json
+// @Param customer body models.Customer true "New Customer Data"
+// @Success 201 {object} models.Customer
+// @Failure 400 {object} apperror.Response "Invalid request body"
+// @Failure 409 {object} apperror.Response "Phone already used by another customer"
+// @Failure 500 {object} apperror.Response "Failed to create customer"
+// @Router /api/pelanggan [post]
+func (h *CustomerHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var customer models.Customer
+	err := json.NewDecoder(r.Body).Decode(&customer)
+	if err != nil {
+		apperror.Write(w, errInvalidBody)
+		return
+	}
+
+	err = h.service.Create(&customer, middlewares.Audit(r))
+	if err != nil {
+		apperror.Write(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(customer)
+}
+
+func (h *CustomerHandler) GetByID(w http.ResponseWriter, r *http.Request, id int) {
+	customer, err := h.service.GetByID(id)
+	if err != nil {
+		apperror.Write(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(customer)
+}
+
+// PUT /api/pelanggan/{id}
+// @Summary Update Customer by ID
+// @Description Memperbarui data pelanggan berdasarkan ID, data yang dapat diubah: { name, phone, email }
+// @Accept json
+// @Tags   pelanggan
+// @Produce json
+// @Param id path int true "Customer ID"
+// @Param customer body models.Customer true "Updated Customer Data"
+// @Success 200 {object} models.Customer
+// @Failure 400 {object} apperror.Response "Invalid request body"
+// @Failure 409 {object} apperror.Response "Phone already used by another customer"
+// @Failure 500 {object} apperror.Response "Failed to update customer"
+// @Router /api/pelanggan/{id} [put]
+func (h *CustomerHandler) Update(w http.ResponseWriter, r *http.Request, id int) {
+	var customer models.Customer
+	err := json.NewDecoder(r.Body).Decode(&customer)
+	if err != nil {
+		apperror.Write(w, errInvalidBody)
+		return
+	}
+
+	customer.ID = id
+	err = h.service.Update(&customer, middlewares.Audit(r))
+	if err != nil {
+		apperror.Write(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(customer)
+}
+
+// GET /api/pelanggan/{id}/transaksi
+// @Summary      Get Customer Purchase History
+// @Description  Mengambil riwayat belanja pelanggan, transaksi terbaru lebih dulu
+// @Tags         pelanggan
+// @Produce      json
+// @Param        id       path      int   true   "Customer ID"
+// @Success      200      {array}   models.Transaction
+// @Failure      404      {object}  apperror.Response "Customer not found"
+// @Router       /api/pelanggan/{id}/transaksi [get]
+func (h *CustomerHandler) GetTransactions(w http.ResponseWriter, r *http.Request, id int) {
+	transactions, err := h.service.GetTransactions(id)
+	if err != nil {
+		apperror.Write(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(transactions)
+}
+
+// GET /api/pelanggan/{id}/poin
+// @Summary      Get Customer Points
+// @Description  Mengambil saldo poin pelanggan beserta buku poin (earn, redeem, expire). Poin yang kedaluwarsa dicatat saat saldo dihitung
+// @Tags         pelanggan
+// @Produce      json
+// @Param        id       path      int   true   "Customer ID"
+// @Success      200      {object}  models.LoyaltyBalance
+// @Failure      404      {object}  apperror.Response "Customer not found"
+// @Router       /api/pelanggan/{id}/poin [get]
+func (h *CustomerHandler) GetPoints(w http.ResponseWriter, r *http.Request, id int) {
+	points, err := h.service.GetPoints(id)
+	if err != nil {
+		apperror.Write(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(points)
+}
@@ -0,0 +1,10 @@
+package handlers
+
+import "kasir-api/apperror"
+
+// Error yang dipakai bersama oleh beberapa handler; error lain datang dari service dan repository.
+var (
+	errInvalidBody   = apperror.BadRequest("Invalid request body")
+	errRouteNotFound = apperror.NotFound("404 page not found")
+	errOwnerRequired = apperror.Forbidden("Owner access required")
+)
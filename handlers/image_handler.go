@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"io"
+	"kasir-api/apperror"
+	"kasir-api/images"
+	"kasir-api/services"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+)
+
+type ImageHandler struct {
+	service *services.ProductImageService
+}
+
+func NewImageHandler(service *services.ProductImageService) *ImageHandler {
+	return &ImageHandler{service: service}
+}
+
+// GET /api/gambar/{key}
+// @Summary Get Image
+// @Description Mengambil file foto produk atau thumbnail-nya dari URL pada field image produk. Tidak memerlukan API key. Setiap unggahan mendapat URL baru, sehingga file boleh di-cache selamanya oleh browser dan CDN
+// @Tags   produk
+// @Produce image/jpeg
+// @Produce image/png
+// @Produce image/webp
+// @Param key path string true "Key file, contoh produk/12/3f9a1c2b-md.jpg"
+// @Success 200 {file} file
+// @Success 304 {string} string "Not Modified"
+// @Failure 404 {object} apperror.Response "File not found"
+// @Router /api/gambar/{key} [get]
+func (h *ImageHandler) Serve(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		apperror.Write(w, apperror.MethodNotAllowed)
+		return
+	}
+
+	key := strings.TrimPrefix(r.URL.Path, images.URLPrefix)
+	object, err := h.service.Open(key)
+	if err != nil {
+		apperror.Write(w, err)
+		return
+	}
+	defer object.Body.Close()
+
+	w.Header().Set("Content-Type", object.ContentType)
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("ETag", `"`+strings.TrimSuffix(path.Base(key), path.Ext(key))+`"`)
+
+	// ServeContent menangani If-None-Match, If-Modified-Since, Range dan HEAD
+	if content, ok := object.Body.(io.ReadSeeker); ok {
+		http.ServeContent(w, r, "", object.ModTime, content)
+		return
+	}
+
+	if match := r.Header.Get("If-None-Match"); match != "" && match == w.Header().Get("ETag") {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Length", strconv.FormatInt(object.Size, 10))
+	if r.Method == http.MethodHead {
+		return
+	}
+	io.Copy(w, object.Body)
+}
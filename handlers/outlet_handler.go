@@ -0,0 +1,238 @@
+package handlers
+
+import (
+	"encoding/json"
+	"kasir-api/apperror"
+	"kasir-api/middlewares"
+	"kasir-api/models"
+	"kasir-api/services"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type OutletHandler struct {
+	service *services.OutletService
+}
+
+func NewOutletHandler(service *services.OutletService) *OutletHandler {
+	return &OutletHandler{service: service}
+}
+
+// GET /api/outlet
+// @Summary      Get All Outlets
+// @Description  Mengambil semua outlet/cabang
+// @Tags         outlet
+// @Produce      json
+// @Success      200      {array}   models.Outlet
+// @Failure      500      {object}  apperror.Response "Failed to get outlets"
+// @Router       /api/outlet [get]
+func (h *OutletHandler) HandleOutlets(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetAll(w, r)
+	case http.MethodPost:
+		h.Create(w, r)
+	default:
+		apperror.Write(w, apperror.MethodNotAllowed)
+	}
+}
+
+// GET /api/outlet/{id}
+// @Summary      Get Outlet by ID
+// @Description  Mengambil data outlet berdasarkan ID
+// @Tags         outlet
+// @Produce      json
+// @Param        id       path      int   true   "Outlet ID"
+// @Success      200      {object}  models.Outlet
+// @Failure      400      {object}  apperror.Response "Invalid outlet ID"
+// @Failure      404      {object}  apperror.Response "Outlet not found"
+// @Router       /api/outlet/{id} [get]
+func (h *OutletHandler) HandleOutletByID(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/outlet/"), "/")
+	idStr, action, _ := strings.Cut(path, "/")
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		apperror.Write(w, apperror.BadRequest("Invalid outlet ID"))
+		return
+	}
+
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		h.GetByID(w, r, id)
+	case action == "" && r.Method == http.MethodPut:
+		h.Update(w, r, id)
+	case action == "produk" && r.Method == http.MethodGet:
+		h.GetProducts(w, r, id)
+	case strings.HasPrefix(action, "produk/") && r.Method == http.MethodPut:
+		productID, err := strconv.Atoi(strings.TrimPrefix(action, "produk/"))
+		if err != nil {
+			apperror.Write(w, apperror.BadRequest("Invalid product ID"))
+			return
+		}
+		h.SetProduct(w, r, id, productID)
+	case action == "" || action == "produk" || strings.HasPrefix(action, "produk/"):
+		apperror.Write(w, apperror.MethodNotAllowed)
+	default:
+		apperror.Write(w, errRouteNotFound)
+	}
+}
+
+func (h *OutletHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	outlets, err := h.service.GetAll()
+	if err != nil {
+		apperror.Write(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(outlets)
+}
+
+// POST /api/outlet
+// @Summary Create Outlet
+// @Description Menambahkan outlet baru: { code, name, address, phone }. Khusus pemilik (owner)
+// @Accept json
+// @Tags   outlet
+// @Produce json
+// @Param outlet body models.Outlet true "New Outlet Data"
+// @Success 201 {object} models.Outlet
+// @Failure 400 {object} apperror.Response "Invalid request body"
+// @Failure 403 {object} apperror.Response "Owner access required"
+// @Failure 500 {object} apperror.Response "Failed to create outlet"
+// @Router /api/outlet [post]
+func (h *OutletHandler) Create(w http.ResponseWriter, r *http.Request) {
+	if !requireOwner(w, r) {
+		return
+	}
+
+	var outlet models.Outlet
+	err := json.NewDecoder(r.Body).Decode(&outlet)
+	if err != nil {
+		apperror.Write(w, errInvalidBody)
+		return
+	}
+
+	err = h.service.Create(&outlet, middlewares.Audit(r))
+	if err != nil {
+		apperror.Write(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(outlet)
+}
+
+func (h *OutletHandler) GetByID(w http.ResponseWriter, r *http.Request, id int) {
+	outlet, err := h.service.GetByID(id)
+	if err != nil {
+		apperror.Write(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(outlet)
+}
+
+// PUT /api/outlet/{id}
+// @Summary Update Outlet by ID
+// @Description Memperbarui data outlet: { code, name, address, phone }. Khusus pemilik (owner)
+// @Accept json
+// @Tags   outlet
+// @Produce json
+// @Param id path int true "Outlet ID"
+// @Param outlet body models.Outlet true "Updated Outlet Data"
+// @Success 200 {object} models.Outlet
+// @Failure 400 {object} apperror.Response "Invalid request body"
+// @Failure 403 {object} apperror.Response "Owner access required"
+// @Failure 500 {object} apperror.Response "Failed to update outlet"
+// @Router /api/outlet/{id} [put]
+func (h *OutletHandler) Update(w http.ResponseWriter, r *http.Request, id int) {
+	if !requireOwner(w, r) {
+		return
+	}
+
+	var outlet models.Outlet
+	err := json.NewDecoder(r.Body).Decode(&outlet)
+	if err != nil {
+		apperror.Write(w, errInvalidBody)
+		return
+	}
+
+	outlet.ID = id
+	err = h.service.Update(&outlet, middlewares.Audit(r))
+	if err != nil {
+		apperror.Write(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(outlet)
+}
+
+// GET /api/outlet/{id}/produk
+// @Summary      Get Outlet Products
+// @Description  Mengambil stok dan harga seluruh produk katalog di outlet. price_override null berarti outlet memakai harga katalog
+// @Tags         outlet
+// @Produce      json
+// @Param        id       path      int   true   "Outlet ID"
+// @Success      200      {array}   models.OutletProduct
+// @Failure      404      {object}  apperror.Response "Outlet not found"
+// @Router       /api/outlet/{id}/produk [get]
+func (h *OutletHandler) GetProducts(w http.ResponseWriter, r *http.Request, id int) {
+	products, err := h.service.GetProducts(id)
+	if err != nil {
+		apperror.Write(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(products)
+}
+
+// PUT /api/outlet/{id}/produk/{product_id}
+// @Summary Set Outlet Product Stock and Price
+// @Description Mengatur stok dan harga khusus produk di outlet: { stock, price_override }. stock kosong mempertahankan stok, price_override null kembali ke harga katalog. Khusus pemilik (owner)
+// @Accept json
+// @Tags   outlet
+// @Produce json
+// @Param id path int true "Outlet ID"
+// @Param product_id path int true "Product ID"
+// @Param product body models.OutletProductUpdate true "Stok dan harga khusus"
+// @Success 200 {array} models.OutletProduct
+// @Failure 400 {object} apperror.Response "Invalid request body"
+// @Failure 403 {object} apperror.Response "Owner access required"
+// @Failure 500 {object} apperror.Response "Failed to update outlet product"
+// @Router /api/outlet/{id}/produk/{product_id} [put]
+func (h *OutletHandler) SetProduct(w http.ResponseWriter, r *http.Request, id int, productID int) {
+	if !requireOwner(w, r) {
+		return
+	}
+
+	var update models.OutletProductUpdate
+	err := json.NewDecoder(r.Body).Decode(&update)
+	if err != nil {
+		apperror.Write(w, errInvalidBody)
+		return
+	}
+
+	err = h.service.SetProduct(id, productID, update, middlewares.Audit(r))
+	if err != nil {
+		apperror.Write(w, err)
+		return
+	}
+
+	h.GetProducts(w, r, id)
+}
+
+// requireOwner menolak request selain dari pemilik. Mengembalikan false jika respons sudah ditulis.
+func requireOwner(w http.ResponseWriter, r *http.Request) bool {
+	user := middlewares.CurrentUser(r)
+	if user == nil || !user.IsOwner() {
+		apperror.Write(w, errOwnerRequired)
+		return false
+	}
+	return true
+}
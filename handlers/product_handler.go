@@ -2,19 +2,33 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"io"
+	"kasir-api/apperror"
+	"kasir-api/exports"
+	"kasir-api/images"
+	"kasir-api/imports"
+	"kasir-api/middlewares"
 	"kasir-api/models"
+	"kasir-api/patch"
 	"kasir-api/services"
+	"log"
+	"mime"
 	"net/http"
 	"strconv"
 	"strings"
 )
 
+var errIfMatchRequired = apperror.New(apperror.CodePreconditionRequired, "If-Match header required")
+
 type ProductHandler struct {
-	service *services.ProductService
+	service      *services.ProductService
+	priceService *services.PriceService
+	imageService *services.ProductImageService
 }
 
-func NewProductHandler(service *services.ProductService) *ProductHandler {
-	return &ProductHandler{service: service}
+func NewProductHandler(service *services.ProductService, priceService *services.PriceService, imageService *services.ProductImageService) *ProductHandler {
+	return &ProductHandler{service: service, priceService: priceService, imageService: imageService}
 }
 
 // GET /api/kategori
@@ -25,18 +39,64 @@ func NewProductHandler(service *services.ProductService) *ProductHandler {
 // @Tags         produk
 // @Produce      json
 // @Success      200  {array}   models.Categories
-// @Failure      500  {string}  string "Failed to get categories"
+// @Failure      500  {object}  apperror.Response "Failed to get categories"
 // @Router       /api/kategori [get]
+func (h *ProductHandler) HandleCategories(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetCategories(w, r)
+	case http.MethodPost:
+		h.CreateCategory(w, r)
+	default:
+		apperror.Write(w, apperror.MethodNotAllowed)
+	}
+}
+
 func (h *ProductHandler) GetCategories(w http.ResponseWriter, r *http.Request) {
 	categories, err := h.service.GetCategories()
 	if err != nil {
-		http.Error(w, "Failed to get categories", http.StatusInternalServerError)
+		apperror.Write(w, err)
 		return
 	}
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(categories)
 }
 
+// POST /api/kategori
+// @Summary      Create Category
+// @Description  Menambahkan kategori produk baru, data yang perlu diisi: { name }. Khusus pemilik (owner)
+// @Accept       json
+// @Tags         category
+// @Produce      json
+// @Param        category  body      models.Categories  true  "New Category Data"
+// @Success      201       {object}  models.Categories
+// @Failure      400       {object}  apperror.Response "Invalid request body"
+// @Failure      401       {object}  apperror.Response "API Key Required"
+// @Failure      403       {object}  apperror.Response "Owner access required"
+// @Failure      422       {object}  apperror.Response "Validation failed"
+// @Failure      500       {object}  apperror.Response "Failed to create category"
+// @Router       /api/kategori [post]
+func (h *ProductHandler) CreateCategory(w http.ResponseWriter, r *http.Request) {
+	if !requireOwner(w, r) {
+		return
+	}
+	var category models.Categories
+	if err := json.NewDecoder(r.Body).Decode(&category); err != nil {
+		apperror.Write(w, errInvalidBody)
+		return
+	}
+
+	err := h.service.CreateCategory(&category, middlewares.Audit(r))
+	if err != nil {
+		apperror.Write(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(category)
+}
+
 // GET /api/produk
 // @Summary      Get All Products
 // @Description  Mengambil semua data produk. Terdapat opsi untuk mendapatkan detail kategori produk
@@ -45,8 +105,9 @@ func (h *ProductHandler) GetCategories(w http.ResponseWriter, r *http.Request) {
 // @Produce      json
 // @Param        details  query     bool  false  "Tampilkan Detail Kategori Produk"
 // @Param        name  	query     string false  "Tampilkan Detail Kategori Produk Berdasarkan Pencarian Nama"
+// @Param        outlet_id  query   int    false  "ID outlet untuk stok dan harga (default outlet bawaan)"
 // @Success      200      {array}   models.Product
-// @Failure      500      {string}  string "Failed to get products"
+// @Failure      500      {object}  apperror.Response "Failed to get products"
 // @Router       /api/produk [get]
 func (h *ProductHandler) HandleProducts(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
@@ -61,24 +122,89 @@ func (h *ProductHandler) HandleProducts(w http.ResponseWriter, r *http.Request)
 	case http.MethodPost:
 		h.Create(w, r)
 	default:
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		apperror.Write(w, apperror.MethodNotAllowed)
 	}
 }
 
 // GET /api/produk/{id}
 // GetByID
 // @Summary      Get Product by ID
-// @Description  Mengambil data produk berdasarkan ID. Terdapat opsi untuk mendapatkan detail kategori produk
+// @Description  Mengambil data produk berdasarkan ID. Terdapat opsi untuk mendapatkan detail kategori produk. Respons berisi header ETag untuk If-Match pada PUT/DELETE; If-None-Match dengan ETag yang sama menghasilkan 304
 // @Accept       json
 // @Tags         produk
 // @Produce      json
 // @Param        id       path      int   true   "Product ID"
 // @Param        details  query     bool  false  "Tampilkan Detail Kategori Produk" default(false)
+// @Param        If-None-Match  header  string  false  "ETag dari respons sebelumnya"
 // @Success      200      {object}  models.Product
-// @Failure      400      {string}  string "Invalid product ID"
-// @Failure      404      {string}  string "Product not found"
+// @Success      304      {string}  string "Not Modified"
+// @Failure      400      {object}  apperror.Response "Invalid product ID"
+// @Failure      404      {object}  apperror.Response "Product not found"
 // @Router       /api/produk/{id} [get]
 func (h *ProductHandler) HandleProductByID(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/produk/"), "/")
+	if path == "terhapus" {
+		if r.Method != http.MethodGet {
+			apperror.Write(w, apperror.MethodNotAllowed)
+			return
+		}
+		h.GetDeleted(w, r)
+		return
+	}
+	if path == "katalog" {
+		if r.Method != http.MethodGet {
+			apperror.Write(w, apperror.MethodNotAllowed)
+			return
+		}
+		h.ExportCatalog(w, r)
+		return
+	}
+	if path == "impor" || path == "massal" {
+		if r.Method != http.MethodPost {
+			apperror.Write(w, apperror.MethodNotAllowed)
+			return
+		}
+		if path == "impor" {
+			h.Import(w, r)
+		} else {
+			h.Bulk(w, r)
+		}
+		return
+	}
+
+	if idStr, action, found := strings.Cut(path, "/"); found {
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			apperror.Write(w, apperror.BadRequest("Invalid product ID"))
+			return
+		}
+
+		switch {
+		case action == "pulihkan" && r.Method == http.MethodPost:
+			h.Restore(w, r, id)
+		case action == "harga" && r.Method == http.MethodGet:
+			h.GetPriceHistory(w, r, id)
+		case action == "harga" && r.Method == http.MethodPost:
+			h.SchedulePrice(w, r, id)
+		case strings.HasPrefix(action, "harga/") && r.Method == http.MethodDelete:
+			priceID, err := strconv.Atoi(strings.TrimPrefix(action, "harga/"))
+			if err != nil {
+				apperror.Write(w, apperror.BadRequest("Invalid price ID"))
+				return
+			}
+			h.CancelPrice(w, r, id, priceID)
+		case action == "gambar" && r.Method == http.MethodPost:
+			h.UploadImage(w, r, id)
+		case action == "gambar" && r.Method == http.MethodDelete:
+			h.DeleteImage(w, r, id)
+		case action == "pulihkan" || action == "harga" || action == "gambar" || strings.HasPrefix(action, "harga/"):
+			apperror.Write(w, apperror.MethodNotAllowed)
+		default:
+			apperror.Write(w, errRouteNotFound)
+		}
+		return
+	}
+
 	switch r.Method {
 	case http.MethodGet:
 		query := r.URL.Query()
@@ -90,10 +216,12 @@ func (h *ProductHandler) HandleProductByID(w http.ResponseWriter, r *http.Reques
 		}
 	case http.MethodPut:
 		h.Update(w, r)
+	case http.MethodPatch:
+		h.Patch(w, r)
 	case http.MethodDelete:
 		h.Delete(w, r)
 	default:
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		apperror.Write(w, apperror.MethodNotAllowed)
 	}
 }
 
@@ -103,13 +231,13 @@ func (h *ProductHandler) GetAll(w http.ResponseWriter, r *http.Request, details
 
 	name := r.URL.Query().Get("name")
 	if details {
-		products, err = h.service.GetAllDetails(name)
+		products, err = h.service.GetAllDetails(middlewares.OutletID(r), name)
 	} else {
-		products, err = h.service.GetAll(name)
+		products, err = h.service.GetAll(middlewares.OutletID(r), name)
 	}
 
 	if err != nil {
-		http.Error(w, "Failed to get products", http.StatusInternalServerError)
+		apperror.Write(w, err)
 		return
 	}
 
@@ -119,29 +247,37 @@ func (h *ProductHandler) GetAll(w http.ResponseWriter, r *http.Request, details
 
 // POST /api/produk
 // @Summary Create New Product
-// @Description Menambahkan data produk baru, data yang perlu diisi: { category_id, name, price, stock }
+// @Description Menambahkan data produk baru, data yang perlu diisi: { category_id, name, price, stock }, sku opsional. Produk masuk ke katalog bersama, price adalah harga katalog dan stock dicatat di outlet yang dipilih (X-Outlet-ID). unit adalah satuan dasar (pcs, kg, liter atau box, default pcs); price dan stock dalam satuan dasar, pecahan hanya untuk kg dan liter. units opsional berisi satuan jual lain, contoh [{ "unit": "box", "factor": 12, "price": 100000 }]; tanpa price harganya price dikali factor. type opsional: standard (default), bundle (paket dengan harga sendiri) atau recipe (resep); bundle dan recipe wajib mengisi components, contoh [{ "product_id": 3, "quantity": 0.25 }], dengan quantity dalam satuan dasar komponen per satu satuan dasar produk. Komponen harus produk standard. Stok paket dan resep tidak diisi, melainkan dihitung dari stok komponen dan stok komponen berkurang saat checkout. modifier_groups opsional berisi kelompok pilihan tambahan, contoh [{ "name": "Susu", "min": 0, "max": 1, "modifiers": [{ "name": "Oat", "price": 5000 }] }]; pembeli memilih min sampai max modifier per kelompok dan price ditambahkan ke harga per satuan jual. Khusus pemilik (owner)
 // @Accept json
 // @Tags   produk
 // @Produce json
 // @Param product body models.Product true "New Product Data"
 // @Success 201 {object} models.Product
-// @Failure 400 {string} string "Invalid request body"
-// @Failure 500 {string} string "Failed to create product"
+// @Failure 400 {object} apperror.Response "Invalid request body"
+// @Failure 401 {object} apperror.Response "API Key Required"
+// @Failure 403 {object} apperror.Response "Owner access required"
+// @Failure 422 {object} apperror.Response "Validation failed"
+// @Failure 500 {object} apperror.Response "Failed to create product"
 // @Router /api/produk [post]
 func (h *ProductHandler) Create(w http.ResponseWriter, r *http.Request) {
+	if !requireOwner(w, r) {
+		return
+	}
 	var product models.Product
 	err := json.NewDecoder(r.Body).Decode(&product)
 	if err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		apperror.Write(w, errInvalidBody)
+		return
 	}
 
-	err = h.service.Create(&product)
+	err = h.service.Create(middlewares.OutletID(r), &product, middlewares.Audit(r))
 
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		apperror.Write(w, err)
 		return
 	}
 
+	w.Header().Set("ETag", product.ETag())
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(product)
@@ -152,20 +288,27 @@ func (h *ProductHandler) GetByID(w http.ResponseWriter, r *http.Request, details
 	id, err := strconv.Atoi(idStr)
 
 	if err != nil {
-		http.Error(w, "Invalid product ID", http.StatusBadRequest)
+		apperror.Write(w, apperror.BadRequest("Invalid product ID"))
 		return
 	}
 
 	var product *models.Product
 
 	if details {
-		product, err = h.service.GetDetailsByID(id)
+		product, err = h.service.GetDetailsByID(middlewares.OutletID(r), id)
 	} else {
-		product, err = h.service.GetByID(id)
+		product, err = h.service.GetByID(middlewares.OutletID(r), id)
 	}
 
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusNotFound)
+		apperror.Write(w, err)
+		return
+	}
+
+	etag := product.ETag()
+	w.Header().Set("ETag", etag)
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" && models.ETagMatches(ifNoneMatch, etag) {
+		w.WriteHeader(http.StatusNotModified)
 		return
 	}
 
@@ -179,12 +322,12 @@ func (h *ProductHandler) GetDetailsByID(w http.ResponseWriter, r *http.Request)
 
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		http.Error(w, "Invalid product ID", http.StatusBadRequest)
+		apperror.Write(w, apperror.BadRequest("Invalid product ID"))
 		return
 	}
-	product, err := h.service.GetDetailsByID(id)
+	product, err := h.service.GetDetailsByID(middlewares.OutletID(r), id)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusNotFound)
+		apperror.Write(w, err)
 		return
 	}
 	w.Header().Set("Content-Type", "application/json")
@@ -193,22 +336,36 @@ func (h *ProductHandler) GetDetailsByID(w http.ResponseWriter, r *http.Request)
 
 // PUT /api/produk/{id}
 // @Summary Update Product by ID
-// @Description Memperbarui data produk berdasarkan ID, data yang dapat diubah: { category_id, name, price, stock }
+// @Description Memperbarui data produk berdasarkan ID, data yang dapat diubah: { sku, category_id, name, price, stock, unit, units, type, components, modifier_groups }. unit atau type kosong serta units dan modifier_groups yang tidak dikirim tidak diubah; components yang tidak dikirim tetap untuk paket dan resep. Kelompok dan modifier yang membawa id diperbarui dengan id tetap, yang tanpa id ditambahkan dan yang tidak dikirim dihapus. stock diabaikan untuk paket dan resep. price mengubah harga katalog, stock mengubah stok di outlet yang dipilih (X-Outlet-ID). Wajib mengirim If-Match berisi ETag dari GET /api/produk/{id}; jika produk sudah diubah pihak lain, respons 412. Khusus pemilik (owner)
 // @Accept json
 // @Tags   produk
 // @Produce json
 // @Param id path int true "Product ID"
+// @Param If-Match header string true "ETag produk yang sedang diedit"
 // @Param product body models.Product true "Updated Product Data"
 // @Success 200 {object} models.Product
-// @Failure 400 {string} string "Invalid request body"
-// @Failure 500 {string} string "Failed to update product"
+// @Failure 400 {object} apperror.Response "Invalid request body"
+// @Failure 403 {object} apperror.Response "Owner access required"
+// @Failure 412 {object} apperror.Response "Product has been modified"
+// @Failure 422 {object} apperror.Response "Validation failed"
+// @Failure 428 {object} apperror.Response "If-Match header required"
+// @Failure 500 {object} apperror.Response "Failed to update product"
 // @Router /api/produk/{id} [put]
 func (h *ProductHandler) Update(w http.ResponseWriter, r *http.Request) {
+	if !requireOwner(w, r) {
+		return
+	}
 	idStr := strings.TrimPrefix(r.URL.Path, "/api/produk/")
 	id, err := strconv.Atoi(idStr)
 
 	if err != nil {
-		http.Error(w, "Invalid product ID", http.StatusBadRequest)
+		apperror.Write(w, apperror.BadRequest("Invalid product ID"))
+		return
+	}
+
+	ifMatch := r.Header.Get("If-Match")
+	if ifMatch == "" {
+		apperror.Write(w, errIfMatchRequired)
 		return
 	}
 
@@ -216,43 +373,119 @@ func (h *ProductHandler) Update(w http.ResponseWriter, r *http.Request) {
 	err = json.NewDecoder(r.Body).Decode(&product)
 
 	if err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		apperror.Write(w, errInvalidBody)
 		return
 	}
 
 	product.ID = id
-	err = h.service.Update(&product)
+	err = h.service.Update(middlewares.OutletID(r), &product, ifMatch, middlewares.Audit(r))
+
+	if err != nil {
+		apperror.Write(w, err)
+		return
+	}
+
+	w.Header().Set("ETag", product.ETag())
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(product)
+}
+
+// PATCH /api/produk/{id}
+// @Summary Patch Product by ID
+// @Description Mengubah sebagian data produk: hanya field yang dikirim yang berubah. Content-Type application/merge-patch+json (atau application/json) untuk JSON Merge Patch, contoh { "price": 12000 }, dan application/json-patch+json untuk JSON Patch, contoh [{ "op": "replace", "path": "/stock", "value": 5 }]. Field yang bisa diubah: sku, name, price, stock, unit, units, type, components, modifier_groups, category_id (null untuk menghapus SKU, units, modifier_groups atau kategori). If-Match opsional; jika dikirim dan produk sudah diubah pihak lain, respons 412. Khusus pemilik (owner)
+// @Accept json
+// @Tags   produk
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param If-Match header string false "ETag produk yang sedang diedit"
+// @Param patch body object true "Merge patch atau daftar operasi JSON Patch"
+// @Success 200 {object} models.Product
+// @Failure 400 {object} apperror.Response "Invalid patch"
+// @Failure 403 {object} apperror.Response "Owner access required"
+// @Failure 404 {object} apperror.Response "Product not found"
+// @Failure 409 {object} apperror.Response "Patch test failed"
+// @Failure 412 {object} apperror.Response "Product has been modified"
+// @Failure 415 {object} apperror.Response "Unsupported Content-Type"
+// @Failure 422 {object} apperror.Response "Validation failed"
+// @Router /api/produk/{id} [patch]
+func (h *ProductHandler) Patch(w http.ResponseWriter, r *http.Request) {
+	if !requireOwner(w, r) {
+		return
+	}
+	idStr := strings.TrimPrefix(r.URL.Path, "/api/produk/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		apperror.Write(w, apperror.BadRequest("Invalid product ID"))
+		return
+	}
+
+	contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch contentType {
+	case "", "application/json":
+		contentType = patch.ContentTypeMergePatch
+	case patch.ContentTypeMergePatch, patch.ContentTypeJSONPatch:
+	default:
+		apperror.Write(w, apperror.New(apperror.CodeUnsupportedMediaType, "Unsupported Content-Type, use application/merge-patch+json or application/json-patch+json"))
+		return
+	}
 
+	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		apperror.Write(w, errInvalidBody)
 		return
 	}
 
+	ifMatch := r.Header.Get("If-Match")
+	if ifMatch == "" {
+		ifMatch = "*"
+	}
+
+	product, err := h.service.Patch(middlewares.OutletID(r), id, contentType, body, ifMatch, middlewares.Audit(r))
+	if err != nil {
+		apperror.Write(w, err)
+		return
+	}
+
+	w.Header().Set("ETag", product.ETag())
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(product)
 }
 
 // DELETE /api/produk/{id}
 // @Summary Delete Product by ID
-// @Description Menghapus data produk berdasarkan ID
+// @Description Menghapus produk berdasarkan ID (soft delete). Produk hilang dari katalog dan checkout, tetapi tetap tercatat di transaksi dan laporan lama, dan bisa dipulihkan lewat /api/produk/{id}/pulihkan. Wajib mengirim If-Match berisi ETag produk. Khusus pemilik (owner)
 // @Param id path int true "Product ID"
+// @Param If-Match header string true "ETag produk yang akan dihapus"
 // @Tags   produk
 // @Success 200 {object} map[string]string
-// @Failure 400 {string} string "Invalid product ID"
-// @Failure 500 {string} string "Failed to delete product"
+// @Failure 400 {object} apperror.Response "Invalid product ID"
+// @Failure 403 {object} apperror.Response "Owner access required"
+// @Failure 412 {object} apperror.Response "Product has been modified"
+// @Failure 428 {object} apperror.Response "If-Match header required"
+// @Failure 500 {object} apperror.Response "Failed to delete product"
 // @Router /api/produk/{id} [delete]
 func (h *ProductHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	if !requireOwner(w, r) {
+		return
+	}
 	idStr := strings.TrimPrefix(r.URL.Path, "/api/produk/")
 	id, err := strconv.Atoi(idStr)
 
 	if err != nil {
-		http.Error(w, "Invalid product ID", http.StatusBadRequest)
+		apperror.Write(w, apperror.BadRequest("Invalid product ID"))
+		return
+	}
+
+	ifMatch := r.Header.Get("If-Match")
+	if ifMatch == "" {
+		apperror.Write(w, errIfMatchRequired)
+		return
 	}
 
-	err = h.service.Delete(id)
+	err = h.service.Delete(middlewares.OutletID(r), id, ifMatch, middlewares.Audit(r))
 
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		apperror.Write(w, err)
 		return
 	}
 
@@ -261,3 +494,360 @@ func (h *ProductHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		"message": "Product deleted successfully",
 	})
 }
+
+// maxImportSize membatasi ukuran file impor produk
+const maxImportSize = 10 << 20
+
+// POST /api/produk/impor
+// @Summary Import Products
+// @Description Impor produk massal dari CSV (pemisah koma atau titik koma) atau XLSX (sheet pertama). Baris pertama berisi judul kolom: sku, name/nama, price/harga wajib; stock/stok (dalam satuan dasar produk, titik atau koma sebagai pemisah desimal tanpa pemisah ribuan) dan category/kategori opsional. Judul lain bisa dipetakan lewat columns, contoh {"Kode Barang":"sku","Harga Jual":"price"}. Produk dengan SKU yang sama diperbarui, selain itu dibuat baru; kategori dicari berdasarkan nama dan dibuat jika belum ada. Stok dicatat di outlet yang dipilih (X-Outlet-ID). dry_run=true hanya memvalidasi dan melaporkan per baris tanpa menyimpan. Impor disimpan per 100 baris dalam satu transaksi. Khusus pemilik (owner)
+// @Accept multipart/form-data
+// @Accept text/csv
+// @Accept application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Tags   produk
+// @Produce json
+// @Param file    formData file   false "File CSV atau XLSX (multipart)"
+// @Param columns formData string false "Pemetaan judul kolom ke field dalam JSON (untuk body langsung pakai query columns)"
+// @Param dry_run query    bool   false "Validasi saja tanpa menyimpan" default(false)
+// @Success 200 {object} models.ProductImportReport
+// @Failure 400 {object} apperror.Response "Invalid import file"
+// @Failure 403 {object} apperror.Response "Owner access required"
+// @Router /api/produk/impor [post]
+func (h *ProductHandler) Import(w http.ResponseWriter, r *http.Request) {
+	if !requireOwner(w, r) {
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportSize)
+
+	// File dikirim sebagai multipart (field file) atau langsung sebagai body dengan Content-Type CSV/XLSX
+	var file io.Reader = r.Body
+	filename := ""
+	columnsJSON := r.URL.Query().Get("columns")
+	contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if contentType == "multipart/form-data" {
+		if err := r.ParseMultipartForm(maxImportSize); err != nil {
+			apperror.Write(w, apperror.BadRequest("Invalid multipart form, expected a file field"))
+			return
+		}
+		upload, header, err := r.FormFile("file")
+		if err != nil {
+			apperror.Write(w, apperror.BadRequest("Invalid multipart form, expected a file field"))
+			return
+		}
+		defer upload.Close()
+		file = upload
+		filename = header.Filename
+		contentType = header.Header.Get("Content-Type")
+		if value := r.FormValue("columns"); value != "" {
+			columnsJSON = value
+		}
+	}
+
+	var columns map[string]string
+	if columnsJSON != "" {
+		if err := json.Unmarshal([]byte(columnsJSON), &columns); err != nil {
+			apperror.Write(w, apperror.BadRequest("Invalid columns, expected a JSON object of column title to field"))
+			return
+		}
+	}
+
+	dryRun := r.URL.Query().Get("dry_run") == "true"
+	report, err := h.service.ImportProducts(middlewares.OutletID(r), file, imports.DetectFormat(filename, contentType), columns, dryRun, middlewares.Audit(r))
+	if err != nil {
+		apperror.Write(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// POST /api/produk/massal
+// @Summary Bulk Update Products
+// @Description Mengubah banyak produk sekaligus dalam satu transaksi. target memilih produk berdasarkan ids, category_id (0 untuk tanpa kategori) dan/atau name (kriteria digabung dengan AND), atau all: true untuk semua produk. operation.type: set_price, adjust_price_percent (contoh value 5 untuk +5%, dibulatkan ke rupiah), adjust_price_amount, set_stock, adjust_stock (paket dan resep dilewati karena stoknya mengikuti komponen) atau set_category. Harga yang diubah adalah harga katalog, stok adalah stok di outlet yang dipilih (X-Outlet-ID). Setiap perubahan dicatat di riwayat harga, buku stok dan audit log. preview=true menampilkan nilai sebelum dan sesudah tanpa menyimpan. Khusus pemilik (owner)
+// @Accept json
+// @Tags   produk
+// @Produce json
+// @Param request body  models.BulkProductRequest true  "Target dan operasi"
+// @Param preview query bool                      false "Hitung perubahan tanpa menyimpan" default(false)
+// @Success 200 {object} models.BulkProductResult
+// @Failure 400 {object} apperror.Response "Invalid request body"
+// @Failure 403 {object} apperror.Response "Owner access required"
+// @Failure 422 {object} apperror.Response "Validation failed"
+// @Router /api/produk/massal [post]
+func (h *ProductHandler) Bulk(w http.ResponseWriter, r *http.Request) {
+	if !requireOwner(w, r) {
+		return
+	}
+
+	var req models.BulkProductRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apperror.Write(w, errInvalidBody)
+		return
+	}
+
+	preview := r.URL.Query().Get("preview") == "true"
+	result, err := h.service.BulkUpdate(middlewares.OutletID(r), req, preview, middlewares.Audit(r))
+	if err != nil {
+		apperror.Write(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// GET /api/produk/katalog
+// @Summary Export Catalog
+// @Description Mengekspor katalog (kategori dan produk aktif) dengan harga dan stok outlet yang dipilih (X-Outlet-ID). Format JSON berisi kategori dan produk; CSV atau XLSX berisi satu baris per produk dengan judul kolom yang sama dengan impor produk, sehingga bisa diimpor kembali di server lain
+// @Tags   produk
+// @Produce json
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param format query string false "Format keluaran" Enums(json, csv, xlsx) default(json)
+// @Param lang   query string false "Bahasa judul kolom" Enums(id, en) default(id)
+// @Success 200 {object} models.Catalog
+// @Failure 500 {object} apperror.Response "Failed to export catalog"
+// @Router /api/produk/katalog [get]
+func (h *ProductHandler) ExportCatalog(w http.ResponseWriter, r *http.Request) {
+	catalog, err := h.service.ExportCatalog(middlewares.OutletID(r))
+	if err != nil {
+		apperror.Write(w, err)
+		return
+	}
+
+	format := exports.Negotiate(r)
+	if format == exports.FormatJSON {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Disposition", `attachment; filename="katalog.json"`)
+		json.NewEncoder(w).Encode(catalog)
+		return
+	}
+
+	writer, err := exports.NewWriter(w, format, "katalog", "Katalog", exports.CatalogColumns, exports.Language(r))
+	if err != nil {
+		apperror.Write(w, err)
+		return
+	}
+	if err := exports.WriteCatalog(writer, catalog); err != nil {
+		log.Println("Export katalog terhenti:", err)
+		return
+	}
+	if err := writer.Close(); err != nil {
+		log.Println("Export katalog gagal ditutup:", err)
+	}
+}
+
+// POST /api/produk/{id}/gambar
+// @Summary Upload Product Image
+// @Description Mengunggah foto produk lewat multipart (field image) atau langsung sebagai body. Hanya JPEG, PNG atau WebP, maksimal 5 MB; jenis file diperiksa dari isinya. Thumbnail sm (160px), md (320px) dan lg (640px) dibuat otomatis. Foto lama diganti. Khusus pemilik (owner)
+// @Accept mpfd
+// @Tags   produk
+// @Produce json
+// @Param id    path     int  true "Product ID"
+// @Param image formData file true "Foto produk"
+// @Success 200 {object} models.Product
+// @Failure 400 {object} apperror.Response "Invalid image file"
+// @Failure 403 {object} apperror.Response "Owner access required"
+// @Failure 404 {object} apperror.Response "Product not found"
+// @Failure 413 {object} apperror.Response "Image too large"
+// @Failure 415 {object} apperror.Response "Unsupported image type"
+// @Router /api/produk/{id}/gambar [post]
+func (h *ProductHandler) UploadImage(w http.ResponseWriter, r *http.Request, id int) {
+	if !requireOwner(w, r) {
+		return
+	}
+	// Ruang tambahan untuk header multipart
+	r.Body = http.MaxBytesReader(w, r.Body, images.MaxSize+64<<10)
+
+	var file io.Reader = r.Body
+	contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if contentType == "multipart/form-data" {
+		upload, _, err := r.FormFile("image")
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				apperror.Write(w, images.ErrTooLarge)
+				return
+			}
+			apperror.Write(w, apperror.BadRequest("Invalid multipart form, expected an image field"))
+			return
+		}
+		defer upload.Close()
+		file = upload
+	}
+
+	product, err := h.imageService.Upload(middlewares.OutletID(r), id, file, middlewares.Audit(r))
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		err = images.ErrTooLarge
+	}
+	if err != nil {
+		apperror.Write(w, err)
+		return
+	}
+
+	w.Header().Set("ETag", product.ETag())
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(product)
+}
+
+// DELETE /api/produk/{id}/gambar
+// @Summary Delete Product Image
+// @Description Menghapus foto produk beserta thumbnail-nya. Khusus pemilik (owner)
+// @Tags   produk
+// @Produce json
+// @Param id path int true "Product ID"
+// @Success 200 {object} models.Product
+// @Failure 403 {object} apperror.Response "Owner access required"
+// @Failure 404 {object} apperror.Response "Product not found"
+// @Router /api/produk/{id}/gambar [delete]
+func (h *ProductHandler) DeleteImage(w http.ResponseWriter, r *http.Request, id int) {
+	if !requireOwner(w, r) {
+		return
+	}
+	product, err := h.imageService.Delete(middlewares.OutletID(r), id, middlewares.Audit(r))
+	if err != nil {
+		apperror.Write(w, err)
+		return
+	}
+
+	w.Header().Set("ETag", product.ETag())
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(product)
+}
+
+// GET /api/produk/terhapus
+// @Summary Get Deleted Products
+// @Description Mengambil produk yang sudah dihapus (soft delete), terbaru lebih dulu. Stok dan harga dari outlet yang dipilih (X-Outlet-ID). Khusus pemilik (owner)
+// @Tags   produk
+// @Produce json
+// @Success 200 {array} models.Product
+// @Failure 403 {object} apperror.Response "Owner access required"
+// @Failure 500 {object} apperror.Response "Failed to get deleted products"
+// @Router /api/produk/terhapus [get]
+func (h *ProductHandler) GetDeleted(w http.ResponseWriter, r *http.Request) {
+	if !requireOwner(w, r) {
+		return
+	}
+
+	products, err := h.service.GetDeleted(middlewares.OutletID(r))
+	if err != nil {
+		apperror.Write(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(products)
+}
+
+// POST /api/produk/{id}/pulihkan
+// @Summary Restore Deleted Product
+// @Description Memulihkan produk yang sudah dihapus sehingga kembali muncul di katalog dan bisa dijual. Khusus pemilik (owner)
+// @Tags   produk
+// @Produce json
+// @Param id path int true "Product ID"
+// @Success 200 {object} models.Product
+// @Failure 403 {object} apperror.Response "Owner access required"
+// @Failure 404 {object} apperror.Response "Product not found"
+// @Failure 409 {object} apperror.Response "Product is not deleted"
+// @Router /api/produk/{id}/pulihkan [post]
+func (h *ProductHandler) Restore(w http.ResponseWriter, r *http.Request, id int) {
+	if !requireOwner(w, r) {
+		return
+	}
+
+	product, err := h.service.Restore(middlewares.OutletID(r), id, middlewares.Audit(r))
+	if err != nil {
+		apperror.Write(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(product)
+}
+
+// GET /api/produk/{id}/harga
+// @Summary Get Product Price History
+// @Description Mengambil riwayat harga katalog produk beserta harga terjadwal (applied_at kosong), yang berlaku paling akhir lebih dulu
+// @Tags   produk
+// @Produce json
+// @Param id path int true "Product ID"
+// @Success 200 {array} models.ProductPrice
+// @Failure 404 {object} apperror.Response "Product not found"
+// @Router /api/produk/{id}/harga [get]
+func (h *ProductHandler) GetPriceHistory(w http.ResponseWriter, r *http.Request, id int) {
+	prices, err := h.priceService.GetHistory(id)
+	if err != nil {
+		apperror.Write(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(prices)
+}
+
+// POST /api/produk/{id}/harga
+// @Summary Schedule Product Price
+// @Description Menjadwalkan harga katalog baru: { price, effective_from, note }. effective_from berformat YYYY-MM-DD (awal hari di zona waktu toko) atau RFC3339 dan harus di masa depan. Harga berlaku otomatis pada waktunya. Khusus pemilik (owner)
+// @Accept json
+// @Tags   produk
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param price body models.SchedulePriceRequest true "Scheduled Price"
+// @Success 201 {object} models.ProductPrice
+// @Failure 400 {object} apperror.Response "Invalid request body"
+// @Failure 403 {object} apperror.Response "Owner access required"
+// @Failure 404 {object} apperror.Response "Product not found"
+// @Router /api/produk/{id}/harga [post]
+func (h *ProductHandler) SchedulePrice(w http.ResponseWriter, r *http.Request, id int) {
+	if !requireOwner(w, r) {
+		return
+	}
+
+	var req models.SchedulePriceRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		apperror.Write(w, errInvalidBody)
+		return
+	}
+
+	price, err := h.priceService.Schedule(id, req, middlewares.Audit(r))
+	if err != nil {
+		apperror.Write(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(price)
+}
+
+// DELETE /api/produk/{id}/harga/{price_id}
+// @Summary Cancel Scheduled Price
+// @Description Membatalkan harga terjadwal yang belum berlaku. Riwayat harga yang sudah berlaku tidak bisa dihapus. Khusus pemilik (owner)
+// @Tags   produk
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param price_id path int true "Scheduled Price ID"
+// @Success 200 {object} map[string]string
+// @Failure 403 {object} apperror.Response "Owner access required"
+// @Failure 404 {object} apperror.Response "Scheduled price not found or already applied"
+// @Router /api/produk/{id}/harga/{price_id} [delete]
+func (h *ProductHandler) CancelPrice(w http.ResponseWriter, r *http.Request, id int, priceID int) {
+	if !requireOwner(w, r) {
+		return
+	}
+
+	err := h.priceService.Cancel(id, priceID, middlewares.Audit(r))
+	if err != nil {
+		apperror.Write(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "Scheduled price cancelled successfully",
+	})
+}
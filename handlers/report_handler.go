@@ -2,12 +2,46 @@ package handlers
 
 import (
 	"encoding/json"
+	"kasir-api/apperror"
+	"kasir-api/exports"
+	"kasir-api/middlewares"
 	"kasir-api/models"
 	"kasir-api/services"
+	"log"
 	"net/http"
 	"strings"
 )
 
+var reportExportColumns = []exports.Column{
+	{ID: "Total Pendapatan", EN: "Total Revenue", Kind: exports.Rupiah, Width: 18},
+	{ID: "Total Transaksi", EN: "Total Transactions", Kind: exports.Integer, Width: 16},
+	{ID: "Produk Terlaris", EN: "Best-Selling Product", Kind: exports.Text, Width: 30},
+	{ID: "Qty Terjual", EN: "Quantity Sold", Kind: exports.Quantity, Width: 14},
+}
+
+var timeSeriesExportColumns = []exports.Column{
+	{ID: "Periode", EN: "Period", Kind: exports.DateTime, Width: 20},
+	{ID: "Total Pendapatan", EN: "Total Revenue", Kind: exports.Rupiah, Width: 18},
+	{ID: "Total Transaksi", EN: "Total Transactions", Kind: exports.Integer, Width: 16},
+	{ID: "Qty Terjual", EN: "Quantity Sold", Kind: exports.Quantity, Width: 14},
+}
+
+var productReportExportColumns = []exports.Column{
+	{ID: "Jenis", EN: "Kind", Kind: exports.Text, Width: 12},
+	{ID: "ID Produk", EN: "Product ID", Kind: exports.Integer, Width: 12},
+	{ID: "Produk", EN: "Product", Kind: exports.Text, Width: 30},
+	{ID: "Satuan", EN: "Unit", Kind: exports.Text, Width: 10},
+	{ID: "Qty", EN: "Quantity", Kind: exports.Quantity, Width: 14},
+}
+
+var modifierReportExportColumns = []exports.Column{
+	{ID: "Kelompok", EN: "Group", Kind: exports.Text, Width: 20},
+	{ID: "Modifier", EN: "Modifier", Kind: exports.Text, Width: 24},
+	{ID: "Dipilih", EN: "Times Selected", Kind: exports.Integer, Width: 12},
+	{ID: "Qty Terjual", EN: "Quantity Sold", Kind: exports.Quantity, Width: 14},
+	{ID: "Total Tambahan", EN: "Add-on Revenue", Kind: exports.Rupiah, Width: 18},
+}
+
 type ReportHandler struct {
 	service *services.ReportService
 }
@@ -24,15 +58,34 @@ func NewReportHandler(service *services.ReportService) *ReportHandler {
 // @Produce      json
 // @Param        start_date  query     string  false  "Tanggal awal (Format: YYYY-MM-DD)" example(2026-01-01)
 // @Param        end_date    query     string  false  "Tanggal akhir (Format: YYYY-MM-DD)" example(2026-02-01)
+// @Param        format      query     string  false  "Format keluaran" Enums(json, csv, xlsx) default(json)
+// @Param        lang        query     string  false  "Bahasa judul kolom ekspor" Enums(id, en) default(id)
+// @Param        outlet_id   query     int     false  "ID outlet (default outlet bawaan)"
 // @Success      200      {array}   models.Report
-// @Failure      500      {string}  string "Failed to get report"
+// @Failure      500      {object}  apperror.Response "Failed to get report"
 // @Router       /api/report [get]
 func (h *ReportHandler) HandleReport(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 	case http.MethodGet:
+		if strings.HasSuffix(strings.TrimSuffix(r.URL.Path, "/"), "/timeseries") {
+			h.GetTimeSeries(w, r)
+			return
+		}
+		if strings.HasSuffix(strings.TrimSuffix(r.URL.Path, "/"), "/compare") {
+			h.Compare(w, r)
+			return
+		}
+		if strings.HasSuffix(strings.TrimSuffix(r.URL.Path, "/"), "/produk") {
+			h.GetProductReport(w, r)
+			return
+		}
+		if strings.HasSuffix(strings.TrimSuffix(r.URL.Path, "/"), "/modifier") {
+			h.GetModifierReport(w, r)
+			return
+		}
 		h.GetReport(w, r)
 	default:
-		http.Error(w, "Method not allowed", http.StatusBadRequest)
+		apperror.Write(w, apperror.MethodNotAllowed)
 	}
 }
 
@@ -42,8 +95,10 @@ func (h *ReportHandler) HandleReport(w http.ResponseWriter, r *http.Request) {
 // @Accept       json
 // @Tags         report
 // @Produce      json
+// @Param        format      query     string  false  "Format keluaran" Enums(json, csv, xlsx) default(json)
+// @Param        lang        query     string  false  "Bahasa judul kolom ekspor" Enums(id, en) default(id)
 // @Success      200      {array}   models.Report
-// @Failure      500      {string}  string "Failed to get report"
+// @Failure      500      {object}  apperror.Response "Failed to get report"
 // @Router       /api/report/hari-ini [get]
 func (h *ReportHandler) GetReport(w http.ResponseWriter, r *http.Request) {
 	var report []models.Report
@@ -58,13 +113,232 @@ func (h *ReportHandler) GetReport(w http.ResponseWriter, r *http.Request) {
 		endDate = r.URL.Query().Get("end_date")
 	}
 
-	report, err = h.service.GetReport(startDate, endDate)
+	report, err = h.service.GetReport(middlewares.OutletID(r), startDate, endDate)
 
 	if err != nil {
-		http.Error(w, "Failed to get report: "+err.Error(), http.StatusInternalServerError)
+		apperror.Write(w, err)
+		return
+	}
+
+	if format := exports.Negotiate(r); format != exports.FormatJSON {
+		h.export(w, r, format, "laporan", reportExportColumns, func(writer exports.Writer) error {
+			for _, row := range report {
+				err := writer.WriteRow(row.TotalRevenue, row.TotalTransaksi, row.ProdukTerlaris.Nama, row.ProdukTerlaris.QtyTerjual)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(report)
 }
+
+// GET /api/report/timeseries
+// @Summary      Get Sales Time Series
+// @Description  Mengambil deret waktu pendapatan, jumlah transaksi dan jumlah barang terjual per jam, hari, minggu atau bulan. Hari dihitung berdasarkan zona waktu toko (TIMEZONE), bucket tanpa transaksi bernilai nol
+// @Accept       json
+// @Tags         report
+// @Produce      json
+// @Param        start_date  query     string  false  "Tanggal awal (Format: YYYY-MM-DD)" example(2026-01-01)
+// @Param        end_date    query     string  false  "Tanggal akhir (Format: YYYY-MM-DD)" example(2026-02-01)
+// @Param        interval    query     string  false  "Interval bucket" Enums(hour, day, week, month) default(day)
+// @Param        format      query     string  false  "Format keluaran" Enums(json, csv, xlsx) default(json)
+// @Param        lang        query     string  false  "Bahasa judul kolom ekspor" Enums(id, en) default(id)
+// @Success      200      {object}  models.TimeSeriesReport
+// @Failure      400      {object}  apperror.Response "Invalid query"
+// @Failure      500      {object}  apperror.Response "Failed to get report"
+// @Router       /api/report/timeseries [get]
+func (h *ReportHandler) GetTimeSeries(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	interval := query.Get("interval")
+	if interval == "" {
+		interval = models.IntervalDay
+	}
+
+	series, err := h.service.GetTimeSeries(middlewares.OutletID(r), query.Get("start_date"), query.Get("end_date"), interval)
+
+	if err != nil {
+		apperror.Write(w, err)
+		return
+	}
+
+	if format := exports.Negotiate(r); format != exports.FormatJSON {
+		h.export(w, r, format, "laporan-"+series.Interval, timeSeriesExportColumns, func(writer exports.Writer) error {
+			for _, point := range series.Data {
+				err := writer.WriteRow(point.Bucket, point.TotalRevenue, point.TotalTransaksi, point.QtyTerjual)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(series)
+}
+
+// GET /api/report/compare
+// @Summary      Compare Report With Previous Period
+// @Description  Membandingkan laporan pada rentang tanggal dengan periode pembanding (periode sebelumnya dengan panjang sama, minggu lalu, bulan lalu atau tahun lalu). Berisi selisih absolut dan persentase untuk pendapatan, jumlah transaksi, rata-rata belanja dan jumlah terjual per produk
+// @Accept       json
+// @Tags         report
+// @Produce      json
+// @Param        start_date  query     string  false  "Tanggal awal (Format: YYYY-MM-DD)" example(2026-01-01)
+// @Param        end_date    query     string  false  "Tanggal akhir (Format: YYYY-MM-DD)" example(2026-02-01)
+// @Param        compare     query     string  false  "Periode pembanding" Enums(previous, last_week, last_month, last_year) default(previous)
+// @Success      200      {object}  models.ReportComparison
+// @Failure      400      {object}  apperror.Response "Invalid query"
+// @Failure      500      {object}  apperror.Response "Failed to get report"
+// @Router       /api/report/compare [get]
+func (h *ReportHandler) Compare(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	compare := query.Get("compare")
+	if compare == "" {
+		compare = models.ComparePrevious
+	}
+
+	comparison, err := h.service.Compare(middlewares.OutletID(r), query.Get("start_date"), query.Get("end_date"), compare)
+
+	if err != nil {
+		apperror.Write(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(comparison)
+}
+
+// GET /api/report/produk
+// @Summary      Get Product and Component Report
+// @Description  Mengambil jumlah terjual per produk (dalam satuan dasar produk) beserta jumlah komponen yang terpakai oleh paket dan resep yang terjual. Pada ekspor, kolom Jenis berisi "terjual" atau "komponen"
+// @Accept       json
+// @Tags         report
+// @Produce      json
+// @Param        start_date  query     string  false  "Tanggal awal (Format: YYYY-MM-DD)" example(2026-01-01)
+// @Param        end_date    query     string  false  "Tanggal akhir (Format: YYYY-MM-DD)" example(2026-02-01)
+// @Param        format      query     string  false  "Format keluaran" Enums(json, csv, xlsx) default(json)
+// @Param        lang        query     string  false  "Bahasa judul kolom ekspor" Enums(id, en) default(id)
+// @Param        outlet_id   query     int     false  "ID outlet (default outlet bawaan)"
+// @Success      200      {object}  models.ProductReport
+// @Failure      400      {object}  apperror.Response "Invalid query"
+// @Failure      500      {object}  apperror.Response "Failed to get report"
+// @Router       /api/report/produk [get]
+func (h *ReportHandler) GetProductReport(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	report, err := h.service.GetProductReport(middlewares.OutletID(r), query.Get("start_date"), query.Get("end_date"))
+	if err != nil {
+		apperror.Write(w, err)
+		return
+	}
+
+	if format := exports.Negotiate(r); format != exports.FormatJSON {
+		h.export(w, r, format, "laporan-produk", productReportExportColumns, func(writer exports.Writer) error {
+			for _, s := range report.Terjual {
+				if err := writer.WriteRow("terjual", s.ProductID, s.Nama, s.Satuan, s.QtyTerjual); err != nil {
+					return err
+				}
+			}
+			for _, u := range report.KomponenTerpakai {
+				if err := writer.WriteRow("komponen", u.ProductID, u.Nama, u.Satuan, u.QtyTerpakai); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// GET /api/report/modifier
+// @Summary      Get Modifier Popularity Report
+// @Description  Menghitung popularitas modifier (misalnya extra shot atau susu oat): berapa item transaksi yang memilihnya, jumlah kuantitas item tersebut dan pendapatan dari harga modifier. Modifier dikelompokkan berdasarkan nama kelompok dan nama saat transaksi
+// @Accept       json
+// @Tags         report
+// @Produce      json
+// @Param        start_date  query     string  false  "Tanggal awal (Format: YYYY-MM-DD)" example(2026-01-01)
+// @Param        end_date    query     string  false  "Tanggal akhir (Format: YYYY-MM-DD)" example(2026-02-01)
+// @Param        format      query     string  false  "Format keluaran" Enums(json, csv, xlsx) default(json)
+// @Param        lang        query     string  false  "Bahasa judul kolom ekspor" Enums(id, en) default(id)
+// @Param        outlet_id   query     int     false  "ID outlet (default outlet bawaan)"
+// @Success      200      {object}  models.ModifierReport
+// @Failure      400      {object}  apperror.Response "Invalid query"
+// @Failure      500      {object}  apperror.Response "Failed to get report"
+// @Router       /api/report/modifier [get]
+func (h *ReportHandler) GetModifierReport(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	report, err := h.service.GetModifierReport(middlewares.OutletID(r), query.Get("start_date"), query.Get("end_date"))
+	if err != nil {
+		apperror.Write(w, err)
+		return
+	}
+
+	if format := exports.Negotiate(r); format != exports.FormatJSON {
+		h.export(w, r, format, "laporan-modifier", modifierReportExportColumns, func(writer exports.Writer) error {
+			for _, m := range report.Modifier {
+				if err := writer.WriteRow(m.Grup, m.Nama, m.Dipilih, m.QtyTerjual, m.TotalTambahan); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// GET /api/report/konsolidasi
+// @Summary      Get Consolidated Report
+// @Description  Laporan gabungan semua outlet beserta rincian pendapatan per outlet dan 10 produk terlaris. Khusus pemilik (owner)
+// @Tags         report
+// @Produce      json
+// @Param        start_date  query     string  false  "Tanggal awal (Format: YYYY-MM-DD)" example(2026-01-01)
+// @Param        end_date    query     string  false  "Tanggal akhir (Format: YYYY-MM-DD)" example(2026-02-01)
+// @Success      200      {object}  models.ConsolidatedReport
+// @Failure      400      {object}  apperror.Response "Invalid query"
+// @Failure      403      {object}  apperror.Response "Owner access required"
+// @Failure      500      {object}  apperror.Response "Failed to get report"
+// @Router       /api/report/konsolidasi [get]
+func (h *ReportHandler) GetConsolidated(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		apperror.Write(w, apperror.MethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	report, err := h.service.GetConsolidated(query.Get("start_date"), query.Get("end_date"))
+	if err != nil {
+		apperror.Write(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+func (h *ReportHandler) export(w http.ResponseWriter, r *http.Request, format string, filename string, columns []exports.Column, write func(exports.Writer) error) {
+	writer, err := exports.NewWriter(w, format, filename, "Laporan", columns, exports.Language(r))
+	if err != nil {
+		apperror.Write(w, err)
+		return
+	}
+
+	if err := write(writer); err != nil {
+		log.Println("Export laporan terhenti:", err)
+		return
+	}
+
+	if err := writer.Close(); err != nil {
+		log.Println("Export laporan gagal ditutup:", err)
+	}
+}
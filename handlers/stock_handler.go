@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"encoding/json"
+	"kasir-api/apperror"
+	"kasir-api/middlewares"
+	"kasir-api/services"
+	"net/http"
+)
+
+type StockHandler struct {
+	service *services.StockService
+}
+
+func NewStockHandler(service *services.StockService) *StockHandler {
+	return &StockHandler{service: service}
+}
+
+// GET /api/stok/mutasi
+// @Summary      Get Stock Ledger
+// @Description  Mengambil buku stok outlet yang dipilih (X-Outlet-ID): penjualan, penyesuaian, transfer keluar dan masuk. Quantity positif berarti stok masuk
+// @Tags         stok
+// @Produce      json
+// @Param        product_id  query     int     false  "Filter produk"
+// @Param        start_date  query     string  false  "Tanggal awal (Format: YYYY-MM-DD)" example(2026-01-01)
+// @Param        end_date    query     string  false  "Tanggal akhir (Format: YYYY-MM-DD)" example(2026-02-01)
+// @Success      200      {array}   models.StockMovement
+// @Failure      400      {object}  apperror.Response "Invalid query"
+// @Failure      500      {object}  apperror.Response "Failed to get stock ledger"
+// @Router       /api/stok/mutasi [get]
+func (h *StockHandler) GetMovements(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		apperror.Write(w, apperror.MethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	movements, err := h.service.GetMovements(middlewares.OutletID(r), query.Get("product_id"), query.Get("start_date"), query.Get("end_date"))
+	if err != nil {
+		apperror.Write(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(movements)
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"encoding/json"
+	"kasir-api/apperror"
+	"kasir-api/middlewares"
+	"kasir-api/models"
+	"kasir-api/services"
+	"net/http"
+)
+
+type SyncHandler struct {
+	service *services.SyncService
+}
+
+func NewSyncHandler(service *services.SyncService) *SyncHandler {
+	return &SyncHandler{service: service}
+}
+
+// POST /api/sync/transaksi
+// @Summary      Sync Offline Transactions
+// @Description  Mengunggah transaksi yang dibuat terminal saat offline. Setiap transaksi wajib memiliki client_uuid; transaksi diproses berurutan dan idempoten, created_at dari terminal dipertahankan dan dipakai untuk harga serta masa berlaku voucher dan kartu hadiah; created_at di masa depan atau lebih dari 30 hari lalu ditolak. Hasil per transaksi: applied, duplicate atau rejected
+// @Tags         sync
+// @Accept       json
+// @Produce      json
+// @Param        batch  body      models.SyncBatchRequest true "Batch transaksi offline"
+// @Success      200    {object}  models.SyncBatchResponse
+// @Failure      400    {object}  apperror.Response "Invalid request body"
+// @Failure      500    {object}  apperror.Response "Failed to sync transactions"
+// @Router       /api/sync/transaksi [post]
+func (h *SyncHandler) HandleTransactions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		apperror.Write(w, apperror.MethodNotAllowed)
+		return
+	}
+
+	var req models.SyncBatchRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		apperror.Write(w, errInvalidBody)
+		return
+	}
+
+	response, err := h.service.ApplyBatch(middlewares.OutletID(r), req, middlewares.Audit(r))
+	if err != nil {
+		apperror.Write(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// GET /api/sync/changes
+// @Summary      Get Catalogue Changes
+// @Description  Mengambil perubahan produk, harga dan kategori sejak cursor, termasuk data yang dihapus. Tanpa cursor mengembalikan seluruh katalog; ulangi dengan cursor dari respons selama has_more bernilai true
+// @Tags         sync
+// @Produce      json
+// @Param        cursor  query     string false  "Cursor dari respons sebelumnya"
+// @Param        limit   query     int    false  "Jumlah perubahan maksimal (default 500, maks 2000)"
+// @Success      200     {object}  models.SyncChanges
+// @Failure      400     {object}  apperror.Response "Invalid query"
+// @Failure      500     {object}  apperror.Response "Failed to get changes"
+// @Router       /api/sync/changes [get]
+func (h *SyncHandler) HandleChanges(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		apperror.Write(w, apperror.MethodNotAllowed)
+		return
+	}
+
+	changes, err := h.service.GetChanges(middlewares.OutletID(r), r.URL.Query().Get("cursor"), r.URL.Query().Get("limit"))
+	if err != nil {
+		apperror.Write(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(changes)
+}
@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"encoding/json"
+	"kasir-api/apperror"
+	"kasir-api/models"
+	"kasir-api/services"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type TenantHandler struct {
+	service *services.TenantService
+}
+
+func NewTenantHandler(service *services.TenantService) *TenantHandler {
+	return &TenantHandler{service: service}
+}
+
+// GET /api/platform/tenant
+// @Summary      Get All Tenants
+// @Description  Mengambil semua tenant pada mode multi-tenant. Memerlukan PLATFORM_API_KEY pada header X-Api-Key
+// @Tags         platform
+// @Produce      json
+// @Success      200      {array}   models.Tenant
+// @Failure      401      {object}  apperror.Response "Invalid API Key"
+// @Failure      500      {object}  apperror.Response "Failed to get tenants"
+// @Router       /api/platform/tenant [get]
+func (h *TenantHandler) HandleTenants(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetAll(w, r)
+	case http.MethodPost:
+		h.Provision(w, r)
+	default:
+		apperror.Write(w, apperror.MethodNotAllowed)
+	}
+}
+
+// GET /api/platform/tenant/{id}
+// @Summary      Get Tenant by ID
+// @Description  Mengambil data tenant berdasarkan ID. Memerlukan PLATFORM_API_KEY
+// @Tags         platform
+// @Produce      json
+// @Param        id       path      int   true   "Tenant ID"
+// @Success      200      {object}  models.Tenant
+// @Failure      400      {object}  apperror.Response "Invalid tenant ID"
+// @Failure      404      {object}  apperror.Response "Tenant not found"
+// @Router       /api/platform/tenant/{id} [get]
+func (h *TenantHandler) HandleTenantByID(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/platform/tenant/"), "/")
+	idStr, action, _ := strings.Cut(path, "/")
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		apperror.Write(w, apperror.BadRequest("Invalid tenant ID"))
+		return
+	}
+
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		h.GetByID(w, r, id)
+	case action == "" && r.Method == http.MethodPut:
+		h.Update(w, r, id)
+	case action == "api-key" && r.Method == http.MethodPost:
+		h.RotateAPIKey(w, r, id)
+	case action == "" || action == "api-key":
+		apperror.Write(w, apperror.MethodNotAllowed)
+	default:
+		apperror.Write(w, errRouteNotFound)
+	}
+}
+
+func (h *TenantHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	tenants, err := h.service.GetAll()
+	if err != nil {
+		apperror.Write(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tenants)
+}
+
+// POST /api/platform/tenant
+// @Summary Provision Tenant
+// @Description Mendaftarkan tenant baru: { slug, name }, serta address, phone, npwp dan receipt_footer opsional untuk struk (name menjadi nama toko di struk). Schema database tenant dibuat dan dimigrasi, lalu API key pemilik tenant ditampilkan sekali pada respons ini. Tenant diakses lewat subdomain {slug}.BASE_DOMAIN, header X-Tenant-ID, atau API key pemilik tenant
+// @Accept json
+// @Tags   platform
+// @Produce json
+// @Param tenant body models.Tenant true "New Tenant Data"
+// @Success 201 {object} models.Tenant
+// @Failure 400 {object} apperror.Response "Invalid request body"
+// @Failure 500 {object} apperror.Response "Failed to provision tenant"
+// @Router /api/platform/tenant [post]
+func (h *TenantHandler) Provision(w http.ResponseWriter, r *http.Request) {
+	var tenant models.Tenant
+	err := json.NewDecoder(r.Body).Decode(&tenant)
+	if err != nil {
+		apperror.Write(w, errInvalidBody)
+		return
+	}
+
+	err = h.service.Provision(&tenant)
+	if err != nil {
+		apperror.Write(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(tenant)
+}
+
+func (h *TenantHandler) GetByID(w http.ResponseWriter, r *http.Request, id int) {
+	tenant, err := h.service.GetByID(id)
+	if err != nil {
+		apperror.Write(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tenant)
+}
+
+// PUT /api/platform/tenant/{id}
+// @Summary Update Tenant by ID
+// @Description Memperbarui nama, status aktif dan identitas struk tenant: { name, active, address, phone, npwp, receipt_footer }, field yang tidak dikirim tidak diubah. Tenant nonaktif ditolak dengan 403. Memerlukan PLATFORM_API_KEY
+// @Accept json
+// @Tags   platform
+// @Produce json
+// @Param id path int true "Tenant ID"
+// @Param tenant body models.TenantUpdate true "Updated Tenant Data"
+// @Success 200 {object} models.Tenant
+// @Failure 400 {object} apperror.Response "Invalid request body"
+// @Failure 404 {object} apperror.Response "Tenant not found"
+// @Router /api/platform/tenant/{id} [put]
+func (h *TenantHandler) Update(w http.ResponseWriter, r *http.Request, id int) {
+	var upd models.TenantUpdate
+	err := json.NewDecoder(r.Body).Decode(&upd)
+	if err != nil {
+		apperror.Write(w, errInvalidBody)
+		return
+	}
+
+	tenant, err := h.service.Update(id, upd)
+	if err != nil {
+		apperror.Write(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tenant)
+}
+
+// POST /api/platform/tenant/{id}/api-key
+// @Summary Rotate Tenant API Key
+// @Description Membuat API key pemilik tenant yang baru, key lama langsung tidak berlaku. Key baru hanya ditampilkan sekali. Memerlukan PLATFORM_API_KEY
+// @Tags   platform
+// @Produce json
+// @Param id path int true "Tenant ID"
+// @Success 200 {object} models.Tenant
+// @Failure 404 {object} apperror.Response "Tenant not found"
+// @Router /api/platform/tenant/{id}/api-key [post]
+func (h *TenantHandler) RotateAPIKey(w http.ResponseWriter, r *http.Request, id int) {
+	tenant, err := h.service.RotateAPIKey(id)
+	if err != nil {
+		apperror.Write(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tenant)
+}
@@ -2,36 +2,45 @@ package handlers
 
 import (
 	"encoding/json"
+	"kasir-api/apperror"
+	"kasir-api/exports"
+	"kasir-api/middlewares"
 	"kasir-api/models"
+	"kasir-api/receipts"
 	"kasir-api/services"
+	"log"
 	"net/http"
+	"strconv"
+	"strings"
 )
 
 type TransactionHandler struct {
 	service *services.TransactionService
+	printer *receipts.Printer
 }
 
-func NewTransactionHandler(service *services.TransactionService) *TransactionHandler {
-	return &TransactionHandler{service: service}
+func NewTransactionHandler(service *services.TransactionService, printer *receipts.Printer) *TransactionHandler {
+	return &TransactionHandler{service: service, printer: printer}
 }
 
 // POST /api/checkout
 // @Summary Checkout Product
-// @Description Melakukan checkout barang: format data yang harus diisi { items: [ { product_id, quantity } ]}
+// @Description Melakukan checkout barang: format data yang harus diisi { items: [ { product_id, quantity, unit, modifiers } ]}. unit opsional (default satuan dasar produk) dan harus satuan dasar atau salah satu units produk; quantity boleh pecahan untuk kg dan liter, contoh 0.75. modifiers berisi id modifier yang dipilih, sesuai batas min/max setiap kelompok modifier produk; harga modifier ditambahkan ke harga satuan dan modifier tersimpan di detail transaksi. Produk yang sama boleh muncul di beberapa item dengan modifier berbeda. Paket dan resep mengurangi stok komponennya, dan komponen yang terpakai dicatat di detail transaksi (components). Opsional: customer_id atau customer_phone untuk mencatat pelanggan dan poin, redeem_points untuk menukar poin sebagai potongan harga, voucher_code untuk voucher dan gift_card_code untuk membayar dengan kartu hadiah
 // @Accept json
 // @Tags   checkout
 // @Produce json
 // @Param product body models.CheckoutRequest true "New Checkout Data"
 // @Success 201 {object} models.Transaction
-// @Failure 400 {string} string "Invalid request body"
-// @Failure 500 {string} string "Failed to create checkout"
+// @Failure 400 {object} apperror.Response "Invalid request body"
+// @Failure 422 {object} apperror.Response "Validation failed"
+// @Failure 500 {object} apperror.Response "Failed to create checkout"
 // @Router /api/checkout [post]
 func (h *TransactionHandler) HandleCheckout(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 	case http.MethodPost:
 		h.Checkout(w, r)
 	default:
-		http.Error(w, "Method not allowed", http.StatusBadRequest)
+		apperror.Write(w, apperror.MethodNotAllowed)
 	}
 }
 
@@ -39,16 +48,233 @@ func (h *TransactionHandler) Checkout(w http.ResponseWriter, r *http.Request) {
 	var req models.CheckoutRequest
 	err := json.NewDecoder(r.Body).Decode(&req)
 	if err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		apperror.Write(w, errInvalidBody)
 		return
 	}
 
-	transaction, err := h.service.Checkout(req.Items)
+	transaction, err := h.service.Checkout(middlewares.OutletID(r), req, middlewares.Audit(r))
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		apperror.Write(w, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(transaction)
 }
+
+var transactionExportColumns = []exports.Column{
+	{ID: "ID Transaksi", EN: "Transaction ID", Kind: exports.Integer, Width: 14},
+	{ID: "Waktu", EN: "Time", Kind: exports.DateTime, Width: 20},
+	{ID: "ID Produk", EN: "Product ID", Kind: exports.Integer, Width: 12},
+	{ID: "Nama Produk", EN: "Product Name", Kind: exports.Text, Width: 30},
+	{ID: "Jumlah", EN: "Quantity", Kind: exports.Quantity, Width: 10},
+	{ID: "Satuan", EN: "Unit", Kind: exports.Text, Width: 8},
+	{ID: "Modifier", EN: "Modifiers", Kind: exports.Text, Width: 30},
+	{ID: "Harga Satuan", EN: "Unit Price", Kind: exports.Rupiah, Width: 16},
+	{ID: "Subtotal", EN: "Subtotal", Kind: exports.Rupiah, Width: 16},
+	{ID: "Diskon", EN: "Discount", Kind: exports.Rupiah, Width: 14},
+	{ID: "Total Transaksi", EN: "Transaction Total", Kind: exports.Rupiah, Width: 18},
+}
+
+// GET /api/transaksi
+// @Summary List Transactions
+// @Description Mengambil daftar transaksi beserta detailnya berdasarkan rentang tanggal (zona waktu toko). Dapat diekspor ke CSV atau XLSX melalui parameter format atau header Accept, satu baris per detail transaksi
+// @Tags   transaksi
+// @Produce json
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param start_date query string false "Tanggal awal (Format: YYYY-MM-DD)" example(2026-01-01)
+// @Param end_date   query string false "Tanggal akhir (Format: YYYY-MM-DD)" example(2026-02-01)
+// @Param format     query string false "Format keluaran" Enums(json, csv, xlsx) default(json)
+// @Param lang       query string false "Bahasa judul kolom ekspor" Enums(id, en) default(id)
+// @Success 200 {array} models.Transaction
+// @Failure 400 {object} apperror.Response "Invalid query"
+// @Failure 500 {object} apperror.Response "Failed to get transactions"
+// @Router /api/transaksi [get]
+func (h *TransactionHandler) HandleTransactions(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetTransactions(w, r)
+	default:
+		apperror.Write(w, apperror.MethodNotAllowed)
+	}
+}
+
+func (h *TransactionHandler) GetTransactions(w http.ResponseWriter, r *http.Request) {
+	startDate := r.URL.Query().Get("start_date")
+	endDate := r.URL.Query().Get("end_date")
+
+	format := exports.Negotiate(r)
+	if format != exports.FormatJSON {
+		h.exportTransactions(w, r, format, startDate, endDate)
+		return
+	}
+
+	transactions, err := h.service.GetTransactions(middlewares.OutletID(r), startDate, endDate)
+	if err != nil {
+		apperror.Write(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(transactions)
+}
+
+func (h *TransactionHandler) exportTransactions(w http.ResponseWriter, r *http.Request, format string, startDate string, endDate string) {
+	var writer exports.Writer
+
+	// Writer baru dibuat saat baris pertama tiba, supaya error sebelum streaming
+	// (misalnya tanggal tidak valid) masih bisa dibalas dengan status yang benar.
+	open := func() error {
+		if writer != nil {
+			return nil
+		}
+		var err error
+		writer, err = exports.NewWriter(w, format, "transaksi", "Transaksi", transactionExportColumns, exports.Language(r))
+		return err
+	}
+
+	err := h.service.StreamTransactions(middlewares.OutletID(r), startDate, endDate, func(t models.Transaction, d models.TransactionDetail) error {
+		if err := open(); err != nil {
+			return err
+		}
+		modifiers := make([]string, len(d.Modifiers))
+		for i, m := range d.Modifiers {
+			modifiers[i] = m.Group + ": " + receipts.Modifier(m)
+		}
+		return writer.WriteRow(t.ID, t.CreatedAt, d.ProductID, d.ProductName, d.Quantity, d.Unit, strings.Join(modifiers, ", "), d.UnitPrice, d.Subtotal, t.DiscountAmount, t.TotalAmount)
+	})
+
+	if writer == nil {
+		if err != nil {
+			apperror.Write(w, err)
+			return
+		}
+		// Tidak ada transaksi, tetap kirim file berisi judul kolom
+		if err := open(); err != nil {
+			apperror.Write(w, err)
+			return
+		}
+	}
+
+	if err != nil {
+		log.Println("Export transaksi terhenti:", err)
+		return
+	}
+
+	if err := writer.Close(); err != nil {
+		log.Println("Export transaksi gagal ditutup:", err)
+	}
+}
+
+// GET /api/transaksi/{id}
+// @Summary Get Transaction by ID
+// @Description Mengambil data transaksi beserta detailnya berdasarkan ID
+// @Tags   transaksi
+// @Produce json
+// @Param id path int true "Transaction ID"
+// @Success 200 {object} models.Transaction
+// @Failure 400 {object} apperror.Response "Invalid transaction ID"
+// @Failure 404 {object} apperror.Response "Transaction not found"
+// @Router /api/transaksi/{id} [get]
+func (h *TransactionHandler) HandleTransactionByID(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		apperror.Write(w, apperror.MethodNotAllowed)
+		return
+	}
+
+	path := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/transaksi/"), "/")
+	idStr, action, _ := strings.Cut(path, "/")
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		apperror.Write(w, apperror.BadRequest("Invalid transaction ID"))
+		return
+	}
+
+	switch action {
+	case "":
+		h.GetByID(w, r, id)
+	case "receipt":
+		h.Receipt(w, r, id)
+	default:
+		apperror.Write(w, errRouteNotFound)
+	}
+}
+
+func (h *TransactionHandler) GetByID(w http.ResponseWriter, r *http.Request, id int) {
+	transaction, err := h.service.GetByID(middlewares.OutletID(r), id)
+	if err != nil {
+		apperror.Write(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(transaction)
+}
+
+// GET /api/transaksi/{id}/receipt
+// @Summary Print Transaction Receipt
+// @Description Mencetak struk transaksi sebagai teks lebar tetap (kertas 58mm/80mm), byte stream ESC/POS untuk printer thermal (dengan potong kertas dan buka laci kas) atau PDF. Cetakan kedua dan seterusnya ditandai sebagai salinan
+// @Tags   transaksi
+// @Produce plain
+// @Produce application/octet-stream
+// @Produce application/pdf
+// @Param id      path  int    true  "Transaction ID"
+// @Param format  query string false "Format struk" Enums(text, escpos, pdf) default(text)
+// @Param paper   query int    false "Lebar kertas (mm)" Enums(58, 80) default(58)
+// @Param preview query bool   false "Pratinjau, tidak dihitung sebagai cetak" default(false)
+// @Param drawer  query bool   false "Buka laci kas (hanya ESC/POS, cetakan pertama)" default(true)
+// @Success 200 {string} string "Receipt"
+// @Failure 400 {object} apperror.Response "Invalid receipt option"
+// @Failure 404 {object} apperror.Response "Transaction not found"
+// @Router /api/transaksi/{id}/receipt [get]
+func (h *TransactionHandler) Receipt(w http.ResponseWriter, r *http.Request, id int) {
+	query := r.URL.Query()
+
+	format := query.Get("format")
+	if format == "" {
+		format = receipts.FormatText
+	}
+	if format != receipts.FormatText && format != receipts.FormatESCPOS && format != receipts.FormatPDF {
+		apperror.Write(w, apperror.BadRequest("Invalid receipt format"))
+		return
+	}
+
+	paper := receipts.Paper58
+	if paperStr := query.Get("paper"); paperStr != "" {
+		paper, _ = strconv.Atoi(paperStr)
+		if paper != receipts.Paper58 && paper != receipts.Paper80 {
+			apperror.Write(w, apperror.BadRequest("Invalid paper width, expected 58 or 80"))
+			return
+		}
+	}
+
+	preview := query.Get("preview") == "true"
+	body, err := h.service.PrintReceipt(middlewares.OutletID(r), id, preview, func(transaction *models.Transaction, isCopy bool) ([]byte, error) {
+		switch format {
+		case receipts.FormatESCPOS:
+			openDrawer := query.Get("drawer") != "false" && !isCopy && !preview
+			return h.printer.ESCPOS(transaction, paper, isCopy, openDrawer)
+		case receipts.FormatPDF:
+			return h.printer.PDF(transaction, paper, isCopy)
+		default:
+			return h.printer.Text(transaction, paper, isCopy)
+		}
+	})
+	if err != nil {
+		apperror.Write(w, err)
+		return
+	}
+
+	switch format {
+	case receipts.FormatESCPOS:
+		w.Header().Set("Content-Type", "application/octet-stream")
+	case receipts.FormatPDF:
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Header().Set("Content-Disposition", "inline; filename=\"struk-"+strconv.Itoa(id)+".pdf\"")
+	default:
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	}
+	w.Write(body)
+}
@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"encoding/json"
+	"kasir-api/apperror"
+	"kasir-api/middlewares"
+	"kasir-api/models"
+	"kasir-api/services"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type TransferHandler struct {
+	service *services.TransferService
+}
+
+func NewTransferHandler(service *services.TransferService) *TransferHandler {
+	return &TransferHandler{service: service}
+}
+
+// GET /api/transfer
+// @Summary      Get Stock Transfers
+// @Description  Mengambil transfer stok yang keluar dari atau masuk ke outlet yang dipilih (X-Outlet-ID)
+// @Tags         transfer
+// @Produce      json
+// @Param        status  query     string false  "Status transfer" Enums(requested, shipped, received, received_with_discrepancy, cancelled)
+// @Success      200      {array}   models.StockTransfer
+// @Failure      400      {object}  apperror.Response "Invalid query"
+// @Failure      500      {object}  apperror.Response "Failed to get transfers"
+// @Router       /api/transfer [get]
+func (h *TransferHandler) HandleTransfers(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetAll(w, r)
+	case http.MethodPost:
+		h.Create(w, r)
+	default:
+		apperror.Write(w, apperror.MethodNotAllowed)
+	}
+}
+
+func (h *TransferHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	transfers, err := h.service.GetAll(middlewares.OutletID(r), r.URL.Query().Get("status"))
+	if err != nil {
+		apperror.Write(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(transfers)
+}
+
+// POST /api/transfer
+// @Summary Create Stock Transfer
+// @Description Membuat permintaan transfer stok: { from_outlet_id, to_outlet_id, note, lines: [{ product_id, quantity }] }. Stok belum berubah sampai transfer dikirim
+// @Accept json
+// @Tags   transfer
+// @Produce json
+// @Param transfer body models.StockTransfer true "New Transfer Data"
+// @Success 201 {object} models.StockTransfer
+// @Failure 400 {object} apperror.Response "Invalid request body"
+// @Failure 403 {object} apperror.Response "Forbidden"
+// @Failure 500 {object} apperror.Response "Failed to create transfer"
+// @Router /api/transfer [post]
+func (h *TransferHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var transfer models.StockTransfer
+	err := json.NewDecoder(r.Body).Decode(&transfer)
+	if err != nil {
+		apperror.Write(w, errInvalidBody)
+		return
+	}
+
+	err = h.service.Create(middlewares.CurrentUser(r), &transfer)
+	if err != nil {
+		apperror.Write(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(transfer)
+}
+
+// GET /api/transfer/{id}
+// @Summary      Get Stock Transfer by ID
+// @Description  Mengambil dokumen transfer beserta jumlah diminta, dikirim, diterima dan selisihnya
+// @Tags         transfer
+// @Produce      json
+// @Param        id       path      int   true   "Transfer ID"
+// @Success      200      {object}  models.StockTransfer
+// @Failure      400      {object}  apperror.Response "Invalid transfer ID"
+// @Failure      404      {object}  apperror.Response "Transfer not found"
+// @Router       /api/transfer/{id} [get]
+func (h *TransferHandler) HandleTransferByID(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/transfer/"), "/")
+	idStr, action, _ := strings.Cut(path, "/")
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		apperror.Write(w, apperror.BadRequest("Invalid transfer ID"))
+		return
+	}
+
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		h.GetByID(w, r, id)
+	case action == "kirim" && r.Method == http.MethodPost:
+		h.Ship(w, r, id)
+	case action == "terima" && r.Method == http.MethodPost:
+		h.Receive(w, r, id)
+	case action == "batal" && r.Method == http.MethodPost:
+		h.Cancel(w, r, id)
+	case action == "" || action == "kirim" || action == "terima" || action == "batal":
+		apperror.Write(w, apperror.MethodNotAllowed)
+	default:
+		apperror.Write(w, errRouteNotFound)
+	}
+}
+
+func (h *TransferHandler) GetByID(w http.ResponseWriter, r *http.Request, id int) {
+	transfer, err := h.service.GetByID(middlewares.CurrentUser(r), id)
+	if err != nil {
+		apperror.Write(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(transfer)
+}
+
+// POST /api/transfer/{id}/kirim
+// @Summary Ship Stock Transfer
+// @Description Mengirim transfer dari outlet asal. Stok asal berkurang dan tercatat di buku stok; barang terlihat sebagai in_transit di outlet tujuan. Opsional: { lines: [{ product_id, quantity }] } untuk jumlah kirim yang berbeda dari permintaan
+// @Accept json
+// @Tags   transfer
+// @Produce json
+// @Param id    path int                       true  "Transfer ID"
+// @Param lines body models.TransferQuantities false "Jumlah dikirim"
+// @Success 200 {object} models.StockTransfer
+// @Failure 400 {object} apperror.Response "Invalid request body"
+// @Failure 403 {object} apperror.Response "Forbidden"
+// @Failure 500 {object} apperror.Response "Failed to ship transfer"
+// @Router /api/transfer/{id}/kirim [post]
+func (h *TransferHandler) Ship(w http.ResponseWriter, r *http.Request, id int) {
+	req, ok := decodeTransferQuantities(w, r)
+	if !ok {
+		return
+	}
+
+	transfer, err := h.service.Ship(middlewares.CurrentUser(r), id, req)
+	writeTransferResult(w, transfer, err)
+}
+
+// POST /api/transfer/{id}/terima
+// @Summary Receive Stock Transfer
+// @Description Menerima transfer di outlet tujuan. Stok tujuan bertambah dan tercatat di buku stok. Opsional: { lines: [{ product_id, quantity }] } untuk jumlah yang benar-benar diterima; selisih membuat status received_with_discrepancy
+// @Accept json
+// @Tags   transfer
+// @Produce json
+// @Param id    path int                       true  "Transfer ID"
+// @Param lines body models.TransferQuantities false "Jumlah diterima"
+// @Success 200 {object} models.StockTransfer
+// @Failure 400 {object} apperror.Response "Invalid request body"
+// @Failure 403 {object} apperror.Response "Forbidden"
+// @Failure 500 {object} apperror.Response "Failed to receive transfer"
+// @Router /api/transfer/{id}/terima [post]
+func (h *TransferHandler) Receive(w http.ResponseWriter, r *http.Request, id int) {
+	req, ok := decodeTransferQuantities(w, r)
+	if !ok {
+		return
+	}
+
+	transfer, err := h.service.Receive(middlewares.CurrentUser(r), id, req)
+	writeTransferResult(w, transfer, err)
+}
+
+// POST /api/transfer/{id}/batal
+// @Summary Cancel Stock Transfer
+// @Description Membatalkan transfer yang belum dikirim
+// @Tags   transfer
+// @Produce json
+// @Param id path int true "Transfer ID"
+// @Success 200 {object} models.StockTransfer
+// @Failure 403 {object} apperror.Response "Forbidden"
+// @Failure 500 {object} apperror.Response "Failed to cancel transfer"
+// @Router /api/transfer/{id}/batal [post]
+func (h *TransferHandler) Cancel(w http.ResponseWriter, r *http.Request, id int) {
+	transfer, err := h.service.Cancel(middlewares.CurrentUser(r), id)
+	writeTransferResult(w, transfer, err)
+}
+
+func decodeTransferQuantities(w http.ResponseWriter, r *http.Request) (models.TransferQuantities, bool) {
+	var req models.TransferQuantities
+	if r.ContentLength != 0 {
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			apperror.Write(w, errInvalidBody)
+			return req, false
+		}
+	}
+	return req, true
+}
+
+func writeTransferResult(w http.ResponseWriter, transfer *models.StockTransfer, err error) {
+	if err != nil {
+		apperror.Write(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(transfer)
+}
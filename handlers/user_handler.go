@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"encoding/json"
+	"kasir-api/apperror"
+	"kasir-api/middlewares"
+	"kasir-api/models"
+	"kasir-api/services"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type UserHandler struct {
+	service *services.UserService
+}
+
+func NewUserHandler(service *services.UserService) *UserHandler {
+	return &UserHandler{service: service}
+}
+
+// GET /api/pengguna
+// @Summary      Get All Users
+// @Description  Mengambil semua pengguna beserta outlet yang ditugaskan. Khusus pemilik (owner)
+// @Tags         pengguna
+// @Produce      json
+// @Success      200      {array}   models.User
+// @Failure      403      {object}  apperror.Response "Owner access required"
+// @Failure      500      {object}  apperror.Response "Failed to get users"
+// @Router       /api/pengguna [get]
+func (h *UserHandler) HandleUsers(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetAll(w, r)
+	case http.MethodPost:
+		h.Create(w, r)
+	default:
+		apperror.Write(w, apperror.MethodNotAllowed)
+	}
+}
+
+// GET /api/pengguna/{id}
+// @Summary      Get User by ID
+// @Description  Mengambil data pengguna berdasarkan ID. Khusus pemilik (owner)
+// @Tags         pengguna
+// @Produce      json
+// @Param        id       path      int   true   "User ID"
+// @Success      200      {object}  models.User
+// @Failure      400      {object}  apperror.Response "Invalid user ID"
+// @Failure      404      {object}  apperror.Response "User not found"
+// @Router       /api/pengguna/{id} [get]
+func (h *UserHandler) HandleUserByID(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/pengguna/"), "/")
+	idStr, action, _ := strings.Cut(path, "/")
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		apperror.Write(w, apperror.BadRequest("Invalid user ID"))
+		return
+	}
+
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		h.GetByID(w, r, id)
+	case action == "" && r.Method == http.MethodPut:
+		h.Update(w, r, id)
+	case action == "" && r.Method == http.MethodDelete:
+		h.Delete(w, r, id)
+	case action == "api-key" && r.Method == http.MethodPost:
+		h.RotateAPIKey(w, r, id)
+	case action == "" || action == "api-key":
+		apperror.Write(w, apperror.MethodNotAllowed)
+	default:
+		apperror.Write(w, errRouteNotFound)
+	}
+}
+
+func (h *UserHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	users, err := h.service.GetAll()
+	if err != nil {
+		apperror.Write(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(users)
+}
+
+// POST /api/pengguna
+// @Summary Create User
+// @Description Menambahkan pengguna: { name, role, outlet_ids }. role owner atau cashier (default cashier). API key hanya ditampilkan sekali pada respons ini. Khusus pemilik (owner)
+// @Accept json
+// @Tags   pengguna
+// @Produce json
+// @Param user body models.User true "New User Data"
+// @Success 201 {object} models.User
+// @Failure 400 {object} apperror.Response "Invalid request body"
+// @Failure 500 {object} apperror.Response "Failed to create user"
+// @Router /api/pengguna [post]
+func (h *UserHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var user models.User
+	err := json.NewDecoder(r.Body).Decode(&user)
+	if err != nil {
+		apperror.Write(w, errInvalidBody)
+		return
+	}
+
+	err = h.service.Create(&user, middlewares.Audit(r))
+	if err != nil {
+		apperror.Write(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(user)
+}
+
+func (h *UserHandler) GetByID(w http.ResponseWriter, r *http.Request, id int) {
+	user, err := h.service.GetByID(id)
+	if err != nil {
+		apperror.Write(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(user)
+}
+
+// PUT /api/pengguna/{id}
+// @Summary Update User by ID
+// @Description Memperbarui nama, peran dan outlet pengguna: { name, role, outlet_ids }. Khusus pemilik (owner)
+// @Accept json
+// @Tags   pengguna
+// @Produce json
+// @Param id path int true "User ID"
+// @Param user body models.User true "Updated User Data"
+// @Success 200 {object} models.User
+// @Failure 400 {object} apperror.Response "Invalid request body"
+// @Failure 500 {object} apperror.Response "Failed to update user"
+// @Router /api/pengguna/{id} [put]
+func (h *UserHandler) Update(w http.ResponseWriter, r *http.Request, id int) {
+	var user models.User
+	err := json.NewDecoder(r.Body).Decode(&user)
+	if err != nil {
+		apperror.Write(w, errInvalidBody)
+		return
+	}
+
+	user.ID = id
+	user.APIKey = ""
+	err = h.service.Update(&user, middlewares.Audit(r))
+	if err != nil {
+		apperror.Write(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(user)
+}
+
+// DELETE /api/pengguna/{id}
+// @Summary Delete User by ID
+// @Description Menghapus pengguna, API key-nya langsung tidak berlaku. Khusus pemilik (owner)
+// @Param id path int true "User ID"
+// @Tags   pengguna
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} apperror.Response "Invalid user ID"
+// @Failure 500 {object} apperror.Response "Failed to delete user"
+// @Router /api/pengguna/{id} [delete]
+func (h *UserHandler) Delete(w http.ResponseWriter, r *http.Request, id int) {
+	err := h.service.Delete(id, middlewares.Audit(r))
+	if err != nil {
+		apperror.Write(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "User deleted successfully",
+	})
+}
+
+// POST /api/pengguna/{id}/api-key
+// @Summary Rotate User API Key
+// @Description Membuat API key baru untuk pengguna, key lama langsung tidak berlaku. Key baru hanya ditampilkan sekali. Khusus pemilik (owner)
+// @Tags   pengguna
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} models.User
+// @Failure 500 {object} apperror.Response "Failed to rotate API key"
+// @Router /api/pengguna/{id}/api-key [post]
+func (h *UserHandler) RotateAPIKey(w http.ResponseWriter, r *http.Request, id int) {
+	user, err := h.service.RotateAPIKey(id, middlewares.Audit(r))
+	if err != nil {
+		apperror.Write(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(user)
+}
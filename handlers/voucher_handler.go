@@ -0,0 +1,245 @@
+package handlers
+
+import (
+	"encoding/json"
+	"kasir-api/apperror"
+	"kasir-api/middlewares"
+	"kasir-api/models"
+	"kasir-api/services"
+	"net/http"
+	"strings"
+)
+
+type VoucherHandler struct {
+	service *services.VoucherService
+}
+
+func NewVoucherHandler(service *services.VoucherService) *VoucherHandler {
+	return &VoucherHandler{service: service}
+}
+
+// GET /api/voucher
+// @Summary      Get All Vouchers
+// @Description  Mengambil semua voucher beserta jumlah pemakaiannya
+// @Tags         voucher
+// @Produce      json
+// @Success      200      {array}   models.Voucher
+// @Failure      500      {object}  apperror.Response "Failed to get vouchers"
+// @Router       /api/voucher [get]
+func (h *VoucherHandler) HandleVouchers(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		vouchers, err := h.service.GetVouchers()
+		if err != nil {
+			apperror.Write(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(vouchers)
+	case http.MethodPost:
+		h.CreateVoucher(w, r)
+	default:
+		apperror.Write(w, apperror.MethodNotAllowed)
+	}
+}
+
+// POST /api/voucher
+// @Summary Create New Voucher
+// @Description Menerbitkan voucher: { code, type (fixed/percent), value, max_discount, min_spend, usage_limit, product_ids, category_ids, starts_at, expires_at, active }. Kode dibuat otomatis jika kosong. Khusus pemilik (owner)
+// @Accept json
+// @Tags   voucher
+// @Produce json
+// @Param voucher body models.Voucher true "New Voucher Data"
+// @Success 201 {object} models.Voucher
+// @Failure 400 {object} apperror.Response "Invalid request body"
+// @Failure 403 {object} apperror.Response "Owner access required"
+// @Failure 409 {object} apperror.Response "Voucher code already exists"
+// @Failure 500 {object} apperror.Response "Failed to create voucher"
+// @Router /api/voucher [post]
+func (h *VoucherHandler) CreateVoucher(w http.ResponseWriter, r *http.Request) {
+	if !requireOwner(w, r) {
+		return
+	}
+	voucher := models.Voucher{Active: true}
+	err := json.NewDecoder(r.Body).Decode(&voucher)
+	if err != nil {
+		apperror.Write(w, errInvalidBody)
+		return
+	}
+
+	err = h.service.CreateVoucher(&voucher, middlewares.Audit(r))
+	if err != nil {
+		apperror.Write(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(voucher)
+}
+
+func (h *VoucherHandler) HandleVoucherByCode(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		apperror.Write(w, apperror.MethodNotAllowed)
+		return
+	}
+
+	code, action := splitCodePath(r.URL.Path, "/api/voucher/")
+	switch action {
+	case "":
+		h.GetVoucher(w, r, code)
+	case "riwayat":
+		h.GetVoucherRedemptions(w, r, code)
+	default:
+		apperror.Write(w, errRouteNotFound)
+	}
+}
+
+// GET /api/voucher/{code}
+// @Summary      Get Voucher by Code
+// @Description  Mengambil voucher berdasarkan kode
+// @Tags         voucher
+// @Produce      json
+// @Param        code     path      string  true  "Voucher Code"
+// @Success      200      {object}  models.Voucher
+// @Failure      404      {object}  apperror.Response "Voucher not found"
+// @Router       /api/voucher/{code} [get]
+func (h *VoucherHandler) GetVoucher(w http.ResponseWriter, r *http.Request, code string) {
+	voucher, err := h.service.GetVoucherByCode(code)
+	writeLookup(w, voucher, err)
+}
+
+// GET /api/voucher/{code}/riwayat
+// @Summary      Get Voucher Redemption History
+// @Description  Mengambil riwayat pemakaian voucher pada transaksi
+// @Tags         voucher
+// @Produce      json
+// @Param        code     path      string  true  "Voucher Code"
+// @Success      200      {array}   models.Redemption
+// @Failure      404      {object}  apperror.Response "Voucher not found"
+// @Router       /api/voucher/{code}/riwayat [get]
+func (h *VoucherHandler) GetVoucherRedemptions(w http.ResponseWriter, r *http.Request, code string) {
+	redemptions, err := h.service.GetVoucherRedemptions(code)
+	writeLookup(w, redemptions, err)
+}
+
+// GET /api/gift-card
+// @Summary      Get All Gift Cards
+// @Description  Mengambil semua kartu hadiah beserta sisa saldonya
+// @Tags         gift-card
+// @Produce      json
+// @Success      200      {array}   models.GiftCard
+// @Failure      500      {object}  apperror.Response "Failed to get gift cards"
+// @Router       /api/gift-card [get]
+func (h *VoucherHandler) HandleGiftCards(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		cards, err := h.service.GetGiftCards()
+		if err != nil {
+			apperror.Write(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(cards)
+	case http.MethodPost:
+		h.CreateGiftCard(w, r)
+	default:
+		apperror.Write(w, apperror.MethodNotAllowed)
+	}
+}
+
+// POST /api/gift-card
+// @Summary Issue Gift Card
+// @Description Menerbitkan kartu hadiah bersaldo: { code, initial_balance, expires_at }. Kode dibuat otomatis jika kosong. Khusus pemilik (owner)
+// @Accept json
+// @Tags   gift-card
+// @Produce json
+// @Param gift_card body models.GiftCard true "New Gift Card Data"
+// @Success 201 {object} models.GiftCard
+// @Failure 400 {object} apperror.Response "Invalid request body"
+// @Failure 403 {object} apperror.Response "Owner access required"
+// @Failure 409 {object} apperror.Response "Gift card code already exists"
+// @Failure 500 {object} apperror.Response "Failed to create gift card"
+// @Router /api/gift-card [post]
+func (h *VoucherHandler) CreateGiftCard(w http.ResponseWriter, r *http.Request) {
+	if !requireOwner(w, r) {
+		return
+	}
+	card := models.GiftCard{Active: true}
+	err := json.NewDecoder(r.Body).Decode(&card)
+	if err != nil {
+		apperror.Write(w, errInvalidBody)
+		return
+	}
+
+	err = h.service.CreateGiftCard(&card, middlewares.Audit(r))
+	if err != nil {
+		apperror.Write(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(card)
+}
+
+func (h *VoucherHandler) HandleGiftCardByCode(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		apperror.Write(w, apperror.MethodNotAllowed)
+		return
+	}
+
+	code, action := splitCodePath(r.URL.Path, "/api/gift-card/")
+	switch action {
+	case "":
+		h.GetGiftCard(w, r, code)
+	case "riwayat":
+		h.GetGiftCardRedemptions(w, r, code)
+	default:
+		apperror.Write(w, errRouteNotFound)
+	}
+}
+
+// GET /api/gift-card/{code}
+// @Summary      Get Gift Card by Code
+// @Description  Mengambil kartu hadiah berdasarkan kode, termasuk sisa saldo
+// @Tags         gift-card
+// @Produce      json
+// @Param        code     path      string  true  "Gift Card Code"
+// @Success      200      {object}  models.GiftCard
+// @Failure      404      {object}  apperror.Response "Gift card not found"
+// @Router       /api/gift-card/{code} [get]
+func (h *VoucherHandler) GetGiftCard(w http.ResponseWriter, r *http.Request, code string) {
+	card, err := h.service.GetGiftCardByCode(code)
+	writeLookup(w, card, err)
+}
+
+// GET /api/gift-card/{code}/riwayat
+// @Summary      Get Gift Card Redemption History
+// @Description  Mengambil riwayat pemakaian kartu hadiah pada transaksi
+// @Tags         gift-card
+// @Produce      json
+// @Param        code     path      string  true  "Gift Card Code"
+// @Success      200      {array}   models.Redemption
+// @Failure      404      {object}  apperror.Response "Gift card not found"
+// @Router       /api/gift-card/{code}/riwayat [get]
+func (h *VoucherHandler) GetGiftCardRedemptions(w http.ResponseWriter, r *http.Request, code string) {
+	redemptions, err := h.service.GetGiftCardRedemptions(code)
+	writeLookup(w, redemptions, err)
+}
+
+func splitCodePath(path string, prefix string) (string, string) {
+	rest := strings.TrimSuffix(strings.TrimPrefix(path, prefix), "/")
+	code, action, _ := strings.Cut(rest, "/")
+	return code, action
+}
+
+// writeLookup menulis hasil pencarian berdasarkan kode; error dianggap data tidak ditemukan.
+func writeLookup(w http.ResponseWriter, data interface{}, err error) {
+	if err != nil {
+		apperror.Write(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(data)
+}
@@ -0,0 +1,189 @@
+// Package images memeriksa foto produk yang diunggah dan membuat thumbnail dalam beberapa ukuran.
+package images
+
+import (
+	"bytes"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"kasir-api/apperror"
+	"kasir-api/models"
+	"net/http"
+	"path"
+	"strings"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+const (
+	// MaxSize adalah ukuran file maksimum yang diterima
+	MaxSize = 5 << 20
+	// MaxDimension membatasi lebar/tinggi agar file kecil dengan resolusi raksasa tidak menghabiskan memori
+	MaxDimension = 6000
+
+	jpegQuality = 85
+
+	// URLPrefix adalah path publik tempat file gambar dilayani
+	URLPrefix = "/api/gambar/"
+)
+
+// Sizes adalah ukuran thumbnail (sisi terpanjang, piksel). Gambar yang lebih kecil tidak diperbesar.
+var Sizes = []Size{
+	{Name: "sm", Pixels: 160},
+	{Name: "md", Pixels: 320},
+	{Name: "lg", Pixels: 640},
+}
+
+type Size struct {
+	Name   string
+	Pixels int
+}
+
+// contentTypes adalah jenis file yang diterima beserta ekstensi file aslinya
+var contentTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/webp": ".webp",
+}
+
+var (
+	ErrUnsupportedType = apperror.New(apperror.CodeUnsupportedMediaType, "image must be JPEG, PNG or WebP")
+	ErrTooLarge        = apperror.New(apperror.CodePayloadTooLarge, "image must not exceed 5 MB")
+	ErrInvalidImage    = apperror.BadRequest("invalid image file")
+)
+
+// File adalah satu file hasil olahan yang siap disimpan.
+type File struct {
+	Data        []byte
+	ContentType string
+}
+
+// Set berisi file asli dan thumbnail dari satu unggahan.
+type Set struct {
+	Original   File
+	Ext        string
+	Thumbnails map[string]File
+}
+
+// Process memeriksa jenis file dari isinya (bukan dari header Content-Type klien), memastikan
+// gambar bisa dibaca lalu membuat thumbnail. PNG tetap PNG agar transparansi terjaga;
+// JPEG dan WebP dijadikan JPEG.
+func Process(data []byte) (*Set, error) {
+	if len(data) > MaxSize {
+		return nil, ErrTooLarge
+	}
+	contentType := http.DetectContentType(data)
+	ext, ok := contentTypes[contentType]
+	if !ok {
+		return nil, ErrUnsupportedType
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+	if config.Width > MaxDimension || config.Height > MaxDimension {
+		return nil, apperror.BadRequest("image dimensions must not exceed %dx%d", MaxDimension, MaxDimension)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+
+	set := &Set{
+		Original:   File{Data: data, ContentType: contentType},
+		Ext:        ext,
+		Thumbnails: make(map[string]File, len(Sizes)),
+	}
+	for _, size := range Sizes {
+		var buf bytes.Buffer
+		thumb := resize(img, size.Pixels)
+		if contentType == "image/png" {
+			err = png.Encode(&buf, thumb)
+		} else {
+			err = jpeg.Encode(&buf, thumb, &jpeg.Options{Quality: jpegQuality})
+		}
+		if err != nil {
+			return nil, err
+		}
+		set.Thumbnails[size.Name] = File{Data: buf.Bytes(), ContentType: thumbnailType(ext)}
+	}
+	return set, nil
+}
+
+// resize mengecilkan img sehingga sisi terpanjangnya maksimal max piksel, perbandingan sisi tetap.
+func resize(img image.Image, max int) image.Image {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	longest := width
+	if height > longest {
+		longest = height
+	}
+	if longest < max {
+		max = longest
+	}
+
+	w, h := max, max
+	if width > height {
+		h = height * max / width
+	} else {
+		w = width * max / height
+	}
+	if w < 1 {
+		w = 1
+	}
+	if h < 1 {
+		h = 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Src, nil)
+	return dst
+}
+
+func thumbnailType(ext string) string {
+	if ext == ".png" {
+		return "image/png"
+	}
+	return "image/jpeg"
+}
+
+func thumbnailExt(ext string) string {
+	if ext == ".png" {
+		return ".png"
+	}
+	return ".jpg"
+}
+
+// ThumbnailKey mengembalikan key thumbnail ukuran size untuk key file asli,
+// contoh produk/12/3f9a1c2b.webp menjadi produk/12/3f9a1c2b-sm.jpg.
+func ThumbnailKey(key string, size string) string {
+	ext := path.Ext(key)
+	return strings.TrimSuffix(key, ext) + "-" + size + thumbnailExt(ext)
+}
+
+// Keys mengembalikan key file asli beserta semua thumbnail-nya.
+func Keys(key string) []string {
+	keys := []string{key}
+	for _, size := range Sizes {
+		keys = append(keys, ThumbnailKey(key, size.Name))
+	}
+	return keys
+}
+
+// ProductImage menyusun URL gambar produk dari key file asli. Key kosong berarti produk tanpa gambar.
+func ProductImage(key string) *models.ProductImage {
+	if key == "" {
+		return nil
+	}
+	img := &models.ProductImage{
+		URL:        URLPrefix + key,
+		Thumbnails: make(map[string]string, len(Sizes)),
+	}
+	for _, size := range Sizes {
+		img.Thumbnails[size.Name] = URLPrefix + ThumbnailKey(key, size.Name)
+	}
+	return img
+}
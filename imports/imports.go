@@ -0,0 +1,171 @@
+// Package imports membaca file CSV atau XLSX untuk impor massal. Judul kolom di baris pertama
+// dipetakan ke nama field, sehingga urutan kolom dan bahasa judulnya bebas.
+package imports
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"kasir-api/apperror"
+	"path"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Format masukan yang didukung
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+// MaxRows membatasi jumlah baris data dalam satu file impor.
+const MaxRows = 10000
+
+// ErrInvalidFile menandai file yang tidak bisa dibaca sebagai CSV/XLSX atau isinya tidak sesuai.
+var ErrInvalidFile = apperror.BadRequest("invalid import file")
+
+// Record adalah satu baris data. Row adalah nomor baris di file (judul kolom di baris 1)
+// dan Values berisi nilai per nama field.
+type Record struct {
+	Row    int
+	Values map[string]string
+}
+
+// Sheet adalah hasil baca file: field yang kolomnya ditemukan dan baris datanya.
+type Sheet struct {
+	Fields  []string
+	Records []Record
+}
+
+// Has mengembalikan true jika file memiliki kolom untuk field tersebut.
+func (s *Sheet) Has(field string) bool {
+	for _, f := range s.Fields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// DetectFormat menentukan format dari nama file, lalu Content-Type. Default CSV.
+func DetectFormat(filename string, contentType string) string {
+	switch strings.ToLower(path.Ext(filename)) {
+	case ".xlsx":
+		return FormatXLSX
+	case ".csv", ".txt":
+		return FormatCSV
+	}
+	if strings.Contains(contentType, "spreadsheetml.sheet") {
+		return FormatXLSX
+	}
+	return FormatCSV
+}
+
+// Read membaca file dengan format tersebut. aliases memetakan judul kolom (huruf kecil) ke
+// nama field; kolom yang tidak dikenal diabaikan dan baris kosong dilewati.
+func Read(r io.Reader, format string, aliases map[string]string) (*Sheet, error) {
+	var rows [][]string
+	var err error
+	if format == FormatXLSX {
+		rows, err = readXLSX(r)
+	} else {
+		rows, err = readCSV(r)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidFile, err.Error())
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w: file is empty", ErrInvalidFile)
+	}
+	if len(rows)-1 > MaxRows {
+		return nil, fmt.Errorf("%w: at most %d rows per import", ErrInvalidFile, MaxRows)
+	}
+
+	sheet := &Sheet{Fields: make([]string, 0)}
+	columns := make(map[int]string)
+	for i, title := range rows[0] {
+		field, ok := aliases[normalizeTitle(title)]
+		if !ok {
+			continue
+		}
+		if sheet.Has(field) {
+			return nil, fmt.Errorf("%w: more than one column for %s", ErrInvalidFile, field)
+		}
+		columns[i] = field
+		sheet.Fields = append(sheet.Fields, field)
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("%w: no known column in the header row", ErrInvalidFile)
+	}
+
+	sheet.Records = make([]Record, 0, len(rows)-1)
+	for n, row := range rows[1:] {
+		record := Record{Row: n + 2, Values: make(map[string]string, len(columns))}
+		empty := true
+		for i, field := range columns {
+			if i < len(row) {
+				value := unescapeFormula(strings.TrimSpace(row[i]))
+				record.Values[field] = value
+				if value != "" {
+					empty = false
+				}
+			}
+		}
+		if !empty {
+			sheet.Records = append(sheet.Records, record)
+		}
+	}
+	return sheet, nil
+}
+
+// unescapeFormula membuang awalan ' yang ditambahkan ekspor CSV pada teks yang menyerupai rumus,
+// sehingga file ekspor katalog bisa diimpor kembali apa adanya.
+func unescapeFormula(value string) string {
+	if len(value) > 1 && value[0] == '\'' && strings.ContainsRune("=+-@", rune(value[1])) {
+		return value[1:]
+	}
+	return value
+}
+
+func normalizeTitle(title string) string {
+	return strings.Join(strings.Fields(strings.ToLower(title)), " ")
+}
+
+// readCSV menerima pemisah koma maupun titik koma (bawaan Excel dengan locale Indonesia).
+func readCSV(r io.Reader) ([][]string, error) {
+	br := bufio.NewReader(r)
+	if bom, err := br.Peek(3); err == nil && bytes.Equal(bom, []byte{0xEF, 0xBB, 0xBF}) {
+		br.Discard(3)
+	}
+
+	// Peek mengembalikan data yang tersedia walaupun file lebih pendek dari 4096 byte
+	firstLine, _ := br.Peek(4096)
+	if i := bytes.IndexByte(firstLine, '\n'); i >= 0 {
+		firstLine = firstLine[:i]
+	}
+
+	reader := csv.NewReader(br)
+	reader.FieldsPerRecord = -1
+	if bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+		reader.Comma = ';'
+	}
+	return reader.ReadAll()
+}
+
+// readXLSX membaca sheet pertama.
+func readXLSX(r io.Reader) ([][]string, error) {
+	file, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	sheets := file.GetSheetList()
+	if len(sheets) == 0 {
+		return nil, fmt.Errorf("workbook has no sheet")
+	}
+	return file.GetRows(sheets[0])
+}
@@ -6,20 +6,47 @@ import (
 	_ "kasir-api/docs"
 	"kasir-api/handlers"
 	"kasir-api/middlewares"
+	"kasir-api/models"
+	"kasir-api/receipts"
 	"kasir-api/repositories"
 	"kasir-api/services"
+	"kasir-api/storage"
 	"net/http"
 	"os"
 	"strings"
+	"time"
+	_ "time/tzdata"
 
 	"github.com/spf13/viper"
 	httpSwagger "github.com/swaggo/http-swagger"
 )
 
 type Config struct {
-	Port   string `mapstructure:"PORT"`
-	DBConn string `mapstructure:"DB_CONN"`
-	APIKey string `mapstructure:"API_KEY"`
+	Port     string `mapstructure:"PORT"`
+	DBConn   string `mapstructure:"DB_CONN"`
+	APIKey   string `mapstructure:"API_KEY"`
+	Timezone string `mapstructure:"TIMEZONE"`
+
+	StoreName             string `mapstructure:"STORE_NAME"`
+	StoreAddress          string `mapstructure:"STORE_ADDRESS"`
+	StorePhone            string `mapstructure:"STORE_PHONE"`
+	StoreNPWP             string `mapstructure:"STORE_NPWP"`
+	ReceiptFooter         string `mapstructure:"RECEIPT_FOOTER"`
+	ReceiptHeaderTemplate string `mapstructure:"RECEIPT_HEADER_TEMPLATE"`
+
+	LoyaltyEarnAmount int `mapstructure:"LOYALTY_EARN_AMOUNT"`
+	LoyaltyPointValue int `mapstructure:"LOYALTY_POINT_VALUE"`
+	LoyaltyExpiryDays int `mapstructure:"LOYALTY_EXPIRY_DAYS"`
+
+	CartReservationMinutes int `mapstructure:"CART_RESERVATION_MINUTES"`
+
+	// Folder penyimpanan foto produk
+	ImageDir string `mapstructure:"IMAGE_DIR"`
+
+	// Mode SaaS: satu schema Postgres per tenant
+	MultiTenant    bool   `mapstructure:"MULTI_TENANT"`
+	BaseDomain     string `mapstructure:"BASE_DOMAIN"`
+	PlatformAPIKey string `mapstructure:"PLATFORM_API_KEY"`
 }
 
 func main() {
@@ -27,18 +54,24 @@ func main() {
 	// @version 1.0.1
 	// @description API untuk aplikasi manajemen kasir yang di-update dengan menggunakan database PostgreSQL. Terdapat penambahan endpoint untuk mengelola kategori produk serta relasi antara produk dan kategori.
 
-	viper.AutomaticEnv()
-	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	config := loadConfig()
 
-	if _, err := os.Stat(".env"); err == nil {
-		viper.SetConfigFile(".env")
-		_ = viper.ReadInConfig()
+	// Perintah CLI (export-catalog, backup, restore) dijalankan lalu keluar tanpa menyalakan server
+	if len(os.Args) > 1 {
+		os.Exit(runCommand(config, os.Args[1:]))
 	}
 
-	config := Config{
-		Port:   viper.GetString("PORT"),
-		DBConn: viper.GetString("DB_CONN"),
-		APIKey: viper.GetString("API_KEY"),
+	loyaltyRules := models.LoyaltyRules{
+		EarnAmount: config.LoyaltyEarnAmount,
+		PointValue: config.LoyaltyPointValue,
+		ExpiryDays: config.LoyaltyExpiryDays,
+	}
+
+	// Zona waktu toko, dipakai untuk menentukan batas hari pada laporan
+	location, err := time.LoadLocation(config.Timezone)
+	if err != nil {
+		fmt.Println("Zona waktu tidak valid:", err.Error())
+		return
 	}
 
 	db, err := database.InitDB(config.DBConn)
@@ -49,26 +82,69 @@ func main() {
 
 	defer db.Close()
 
-	apiKeyMiddleware := middlewares.APIkey(config.APIKey)
+	// RECEIPT_HEADER_TEMPLATE berisi path file template kepala struk (opsional)
+	headerTemplate := ""
+	if config.ReceiptHeaderTemplate != "" {
+		content, err := os.ReadFile(config.ReceiptHeaderTemplate)
+		if err != nil {
+			fmt.Println("Gagal membaca template struk:", err.Error())
+			return
+		}
+		headerTemplate = string(content)
+	}
+
+	receiptPrinter, err := receipts.NewPrinter(receipts.Store{
+		Name:    config.StoreName,
+		Address: config.StoreAddress,
+		Phone:   config.StorePhone,
+		NPWP:    config.StoreNPWP,
+		Footer:  config.ReceiptFooter,
+	}, headerTemplate)
+	if err != nil {
+		fmt.Println("Template struk tidak valid:", err.Error())
+		return
+	}
+
+	opts := appOptions{
+		location:       location,
+		loyaltyRules:   loyaltyRules,
+		receiptPrinter: receiptPrinter,
+		cartTTL:        time.Duration(config.CartReservationMinutes) * time.Minute,
+		imageStore:     storage.NewLocal(config.ImageDir),
+		publicCatalog:  !config.MultiTenant,
+	}
+
+	if config.MultiTenant {
+		// Setiap tenant memakai schema sendiri; schema public hanya berisi daftar tenant
+		if err := database.MigratePlatform(db); err != nil {
+			fmt.Println("Gagal menjalankan migrasi platform:", err.Error())
+			return
+		}
+
+		tenantRepo := repositories.NewTenantRepository(db)
+		tenantService := services.NewTenantService(tenantRepo, config.DBConn, config.BaseDomain)
+		tenantHandler := handlers.NewTenantHandler(tenantService)
+
+		routers := newTenantRouters(config.DBConn, opts)
+		defer routers.Close()
 
-	// var categories = models.DataCategories
-	productRepo := repositories.NewProductRepository(db)
-	productService := services.NewProductService(productRepo)
-	productHandler := handlers.NewProductHandler(productService)
+		platformKeyMiddleware := middlewares.PlatformKey(config.PlatformAPIKey)
 
-	transactionRepo := repositories.NewTransactionRepository(db)
-	transactionService := services.NewTransactionService(transactionRepo)
-	transactionHandler := handlers.NewTransactionHandler(transactionService)
+		http.HandleFunc("/api/platform/tenant", middlewares.CORS(middlewares.Logger(platformKeyMiddleware(tenantHandler.HandleTenants))))
+		http.HandleFunc("/api/platform/tenant/", middlewares.CORS(middlewares.Logger(platformKeyMiddleware(tenantHandler.HandleTenantByID))))
+		http.HandleFunc("/api/", middlewares.CORS(middlewares.Tenant(tenantService.Resolve, routers.handlerFor)))
+	} else {
+		if err := database.Migrate(db); err != nil {
+			fmt.Println("Gagal menjalankan migrasi database:", err.Error())
+			return
+		}
 
-	reportRepo := repositories.NewReportRepository(db)
-	reportService := services.NewReportService(reportRepo)
-	reportHandler := handlers.NewReportHandler(reportService)
+		stopScheduler := startPriceScheduler(db, opts)
+		defer stopScheduler()
 
-	http.HandleFunc("/api/kategori", middlewares.CORS(middlewares.Logger(productHandler.GetCategories)))
-	http.HandleFunc("/api/produk", middlewares.CORS(middlewares.Logger(productHandler.HandleProducts)))
-	http.HandleFunc("/api/produk/", middlewares.CORS(middlewares.Logger(apiKeyMiddleware(productHandler.HandleProductByID))))
-	http.HandleFunc("/api/report/", middlewares.CORS(middlewares.Logger(reportHandler.HandleReport)))
-	http.HandleFunc("/api/checkout", middlewares.CORS(middlewares.Logger(apiKeyMiddleware(transactionHandler.HandleCheckout))))
+		// API_KEY dari konfigurasi berlaku sebagai pemilik toko
+		http.Handle("/api/", newRouter(db, services.HashAPIKey(config.APIKey), opts))
+	}
 
 	http.HandleFunc("/swagger/", httpSwagger.WrapHandler)
 
@@ -94,3 +170,49 @@ func main() {
 		fmt.Println("Gagal menjalankan server karena", err.Error())
 	}
 }
+
+// loadConfig membaca konfigurasi dari environment dan file .env (jika ada).
+func loadConfig() Config {
+	viper.AutomaticEnv()
+	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	viper.SetDefault("TIMEZONE", "Asia/Jakarta")
+	viper.SetDefault("STORE_NAME", "Kasir API")
+	viper.SetDefault("RECEIPT_FOOTER", "Terima kasih atas kunjungan Anda")
+	// 1 poin per Rp10.000, 1 poin bernilai Rp100, berlaku 1 tahun
+	viper.SetDefault("LOYALTY_EARN_AMOUNT", 10000)
+	viper.SetDefault("LOYALTY_POINT_VALUE", 100)
+	viper.SetDefault("LOYALTY_EXPIRY_DAYS", 365)
+	viper.SetDefault("CART_RESERVATION_MINUTES", 15)
+	viper.SetDefault("IMAGE_DIR", "uploads")
+
+	if _, err := os.Stat(".env"); err == nil {
+		viper.SetConfigFile(".env")
+		_ = viper.ReadInConfig()
+	}
+
+	return Config{
+		Port:     viper.GetString("PORT"),
+		DBConn:   viper.GetString("DB_CONN"),
+		APIKey:   viper.GetString("API_KEY"),
+		Timezone: viper.GetString("TIMEZONE"),
+
+		StoreName:             viper.GetString("STORE_NAME"),
+		StoreAddress:          viper.GetString("STORE_ADDRESS"),
+		StorePhone:            viper.GetString("STORE_PHONE"),
+		StoreNPWP:             viper.GetString("STORE_NPWP"),
+		ReceiptFooter:         viper.GetString("RECEIPT_FOOTER"),
+		ReceiptHeaderTemplate: viper.GetString("RECEIPT_HEADER_TEMPLATE"),
+
+		LoyaltyEarnAmount: viper.GetInt("LOYALTY_EARN_AMOUNT"),
+		LoyaltyPointValue: viper.GetInt("LOYALTY_POINT_VALUE"),
+		LoyaltyExpiryDays: viper.GetInt("LOYALTY_EXPIRY_DAYS"),
+
+		CartReservationMinutes: viper.GetInt("CART_RESERVATION_MINUTES"),
+
+		ImageDir: viper.GetString("IMAGE_DIR"),
+
+		MultiTenant:    viper.GetBool("MULTI_TENANT"),
+		BaseDomain:     viper.GetString("BASE_DOMAIN"),
+		PlatformAPIKey: viper.GetString("PLATFORM_API_KEY"),
+	}
+}
@@ -1,24 +1,80 @@
 package middlewares
 
-import "net/http"
+import (
+	"context"
+	"kasir-api/apperror"
+	"kasir-api/models"
+	"net/http"
+)
+
+type contextKey string
+
+const userKey contextKey = "user"
+
+var (
+	errAPIKeyRequired = apperror.Unauthorized("API Key Required")
+	errInvalidAPIKey  = apperror.Unauthorized("Invalid API Key")
+	errOwnerRequired  = apperror.Forbidden("Owner access required")
+)
 
 // func (api key) func handler http.handler
-func APIkey(validApiKEY string) func(http.HandlerFunc) http.HandlerFunc {
+// lookup mengenali key pemilik (owner) maupun key pengguna yang terdaftar,
+// dan mengembalikan nil jika key tidak dikenal.
+func APIkey(lookup func(apiKey string) (*models.User, error)) func(http.HandlerFunc) http.HandlerFunc {
 	return func(next http.HandlerFunc) http.HandlerFunc {
 		return func(w http.ResponseWriter, r *http.Request) {
 			apiKey := r.Header.Get("X-Api-Key")
 
 			if apiKey == "" {
-				http.Error(w, "API Key Required", http.StatusUnauthorized)
+				apperror.Write(w, errAPIKeyRequired)
+				return
+			}
+
+			user, err := lookup(apiKey)
+			if err != nil {
+				apperror.Write(w, err)
 				return
 			}
 
-			if apiKey != validApiKEY {
-				http.Error(w, "Invalid API Key", http.StatusUnauthorized)
+			if user == nil {
+				apperror.Write(w, errInvalidAPIKey)
+				return
+			}
+
+			next(w, r.WithContext(context.WithValue(r.Context(), userKey, user)))
+		}
+	}
+}
+
+// PublicReads membuka GET tanpa API key dan meneruskan method lain ke auth (biasanya APIkey).
+// GET yang membawa X-Api-Key tetap melewati auth agar pengguna yang login dikenali.
+func PublicReads(auth func(http.HandlerFunc) http.HandlerFunc) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		protected := auth(next)
+		return func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodGet && r.Header.Get("X-Api-Key") == "" {
+				next(w, r)
 				return
 			}
+			protected(w, r)
+		}
+	}
+}
+
+// CurrentUser mengembalikan pengguna yang lolos APIkey, atau nil pada endpoint publik.
+func CurrentUser(r *http.Request) *models.User {
+	user, _ := r.Context().Value(userKey).(*models.User)
+	return user
+}
 
-			next(w, r)
+// OwnerOnly membatasi endpoint untuk pemilik. Dipasang setelah APIkey.
+func OwnerOnly(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user := CurrentUser(r)
+		if user == nil || !user.IsOwner() {
+			apperror.Write(w, errOwnerRequired)
+			return
 		}
+		next(w, r)
 	}
 }
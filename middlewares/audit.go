@@ -0,0 +1,40 @@
+package middlewares
+
+import (
+	"kasir-api/models"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// Audit mengumpulkan pelaku dan asal request untuk dicatat di audit log.
+func Audit(r *http.Request) models.AuditMeta {
+	meta := models.AuditMeta{
+		UserAgent: r.UserAgent(),
+		RequestID: RequestID(r),
+		IP:        clientIP(r),
+	}
+
+	if user := CurrentUser(r); user != nil {
+		meta.Actor = user.Name
+		if user.ID != 0 {
+			id := user.ID
+			meta.UserID = &id
+		}
+	}
+	return meta
+}
+
+// clientIP memakai alamat pertama X-Forwarded-For jika server berada di belakang proxy.
+func clientIP(r *http.Request) string {
+	if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+		ip, _, _ := strings.Cut(forwarded, ",")
+		return strings.TrimSpace(ip)
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -5,8 +5,9 @@ import "net/http"
 func CORS(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-Api-Key, Authorization")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-Api-Key, X-Outlet-ID, X-Tenant-ID, X-Request-ID, If-Match, If-None-Match, Authorization")
+		w.Header().Set("Access-Control-Expose-Headers", "X-Request-ID, ETag")
 		if r.Method == "OPTIONS" {
 			w.WriteHeader(http.StatusOK)
 			return
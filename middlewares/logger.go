@@ -1,20 +1,40 @@
 package middlewares
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"log"
 	"net/http"
 	"time"
 )
 
+const requestIDKey contextKey = "request_id"
+
 func Logger(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
 
-		log.Printf("[REQUEST] %s %s dari %s", r.Method, r.RequestURI, r.RemoteAddr)
+		// Pakai X-Request-ID dari klien/proxy jika ada, supaya log dan audit bisa dicocokkan
+		requestID := r.Header.Get("X-Request-ID")
+		if requestID == "" || len(requestID) > 64 {
+			b := make([]byte, 8)
+			rand.Read(b)
+			requestID = hex.EncodeToString(b)
+		}
+		w.Header().Set("X-Request-ID", requestID)
+
+		log.Printf("[REQUEST] %s %s %s dari %s", requestID, r.Method, r.RequestURI, r.RemoteAddr)
 
-		next(w, r)
+		next(w, r.WithContext(context.WithValue(r.Context(), requestIDKey, requestID)))
 
 		duration := time.Since(start)
-		log.Printf("\n[DONE] %s %s selesai dalam %v", r.Method, r.RequestURI, duration)
+		log.Printf("\n[DONE] %s %s %s selesai dalam %v", requestID, r.Method, r.RequestURI, duration)
 	}
 }
+
+// RequestID mengembalikan id request yang dibuat Logger.
+func RequestID(r *http.Request) string {
+	requestID, _ := r.Context().Value(requestIDKey).(string)
+	return requestID
+}
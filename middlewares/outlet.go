@@ -0,0 +1,40 @@
+package middlewares
+
+import (
+	"context"
+	"kasir-api/apperror"
+	"kasir-api/models"
+	"net/http"
+)
+
+const outletKey contextKey = "outlet"
+
+// Outlet menentukan outlet request dari header X-Outlet-ID atau query outlet_id dan
+// memastikan pengguna boleh mengaksesnya. Dipasang setelah APIkey jika endpoint memerlukan login.
+func Outlet(resolve func(user *models.User, requested string) (int, error)) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			requested := r.Header.Get("X-Outlet-ID")
+			if requested == "" {
+				requested = r.URL.Query().Get("outlet_id")
+			}
+
+			outletID, err := resolve(CurrentUser(r), requested)
+			if err != nil {
+				apperror.Write(w, err)
+				return
+			}
+
+			next(w, r.WithContext(context.WithValue(r.Context(), outletKey, outletID)))
+		}
+	}
+}
+
+// OutletID mengembalikan outlet yang dipilih middleware Outlet.
+func OutletID(r *http.Request) int {
+	outletID, ok := r.Context().Value(outletKey).(int)
+	if !ok {
+		return models.DefaultOutletID
+	}
+	return outletID
+}
@@ -0,0 +1,48 @@
+package middlewares
+
+import (
+	"crypto/subtle"
+	"kasir-api/apperror"
+	"kasir-api/models"
+	"net/http"
+)
+
+// Tenant menentukan tenant request lalu meneruskannya ke router milik tenant tersebut.
+// Router tenant memakai koneksi database yang hanya melihat schema tenant itu.
+func Tenant(resolve func(host string, requested string, apiKey string) (*models.Tenant, error), handlerFor func(tenant *models.Tenant) (http.Handler, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		tenant, err := resolve(r.Host, r.Header.Get("X-Tenant-ID"), r.Header.Get("X-Api-Key"))
+		if err != nil {
+			apperror.Write(w, err)
+			return
+		}
+
+		handler, err := handlerFor(tenant)
+		if err != nil {
+			apperror.Write(w, err)
+			return
+		}
+
+		handler.ServeHTTP(w, r)
+	}
+}
+
+// PlatformKey membatasi endpoint pengelolaan tenant untuk operator platform (PLATFORM_API_KEY).
+func PlatformKey(validKey string) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			apiKey := r.Header.Get("X-Api-Key")
+			if apiKey == "" {
+				apperror.Write(w, errAPIKeyRequired)
+				return
+			}
+
+			if validKey == "" || subtle.ConstantTimeCompare([]byte(apiKey), []byte(validKey)) != 1 {
+				apperror.Write(w, errInvalidAPIKey)
+				return
+			}
+
+			next(w, r)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Aksi yang dicatat di audit log
+const (
+	AuditCreate = "create"
+	AuditUpdate = "update"
+	AuditDelete = "delete"
+)
+
+// Entitas yang dicatat di audit log
+const (
+	AuditEntityProduct       = "product"
+	AuditEntityProductPrice  = "product_price"
+	AuditEntityOutletProduct = "outlet_product"
+	AuditEntityTransaction   = "transaction"
+	AuditEntityOutlet        = "outlet"
+	AuditEntityUser          = "user"
+	AuditEntityCategory      = "category"
+	AuditEntityVoucher       = "voucher"
+	AuditEntityGiftCard      = "gift_card"
+	AuditEntityCustomer      = "customer"
+)
+
+// AuditMeta adalah pelaku dan asal request yang melakukan perubahan.
+// UserID kosong untuk pemilik lewat API_KEY maupun request tanpa API key.
+type AuditMeta struct {
+	Actor     string
+	UserID    *int
+	IP        string
+	UserAgent string
+	RequestID string
+}
+
+// AuditChange adalah nilai satu field sebelum dan sesudah perubahan.
+type AuditChange struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// AuditEntry adalah satu baris audit log. Before kosong untuk create, After kosong untuk delete,
+// Changes hanya berisi field yang berubah pada update.
+type AuditEntry struct {
+	ID        int64                  `json:"id"`
+	Actor     string                 `json:"actor"`
+	UserID    *int                   `json:"user_id"`
+	Action    string                 `json:"action"`
+	Entity    string                 `json:"entity"`
+	EntityID  int                    `json:"entity_id"`
+	Before    json.RawMessage        `json:"before" swaggertype:"object"`
+	After     json.RawMessage        `json:"after" swaggertype:"object"`
+	Changes   map[string]AuditChange `json:"changes"`
+	IP        string                 `json:"ip"`
+	UserAgent string                 `json:"user_agent"`
+	RequestID string                 `json:"request_id"`
+	CreatedAt time.Time              `json:"created_at"`
+}
+
+// AuditFilter adalah filter GET /api/audit. Nilai kosong berarti tidak difilter.
+type AuditFilter struct {
+	Entity   string
+	EntityID int
+	Action   string
+	Actor    string
+	Start    time.Time
+	End      time.Time
+	Limit    int
+}
@@ -0,0 +1,53 @@
+package models
+
+// Jenis operasi massal produk
+const (
+	BulkSetPrice           = "set_price"
+	BulkAdjustPricePercent = "adjust_price_percent"
+	BulkAdjustPriceAmount  = "adjust_price_amount"
+	BulkSetStock           = "set_stock"
+	BulkAdjustStock        = "adjust_stock"
+	BulkSetCategory        = "set_category"
+)
+
+// BulkTarget memilih produk yang diubah. Kriteria yang diisi digabung dengan AND; minimal satu
+// kriteria harus diisi, atau All untuk semua produk. CategoryID 0 berarti produk tanpa kategori.
+type BulkTarget struct {
+	IDs        []int  `json:"ids,omitempty"`
+	CategoryID *int   `json:"category_id,omitempty"`
+	Name       string `json:"name,omitempty"`
+	All        bool   `json:"all,omitempty"`
+}
+
+// BulkOperation adalah perubahan yang diterapkan ke setiap produk. Value berarti harga baru
+// (set_price), persen (adjust_price_percent, contoh 5 atau -10), selisih rupiah
+// (adjust_price_amount), stok baru (set_stock), selisih stok (adjust_stock) atau id kategori
+// (set_category, 0 untuk tanpa kategori).
+type BulkOperation struct {
+	Type  string  `json:"type" example:"adjust_price_percent"`
+	Value float64 `json:"value" example:"5"`
+}
+
+type BulkProductRequest struct {
+	Target    BulkTarget    `json:"target"`
+	Operation BulkOperation `json:"operation"`
+	Note      string        `json:"note,omitempty"`
+}
+
+// BulkProductChange adalah nilai sebelum dan sesudah pada satu produk.
+type BulkProductChange struct {
+	ProductID int     `json:"product_id"`
+	Name      string  `json:"name"`
+	Field     string  `json:"field" example:"price"`
+	Before    float64 `json:"before" example:"10000"`
+	After     float64 `json:"after" example:"10500"`
+}
+
+// BulkProductResult adalah hasil operasi massal. Pada preview tidak ada yang disimpan.
+// Matched adalah jumlah produk yang terpilih, Changes hanya berisi produk yang nilainya berubah.
+type BulkProductResult struct {
+	Preview bool                `json:"preview"`
+	Matched int                 `json:"matched"`
+	Changed int                 `json:"changed"`
+	Changes []BulkProductChange `json:"changes"`
+}
@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+// Status keranjang
+const (
+	CartOpen       = "open"
+	CartHeld       = "held"
+	CartCheckedOut = "checked_out"
+	CartAbandoned  = "abandoned"
+)
+
+type Cart struct {
+	ID            int        `json:"id"`
+	OutletID      int        `json:"outlet_id"`
+	Status        string     `json:"status"`
+	Terminal      string     `json:"terminal"`
+	Note          string     `json:"note"`
+	CustomerID    *int       `json:"customer_id,omitempty"`
+	Reserve       bool       `json:"reserve"`
+	ReservedUntil *time.Time `json:"reserved_until,omitempty"`
+	TransactionID *int       `json:"transaction_id,omitempty"`
+	Items         []CartItem `json:"items"`
+	TotalAmount   int        `json:"total_amount"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// CartItem memakai harga dan stok terkini dari produk. Price dan Quantity dalam satuan jual (Unit),
+// BaseQuantity dan AvailableStock dalam satuan dasar produk. Subtotal sudah termasuk harga Modifiers.
+type CartItem struct {
+	ID             int                `json:"id"`
+	ProductID      int                `json:"product_id"`
+	ProductName    string             `json:"product_name"`
+	Unit           string             `json:"unit"`
+	Price          int                `json:"price"`
+	Quantity       float64            `json:"quantity"`
+	BaseQuantity   float64            `json:"base_quantity"`
+	Subtotal       int                `json:"subtotal"`
+	AvailableStock float64            `json:"available_stock"`
+	Modifiers      []SelectedModifier `json:"modifiers,omitempty"`
+}
+
+// CartCheckoutRequest berisi opsi checkout keranjang, item diambil dari keranjang.
+type CartCheckoutRequest struct {
+	CustomerPhone string `json:"customer_phone,omitempty"`
+	RedeemPoints  int    `json:"redeem_points,omitempty"`
+	VoucherCode   string `json:"voucher_code,omitempty"`
+	GiftCardCode  string `json:"gift_card_code,omitempty"`
+}
@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// Catalog adalah ekspor katalog: seluruh kategori dan produk aktif beserta harga dan stok di satu outlet.
+type Catalog struct {
+	ExportedAt time.Time    `json:"exported_at"`
+	OutletID   int          `json:"outlet_id"`
+	Categories []Categories `json:"categories"`
+	Products   []Product    `json:"products"`
+}
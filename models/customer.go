@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+type Customer struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	Phone     string    `json:"phone,omitempty"`
+	Email     string    `json:"email,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Jenis entri buku poin
+const (
+	LoyaltyEarn   = "earn"
+	LoyaltyRedeem = "redeem"
+	LoyaltyExpire = "expire"
+)
+
+type LoyaltyEntry struct {
+	ID            int        `json:"id"`
+	CustomerID    int        `json:"customer_id"`
+	TransactionID *int       `json:"transaction_id,omitempty"`
+	Type          string     `json:"type"`
+	Points        int        `json:"points"`
+	ExpiresAt     *time.Time `json:"expires_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+type LoyaltyBalance struct {
+	CustomerID int            `json:"customer_id"`
+	Balance    int            `json:"balance"`
+	PointValue int            `json:"point_value"`
+	Ledger     []LoyaltyEntry `json:"ledger"`
+}
+
+// LoyaltyRules mengatur perolehan dan penukaran poin.
+// EarnAmount: nominal belanja (Rp) untuk 1 poin, PointValue: nilai 1 poin (Rp) saat ditukar,
+// ExpiryDays: masa berlaku poin dalam hari (0 berarti tidak kedaluwarsa).
+type LoyaltyRules struct {
+	EarnAmount int
+	PointValue int
+	ExpiryDays int
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ETag produk terdiri dari versi produk dan versi stok/harga di outlet, karena PUT produk
+// juga mengganti stok outlet. Perubahan salah satunya membuat ETag lama tidak berlaku.
+func (p Product) ETag() string {
+	return fmt.Sprintf(`"%d-%d"`, p.Version, p.StockVersion)
+}
+
+// ETagMatches memeriksa apakah header If-Match / If-None-Match berisi etag.
+// "*" cocok dengan semua ETag dan awalan W/ diabaikan.
+func ETagMatches(header string, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,37 @@
+package models
+
+import "testing"
+
+func TestProductETag(t *testing.T) {
+	p := Product{Version: 3, StockVersion: 7}
+	if got := p.ETag(); got != `"3-7"` {
+		t.Fatalf("ETag() = %s, want \"3-7\"", got)
+	}
+}
+
+func TestETagMatches(t *testing.T) {
+	etag := `"3-7"`
+	cases := []struct {
+		name   string
+		header string
+		want   bool
+	}{
+		{"exact", `"3-7"`, true},
+		{"any", `*`, true},
+		{"weak prefix", `W/"3-7"`, true},
+		{"list", `"1-1", "3-7"`, true},
+		{"list without spaces", `"1-1","3-7"`, true},
+		{"stale version", `"2-7"`, false},
+		{"stale stock version", `"3-6"`, false},
+		{"unquoted", `3-7`, false},
+		{"list without match", `"1-1", W/"2-2"`, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := ETagMatches(tc.header, etag); got != tc.want {
+				t.Fatalf("ETagMatches(%s) = %v, want %v", tc.header, got, tc.want)
+			}
+		})
+	}
+}
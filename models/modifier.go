@@ -0,0 +1,39 @@
+package models
+
+// ModifierGroup adalah kelompok pilihan tambahan produk, contoh "Susu" (full cream, oat) atau
+// "Tambahan" (extra shot). Pembeli memilih minimal Min dan maksimal Max modifier dari satu kelompok;
+// Min 0 berarti kelompok boleh dilewati.
+type ModifierGroup struct {
+	ID        int        `json:"id,omitempty"`
+	Name      string     `json:"name"`
+	Min       int        `json:"min"`
+	Max       int        `json:"max"`
+	Modifiers []Modifier `json:"modifiers"`
+}
+
+// Modifier adalah satu pilihan dalam kelompok. Price adalah tambahan harga per satuan jual, 0 untuk
+// pilihan tanpa biaya seperti "kurang gula".
+type Modifier struct {
+	ID    int    `json:"id,omitempty"`
+	Name  string `json:"name"`
+	Price int    `json:"price"`
+}
+
+// SelectedModifier adalah modifier yang dipilih pada item keranjang atau transaksi. Pada transaksi,
+// nama kelompok, nama dan harga disimpan seperti saat checkout sehingga tidak ikut berubah jika
+// modifier diubah; ModifierID kosong jika modifier sudah dihapus.
+type SelectedModifier struct {
+	ModifierID int    `json:"modifier_id,omitempty"`
+	Group      string `json:"group"`
+	Name       string `json:"name"`
+	Price      int    `json:"price"`
+}
+
+// ModifierPrice menjumlahkan tambahan harga modifier per satuan jual.
+func ModifierPrice(modifiers []SelectedModifier) int {
+	total := 0
+	for _, m := range modifiers {
+		total += m.Price
+	}
+	return total
+}
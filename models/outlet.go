@@ -0,0 +1,85 @@
+package models
+
+import "time"
+
+// DefaultOutletID adalah outlet bawaan yang dibuat migrasi, dipakai jika request tidak memilih outlet.
+const DefaultOutletID = 1
+
+type Outlet struct {
+	ID        int       `json:"id"`
+	Code      string    `json:"code"`
+	Name      string    `json:"name"`
+	Address   string    `json:"address"`
+	Phone     string    `json:"phone"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// OutletProduct adalah stok dan harga produk di satu outlet.
+// PriceOverride kosong berarti outlet memakai harga katalog (BasePrice).
+// InTransit adalah jumlah yang sudah dikirim outlet lain ke outlet ini tetapi belum diterima.
+type OutletProduct struct {
+	ProductID     int     `json:"product_id"`
+	ProductName   string  `json:"product_name"`
+	BasePrice     int     `json:"base_price"`
+	PriceOverride *int    `json:"price_override"`
+	Price         int     `json:"price"`
+	Stock         float64 `json:"stock"`
+	InTransit     float64 `json:"in_transit"`
+}
+
+// OutletProductUpdate mengubah stok dan harga khusus produk di outlet. PriceOverride null menghapus harga khusus.
+type OutletProductUpdate struct {
+	Stock         *float64 `json:"stock"`
+	PriceOverride *int     `json:"price_override"`
+}
+
+// Peran pengguna
+const (
+	RoleOwner   = "owner"
+	RoleCashier = "cashier"
+)
+
+// User adalah pengguna API. APIKey hanya dikembalikan sekali saat pengguna dibuat
+// atau key-nya diganti; server hanya menyimpan hash-nya.
+type User struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	Role      string    `json:"role"`
+	OutletIDs []int     `json:"outlet_ids"`
+	APIKey    string    `json:"api_key,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (u *User) IsOwner() bool {
+	return u.Role == RoleOwner
+}
+
+// CanAccess melaporkan apakah pengguna boleh bekerja di outlet tersebut.
+func (u *User) CanAccess(outletID int) bool {
+	if u.IsOwner() {
+		return true
+	}
+	for _, id := range u.OutletIDs {
+		if id == outletID {
+			return true
+		}
+	}
+	return false
+}
+
+type OutletReport struct {
+	OutletID       int     `json:"outlet_id"`
+	OutletName     string  `json:"outlet_name"`
+	TotalRevenue   float64 `json:"total_revenue"`
+	TotalTransaksi int     `json:"total_transaksi"`
+}
+
+// ConsolidatedReport adalah laporan gabungan semua outlet untuk pemilik.
+type ConsolidatedReport struct {
+	StartDate      string         `json:"start_date"`
+	EndDate        string         `json:"end_date"`
+	TotalRevenue   float64        `json:"total_revenue"`
+	TotalTransaksi int            `json:"total_transaksi"`
+	ProdukTerlaris []ProductSales `json:"produk_terlaris"`
+	Outlets        []OutletReport `json:"outlets"`
+}
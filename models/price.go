@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// ProductPrice adalah satu baris riwayat harga katalog. AppliedAt kosong berarti harga
+// terjadwal yang belum berlaku.
+type ProductPrice struct {
+	ID            int        `json:"id"`
+	ProductID     int        `json:"product_id"`
+	Price         int        `json:"price"`
+	EffectiveFrom time.Time  `json:"effective_from"`
+	AppliedAt     *time.Time `json:"applied_at"`
+	Note          string     `json:"note"`
+	CreatedBy     string     `json:"created_by"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// SchedulePriceRequest menjadwalkan harga katalog baru. EffectiveFrom berformat
+// YYYY-MM-DD (awal hari di zona waktu toko) atau RFC3339.
+type SchedulePriceRequest struct {
+	Price         int    `json:"price"`
+	EffectiveFrom string `json:"effective_from" example:"2026-11-01"`
+	Note          string `json:"note"`
+}
@@ -1,10 +1,52 @@
 package models
 
+import "time"
+
+// Jenis produk. Paket (bundle) dan resep tidak punya stok sendiri: stoknya dihitung dari stok
+// komponen dan checkout mengurangi stok komponen.
+const (
+	ProductStandard = "standard"
+	ProductBundle   = "bundle"
+	ProductRecipe   = "recipe"
+)
+
 type Product struct {
-	ID           int     `json:"id"`
-	Name         string  `json:"name"`
-	Price        float64 `json:"price"`
-	Stock        int     `json:"stock"`
-	CategoryID   int     `json:"category_id,omitempty"`
-	CategoryName *string `json:"category_name,omitempty"`
+	ID             int                `json:"id"`
+	SKU            string             `json:"sku,omitempty"`
+	Name           string             `json:"name"`
+	Price          float64            `json:"price"`
+	Stock          float64            `json:"stock"`
+	Unit           string             `json:"unit"`
+	Units          []ProductUnit      `json:"units,omitempty"`
+	Type           string             `json:"type"`
+	Components     []ProductComponent `json:"components,omitempty"`
+	ModifierGroups []ModifierGroup    `json:"modifier_groups,omitempty"`
+	CategoryID     int                `json:"category_id,omitempty"`
+	CategoryName   *string            `json:"category_name,omitempty"`
+	Image          *ProductImage      `json:"image,omitempty"`
+	ImageKey       string             `json:"-"`
+	DeletedAt      *time.Time         `json:"deleted_at,omitempty"`
+	Version        int                `json:"version,omitempty"`
+	StockVersion   int64              `json:"-"`
+}
+
+// ProductImage berisi URL foto produk: file asli dan thumbnail per ukuran (sm, md, lg).
+type ProductImage struct {
+	URL        string            `json:"url"`
+	Thumbnails map[string]string `json:"thumbnails"`
+}
+
+// IsComposite melaporkan apakah produk adalah paket atau resep.
+func (p *Product) IsComposite() bool {
+	return p.Type == ProductBundle || p.Type == ProductRecipe
+}
+
+// ProductComponent adalah isi paket atau bahan resep. Quantity dalam satuan dasar komponen per satu
+// satuan dasar produk, contoh 0.018 kg kopi untuk satu latte. Pada detail transaksi Quantity adalah
+// total yang terpakai.
+type ProductComponent struct {
+	ProductID   int     `json:"product_id"`
+	ProductName string  `json:"product_name,omitempty"`
+	Unit        string  `json:"unit,omitempty"`
+	Quantity    float64 `json:"quantity"`
 }
@@ -0,0 +1,40 @@
+package models
+
+// Aksi per baris impor produk
+const (
+	ImportCreate = "create"
+	ImportUpdate = "update"
+	ImportFailed = "failed"
+)
+
+// ProductImportRow adalah satu baris file impor yang sudah lolos validasi. Stock nil berarti
+// kolom stok tidak diisi: produk baru mulai dari 0 dan stok produk lama tidak diubah.
+// Category kosong berarti kategori produk lama tidak diubah.
+type ProductImportRow struct {
+	Row      int
+	SKU      string
+	Name     string
+	Price    float64
+	Stock    *float64
+	Category string
+}
+
+// ProductImportResult adalah hasil satu baris impor.
+type ProductImportResult struct {
+	Row       int          `json:"row" example:"2"`
+	SKU       string       `json:"sku" example:"KOPI-001"`
+	Action    string       `json:"action" example:"create"`
+	ProductID int          `json:"product_id,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
+}
+
+// ProductImportReport adalah ringkasan impor. Pada dry run tidak ada yang disimpan dan
+// Created/Updated adalah jumlah baris yang akan dibuat atau diperbarui.
+type ProductImportReport struct {
+	DryRun  bool                  `json:"dry_run"`
+	Total   int                   `json:"total"`
+	Created int                   `json:"created"`
+	Updated int                   `json:"updated"`
+	Failed  int                   `json:"failed"`
+	Rows    []ProductImportResult `json:"rows"`
+}
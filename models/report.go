@@ -1,10 +1,133 @@
 package models
 
+import "time"
+
 type Report struct {
 	TotalRevenue   float64 `json:"total_revenue"`
 	TotalTransaksi int     `json:"total_transaksi"`
 	ProdukTerlaris struct {
-		Nama       string `json:"nama"`
-		QtyTerjual int    `json:"qty_terjual"`
+		Nama       string  `json:"nama"`
+		QtyTerjual float64 `json:"qty_terjual"`
 	} `json:"produk_terlaris"`
 }
+
+// Interval pengelompokan data time-series
+const (
+	IntervalHour  = "hour"
+	IntervalDay   = "day"
+	IntervalWeek  = "week"
+	IntervalMonth = "month"
+)
+
+func IsValidInterval(interval string) bool {
+	switch interval {
+	case IntervalHour, IntervalDay, IntervalWeek, IntervalMonth:
+		return true
+	}
+	return false
+}
+
+type TimeSeriesPoint struct {
+	Bucket         time.Time `json:"bucket"`
+	TotalRevenue   float64   `json:"total_revenue"`
+	TotalTransaksi int       `json:"total_transaksi"`
+	QtyTerjual     float64   `json:"qty_terjual"`
+}
+
+type TimeSeriesReport struct {
+	Interval  string            `json:"interval"`
+	Timezone  string            `json:"timezone"`
+	StartDate string            `json:"start_date"`
+	EndDate   string            `json:"end_date"`
+	Data      []TimeSeriesPoint `json:"data"`
+}
+
+// Pilihan periode pembanding
+const (
+	ComparePrevious  = "previous"
+	CompareLastWeek  = "last_week"
+	CompareLastMonth = "last_month"
+	CompareLastYear  = "last_year"
+)
+
+func IsValidCompare(compare string) bool {
+	switch compare {
+	case ComparePrevious, CompareLastWeek, CompareLastMonth, CompareLastYear:
+		return true
+	}
+	return false
+}
+
+type PeriodSummary struct {
+	StartDate      string  `json:"start_date"`
+	EndDate        string  `json:"end_date"`
+	TotalRevenue   float64 `json:"total_revenue"`
+	TotalTransaksi int     `json:"total_transaksi"`
+	AverageBasket  float64 `json:"average_basket"`
+}
+
+// Delta berisi selisih absolut dan persentase terhadap periode pembanding.
+// Percent bernilai null jika nilai periode pembanding nol.
+type Delta struct {
+	Absolute float64  `json:"absolute"`
+	Percent  *float64 `json:"percent"`
+}
+
+type ProductSales struct {
+	ProductID  int     `json:"product_id"`
+	Nama       string  `json:"nama"`
+	Satuan     string  `json:"satuan"`
+	QtyTerjual float64 `json:"qty_terjual"`
+}
+
+type ProductComparison struct {
+	ProductID   int     `json:"product_id"`
+	Nama        string  `json:"nama"`
+	QtyCurrent  float64 `json:"qty_current"`
+	QtyPrevious float64 `json:"qty_previous"`
+	Delta       Delta   `json:"delta"`
+}
+
+type ReportComparison struct {
+	CompareTo      string              `json:"compare_to"`
+	Timezone       string              `json:"timezone"`
+	Current        PeriodSummary       `json:"current"`
+	Previous       PeriodSummary       `json:"previous"`
+	TotalRevenue   Delta               `json:"total_revenue"`
+	TotalTransaksi Delta               `json:"total_transaksi"`
+	AverageBasket  Delta               `json:"average_basket"`
+	Produk         []ProductComparison `json:"produk"`
+}
+
+// ComponentUsage adalah jumlah komponen (isi paket atau bahan resep) yang terpakai, dalam
+// satuan dasar komponen.
+type ComponentUsage struct {
+	ProductID   int     `json:"product_id"`
+	Nama        string  `json:"nama"`
+	Satuan      string  `json:"satuan"`
+	QtyTerpakai float64 `json:"qty_terpakai"`
+}
+
+// ProductReport berisi produk yang terjual beserta komponen yang terpakai oleh paket dan resep.
+type ProductReport struct {
+	StartDate        string           `json:"start_date"`
+	EndDate          string           `json:"end_date"`
+	Terjual          []ProductSales   `json:"terjual"`
+	KomponenTerpakai []ComponentUsage `json:"komponen_terpakai"`
+}
+
+// ModifierSales adalah popularitas satu modifier: Dipilih adalah jumlah item transaksi yang memilihnya,
+// QtyTerjual jumlah kuantitas item tersebut dan TotalTambahan pendapatan dari harga modifier.
+type ModifierSales struct {
+	Grup          string  `json:"grup"`
+	Nama          string  `json:"nama"`
+	Dipilih       int     `json:"dipilih"`
+	QtyTerjual    float64 `json:"qty_terjual"`
+	TotalTambahan int     `json:"total_tambahan"`
+}
+
+type ModifierReport struct {
+	StartDate string          `json:"start_date"`
+	EndDate   string          `json:"end_date"`
+	Modifier  []ModifierSales `json:"modifier"`
+}
@@ -0,0 +1,63 @@
+package models
+
+import "time"
+
+// Jenis mutasi buku stok
+const (
+	StockSale        = "sale"
+	StockAdjustment  = "adjustment"
+	StockTransferOut = "transfer_out"
+	StockTransferIn  = "transfer_in"
+)
+
+// StockMovement adalah satu baris buku stok. Quantity positif berarti stok masuk, negatif keluar.
+type StockMovement struct {
+	ID            int       `json:"id"`
+	OutletID      int       `json:"outlet_id"`
+	ProductID     int       `json:"product_id"`
+	ProductName   string    `json:"product_name"`
+	Quantity      float64   `json:"quantity"`
+	Type          string    `json:"type"`
+	TransactionID *int      `json:"transaction_id,omitempty"`
+	TransferID    *int      `json:"transfer_id,omitempty"`
+	Note          string    `json:"note,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// Status dokumen transfer stok
+const (
+	TransferRequested   = "requested"
+	TransferShipped     = "shipped"
+	TransferReceived    = "received"
+	TransferDiscrepancy = "received_with_discrepancy"
+	TransferCancelled   = "cancelled"
+)
+
+type StockTransfer struct {
+	ID           int                 `json:"id"`
+	FromOutletID int                 `json:"from_outlet_id"`
+	ToOutletID   int                 `json:"to_outlet_id"`
+	Status       string              `json:"status"`
+	Note         string              `json:"note"`
+	Lines        []StockTransferLine `json:"lines"`
+	CreatedAt    time.Time           `json:"created_at"`
+	ShippedAt    *time.Time          `json:"shipped_at,omitempty"`
+	ReceivedAt   *time.Time          `json:"received_at,omitempty"`
+}
+
+// StockTransferLine berisi jumlah yang diminta, dikirim dan diterima.
+// Discrepancy adalah selisih kirim dan terima (positif berarti kurang diterima).
+type StockTransferLine struct {
+	ProductID        int      `json:"product_id"`
+	ProductName      string   `json:"product_name,omitempty"`
+	Quantity         float64  `json:"quantity"`
+	QuantityShipped  *float64 `json:"quantity_shipped,omitempty"`
+	QuantityReceived *float64 `json:"quantity_received,omitempty"`
+	Discrepancy      float64  `json:"discrepancy,omitempty"`
+}
+
+// TransferQuantities berisi jumlah aktual saat kirim atau terima.
+// Produk yang tidak disebut memakai jumlah langkah sebelumnya.
+type TransferQuantities struct {
+	Lines []CheckoutItem `json:"lines"`
+}
@@ -0,0 +1,52 @@
+package models
+
+import "time"
+
+// SyncTransaction adalah transaksi yang dibuat terminal saat offline.
+// ClientUUID dibuat oleh terminal, CreatedAt adalah waktu penjualan sebenarnya.
+type SyncTransaction struct {
+	ClientUUID string    `json:"client_uuid"`
+	CreatedAt  time.Time `json:"created_at"`
+	CheckoutRequest
+}
+
+type SyncBatchRequest struct {
+	Transactions []SyncTransaction `json:"transactions"`
+}
+
+// Hasil sinkronisasi per transaksi
+const (
+	SyncApplied   = "applied"
+	SyncDuplicate = "duplicate"
+	SyncRejected  = "rejected"
+)
+
+type SyncResult struct {
+	ClientUUID    string `json:"client_uuid"`
+	Status        string `json:"status"`
+	TransactionID int    `json:"transaction_id,omitempty"`
+	Reason        string `json:"reason,omitempty"`
+}
+
+type SyncBatchResponse struct {
+	Applied   int          `json:"applied"`
+	Duplicate int          `json:"duplicate"`
+	Rejected  int          `json:"rejected"`
+	Results   []SyncResult `json:"results"`
+}
+
+type SyncTombstone struct {
+	Entity    string    `json:"entity"`
+	ID        int       `json:"id"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// SyncChanges berisi perubahan katalog sejak cursor. Terminal menyimpan Cursor
+// dan mengirimkannya kembali pada permintaan berikutnya; ulangi selama HasMore bernilai true.
+type SyncChanges struct {
+	Cursor     string          `json:"cursor"`
+	HasMore    bool            `json:"has_more"`
+	Products   []Product       `json:"products"`
+	Categories []Categories    `json:"categories"`
+	Deleted    []SyncTombstone `json:"deleted"`
+}
@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// Tenant adalah satu toko/pelanggan SaaS. Datanya disimpan di schema Postgres tersendiri (Schema).
+type Tenant struct {
+	ID         int       `json:"id"`
+	Slug       string    `json:"slug"`
+	Name       string    `json:"name"`
+	Schema     string    `json:"schema"`
+	Active     bool      `json:"active"`
+	APIKey     string    `json:"api_key,omitempty"`
+	APIKeyHash string    `json:"-"`
+	CreatedAt  time.Time `json:"created_at"`
+
+	// Identitas toko di struk tenant; nama toko memakai Name dan ReceiptFooter kosong berarti
+	// footer bawaan
+	Address       string `json:"address"`
+	Phone         string `json:"phone"`
+	NPWP          string `json:"npwp"`
+	ReceiptFooter string `json:"receipt_footer"`
+}
+
+// TenantUpdate adalah body PUT tenant; field kosong (nil) tidak diubah.
+type TenantUpdate struct {
+	Name          string  `json:"name"`
+	Active        *bool   `json:"active"`
+	Address       *string `json:"address"`
+	Phone         *string `json:"phone"`
+	NPWP          *string `json:"npwp"`
+	ReceiptFooter *string `json:"receipt_footer"`
+}
@@ -2,27 +2,58 @@ package models
 
 import "time"
 
+// TotalAmount adalah total setelah diskon (poin dan voucher). Pembayaran dengan kartu hadiah
+// tidak mengurangi TotalAmount, sisa yang harus dibayar ada di AmountDue.
 type Transaction struct {
-	ID          int                 `json:"id"`
-	TotalAmount int                 `json:"total_amount"`
-	CreatedAt   time.Time           `json:"created_at"`
-	Details     []TransactionDetail `json:"details"`
+	ID              int                 `json:"id"`
+	OutletID        int                 `json:"outlet_id"`
+	TotalAmount     int                 `json:"total_amount"`
+	DiscountAmount  int                 `json:"discount_amount"`
+	VoucherCode     string              `json:"voucher_code,omitempty"`
+	VoucherDiscount int                 `json:"voucher_discount,omitempty"`
+	GiftCardCode    string              `json:"gift_card_code,omitempty"`
+	GiftCardAmount  int                 `json:"gift_card_amount"`
+	AmountDue       int                 `json:"amount_due"`
+	CustomerID      *int                `json:"customer_id,omitempty"`
+	PointsEarned    int                 `json:"points_earned,omitempty"`
+	PointsRedeemed  int                 `json:"points_redeemed,omitempty"`
+	CreatedAt       time.Time           `json:"created_at"`
+	Details         []TransactionDetail `json:"details"`
 }
 
+// TransactionDetail mencatat kuantitas dan harga dalam satuan jual (Unit). BaseQuantity adalah
+// kuantitas dalam satuan dasar produk yang mengurangi stok. Subtotal adalah UnitPrice ditambah harga
+// semua Modifiers, dikali Quantity.
 type TransactionDetail struct {
-	ID            int    `json:"id"`
-	TransactionID int    `json:"transaction_id"`
-	ProductID     int    `json:"product_id"`
-	ProductName   string `json:"product_name"`
-	Quantity      int    `json:"quantity"`
-	Subtotal      int    `json:"subtotal"`
+	ID            int     `json:"id"`
+	TransactionID int     `json:"transaction_id"`
+	ProductID     int     `json:"product_id"`
+	ProductName   string  `json:"product_name"`
+	Quantity      float64 `json:"quantity"`
+	Unit          string  `json:"unit"`
+	BaseQuantity  float64 `json:"base_quantity"`
+	UnitPrice     int     `json:"unit_price"`
+	Subtotal      int     `json:"subtotal"`
+	// Components berisi stok komponen yang terpakai jika produk adalah paket atau resep
+	Components []ProductComponent `json:"components,omitempty"`
+	Modifiers  []SelectedModifier `json:"modifiers,omitempty"`
 }
 
 type CheckoutRequest struct {
-	Items []CheckoutItem `json:"items"`
+	Items         []CheckoutItem `json:"items"`
+	CustomerID    *int           `json:"customer_id,omitempty"`
+	CustomerPhone string         `json:"customer_phone,omitempty"`
+	RedeemPoints  int            `json:"redeem_points,omitempty"`
+	VoucherCode   string         `json:"voucher_code,omitempty"`
+	GiftCardCode  string         `json:"gift_card_code,omitempty"`
 }
 
+// CheckoutItem memilih produk, kuantitas dan satuan jual. Unit kosong berarti satuan dasar produk;
+// kuantitas pecahan hanya boleh untuk satuan yang mengizinkannya (kg, liter). Modifiers berisi id
+// modifier yang dipilih dari kelompok modifier produk.
 type CheckoutItem struct {
-	ProductID int `json:"product_id"`
-	Quantity  int `json:"quantity"`
+	ProductID int     `json:"product_id"`
+	Quantity  float64 `json:"quantity"`
+	Unit      string  `json:"unit,omitempty"`
+	Modifiers []int   `json:"modifiers,omitempty"`
 }
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"math"
+	"strconv"
+)
+
+// Satuan yang dikenal. Stok produk selalu dicatat dalam satuan dasar produk (Product.Unit).
+const (
+	UnitPcs   = "pcs"
+	UnitKg    = "kg"
+	UnitLiter = "liter"
+	UnitBox   = "box"
+)
+
+// QuantityDecimals adalah jumlah angka desimal kuantitas dan stok (gram/mililiter untuk kg/liter).
+const QuantityDecimals = 3
+
+// Unit adalah satuan beserta aturan boleh tidaknya dijual pecahan.
+type Unit struct {
+	Code         string `json:"code"`
+	Name         string `json:"name"`
+	AllowDecimal bool   `json:"allow_decimal"`
+}
+
+// Units adalah daftar satuan yang bisa dipakai produk.
+var Units = map[string]Unit{
+	UnitPcs:   {Code: UnitPcs, Name: "Pcs", AllowDecimal: false},
+	UnitKg:    {Code: UnitKg, Name: "Kilogram", AllowDecimal: true},
+	UnitLiter: {Code: UnitLiter, Name: "Liter", AllowDecimal: true},
+	UnitBox:   {Code: UnitBox, Name: "Box", AllowDecimal: false},
+}
+
+// ProductUnit adalah satuan jual tambahan produk. Factor adalah jumlah satuan dasar dalam satu
+// satuan jual, contoh box = 12 pcs. Price kosong berarti harga satuan dasar dikali Factor.
+type ProductUnit struct {
+	Unit   string  `json:"unit"`
+	Factor float64 `json:"factor"`
+	Price  *int    `json:"price,omitempty"`
+}
+
+// RoundQuantity membulatkan kuantitas ke QuantityDecimals angka desimal, supaya sisa pembulatan
+// float64 (misalnya 0.1 + 0.2) tidak ikut tersimpan.
+func RoundQuantity(q float64) float64 {
+	scale := math.Pow10(QuantityDecimals)
+	return math.Round(q*scale) / scale
+}
+
+// ValidQuantity melaporkan apakah q boleh dipakai untuk satuan unit: satuan utuh (pcs, box) hanya
+// menerima bilangan bulat, dan tidak ada satuan yang menerima lebih dari QuantityDecimals desimal.
+func ValidQuantity(unit string, q float64) bool {
+	rounded := RoundQuantity(q)
+	if math.Abs(rounded-q) > 1e-9 {
+		return false
+	}
+	if u, ok := Units[unit]; ok && !u.AllowDecimal {
+		return rounded == math.Trunc(rounded)
+	}
+	return true
+}
+
+// FormatQuantity menulis kuantitas tanpa nol di belakang koma, contoh 2 atau 0.75.
+func FormatQuantity(q float64) string {
+	return strconv.FormatFloat(RoundQuantity(q), 'f', -1, 64)
+}
@@ -0,0 +1,66 @@
+package models
+
+import "testing"
+
+func TestRoundQuantity(t *testing.T) {
+	cases := []struct {
+		in   float64
+		want float64
+	}{
+		{0.1 + 0.2, 0.3},
+		{1.0005, 1.001},
+		{1.0004, 1},
+		{2.25, 2.25},
+		{-0.0004, 0},
+		{12, 12},
+	}
+
+	for _, tc := range cases {
+		if got := RoundQuantity(tc.in); got != tc.want {
+			t.Errorf("RoundQuantity(%v) = %v, want %v", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestValidQuantity(t *testing.T) {
+	cases := []struct {
+		unit string
+		q    float64
+		want bool
+	}{
+		{UnitPcs, 3, true},
+		{UnitPcs, 1.5, false},
+		{UnitBox, 2, true},
+		{UnitBox, 0.5, false},
+		{UnitKg, 0.25, true},
+		{UnitKg, 0.018, true},
+		{UnitKg, 0.0185, false},
+		{UnitLiter, 1.5, true},
+		{UnitKg, 0.1 + 0.2, true},
+		{"gelas", 1.5, true},
+	}
+
+	for _, tc := range cases {
+		if got := ValidQuantity(tc.unit, tc.q); got != tc.want {
+			t.Errorf("ValidQuantity(%q, %v) = %v, want %v", tc.unit, tc.q, got, tc.want)
+		}
+	}
+}
+
+func TestFormatQuantity(t *testing.T) {
+	cases := []struct {
+		in   float64
+		want string
+	}{
+		{2, "2"},
+		{0.75, "0.75"},
+		{0.1 + 0.2, "0.3"},
+		{1.2345, "1.235"},
+	}
+
+	for _, tc := range cases {
+		if got := FormatQuantity(tc.in); got != tc.want {
+			t.Errorf("FormatQuantity(%v) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
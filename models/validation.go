@@ -0,0 +1,9 @@
+package models
+
+// FieldError adalah satu kesalahan pada field request. Field memakai nama JSON,
+// contoh "items[1].quantity".
+type FieldError struct {
+	Field   string `json:"field" example:"price"`
+	Code    string `json:"code" example:"min"`
+	Message string `json:"message" example:"price must not be negative"`
+}
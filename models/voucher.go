@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// Jenis voucher
+const (
+	VoucherFixed   = "fixed"
+	VoucherPercent = "percent"
+)
+
+type Voucher struct {
+	ID          int        `json:"id"`
+	Code        string     `json:"code"`
+	Type        string     `json:"type"`
+	Value       int        `json:"value"`
+	MaxDiscount *int       `json:"max_discount,omitempty"`
+	MinSpend    int        `json:"min_spend"`
+	UsageLimit  *int       `json:"usage_limit,omitempty"`
+	UsedCount   int        `json:"used_count"`
+	ProductIDs  []int      `json:"product_ids"`
+	CategoryIDs []int      `json:"category_ids"`
+	StartsAt    *time.Time `json:"starts_at,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	Active      bool       `json:"active"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+type GiftCard struct {
+	ID             int        `json:"id"`
+	Code           string     `json:"code"`
+	InitialBalance int        `json:"initial_balance"`
+	Balance        int        `json:"balance"`
+	ExpiresAt      *time.Time `json:"expires_at,omitempty"`
+	Active         bool       `json:"active"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// Redemption adalah satu pemakaian voucher atau kartu hadiah pada transaksi.
+type Redemption struct {
+	ID            int       `json:"id"`
+	TransactionID int       `json:"transaction_id"`
+	Code          string    `json:"code"`
+	Amount        int       `json:"amount"`
+	CreatedAt     time.Time `json:"created_at"`
+}
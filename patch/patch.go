@@ -0,0 +1,337 @@
+// Package patch menerapkan JSON Merge Patch (RFC 7396) dan JSON Patch (RFC 6902)
+// pada dokumen JSON untuk endpoint PATCH.
+package patch
+
+import (
+	"encoding/json"
+	"fmt"
+	"kasir-api/apperror"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Content-Type yang didukung
+const (
+	ContentTypeMergePatch = "application/merge-patch+json"
+	ContentTypeJSONPatch  = "application/json-patch+json"
+)
+
+var (
+	// ErrInvalidPatch menandai dokumen patch yang tidak bisa dibaca atau operasi yang tidak valid
+	ErrInvalidPatch = apperror.BadRequest("invalid patch")
+
+	// ErrTestFailed menandai operasi "test" JSON Patch yang nilainya tidak cocok
+	ErrTestFailed = apperror.Conflict("patch test failed")
+)
+
+// MergePatch menerapkan JSON Merge Patch: field berisi null dihapus, object digabung secara
+// rekursif, nilai lain menggantikan nilai lama.
+func MergePatch(doc []byte, patch []byte) ([]byte, error) {
+	var target, p interface{}
+	if err := json.Unmarshal(doc, &target); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(patch, &p); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidPatch, err.Error())
+	}
+	return json.Marshal(mergeValue(target, p))
+}
+
+func mergeValue(target interface{}, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = make(map[string]interface{})
+	}
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+			continue
+		}
+		targetObject[key] = mergeValue(targetObject[key], value)
+	}
+	return targetObject
+}
+
+type operation struct {
+	Op    string          `json:"op"`
+	Path  *string         `json:"path"`
+	From  *string         `json:"from"`
+	Value json.RawMessage `json:"value"`
+}
+
+// Apply menerapkan daftar operasi JSON Patch secara berurutan. Jika satu operasi gagal,
+// tidak ada perubahan yang dikembalikan.
+func Apply(doc []byte, patch []byte) ([]byte, error) {
+	var target interface{}
+	if err := json.Unmarshal(doc, &target); err != nil {
+		return nil, err
+	}
+
+	var ops []operation
+	if err := json.Unmarshal(patch, &ops); err != nil {
+		return nil, fmt.Errorf("%w: JSON Patch must be an array of operations", ErrInvalidPatch)
+	}
+
+	for i, op := range ops {
+		var err error
+		target, err = applyOperation(target, op)
+		if err != nil {
+			return nil, fmt.Errorf("operation %d (%s): %w", i, op.Op, err)
+		}
+	}
+	return json.Marshal(target)
+}
+
+func applyOperation(doc interface{}, op operation) (interface{}, error) {
+	if op.Path == nil {
+		return nil, fmt.Errorf("%w: path is required", ErrInvalidPatch)
+	}
+	path, err := parsePointer(*op.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	value := func() (interface{}, error) {
+		// null tetap terbaca sebagai "null", hanya field yang tidak dikirim yang kosong
+		if len(op.Value) == 0 {
+			return nil, fmt.Errorf("%w: value is required", ErrInvalidPatch)
+		}
+		var v interface{}
+		if err := json.Unmarshal(op.Value, &v); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidPatch, err.Error())
+		}
+		return v, nil
+	}
+
+	switch op.Op {
+	case "add":
+		v, err := value()
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, path, v)
+	case "remove":
+		doc, _, err := remove(doc, path)
+		return doc, err
+	case "replace":
+		v, err := value()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := get(doc, path); err != nil {
+			return nil, err
+		}
+		doc, _, err = remove(doc, path)
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, path, v)
+	case "move", "copy":
+		if op.From == nil {
+			return nil, fmt.Errorf("%w: from is required", ErrInvalidPatch)
+		}
+		from, err := parsePointer(*op.From)
+		if err != nil {
+			return nil, err
+		}
+		v, err := get(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		if op.Op == "move" {
+			if len(path) > len(from) && reflect.DeepEqual(path[:len(from)], from) {
+				return nil, fmt.Errorf("%w: cannot move a value into itself", ErrInvalidPatch)
+			}
+			doc, _, err = remove(doc, from)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			v = deepCopy(v)
+		}
+		return add(doc, path, v)
+	case "test":
+		v, err := value()
+		if err != nil {
+			return nil, err
+		}
+		current, err := get(doc, path)
+		if err != nil {
+			return nil, err
+		}
+		if !reflect.DeepEqual(current, v) {
+			return nil, fmt.Errorf("%w: value at %s does not match", ErrTestFailed, *op.Path)
+		}
+		return doc, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown op %q", ErrInvalidPatch, op.Op)
+	}
+}
+
+// parsePointer memecah JSON Pointer (RFC 6901) menjadi token, dengan ~1 menjadi / dan ~0 menjadi ~.
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return []string{}, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("%w: path %q must start with /", ErrInvalidPatch, pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+func arrayIndex(token string, length int, allowEnd bool) (int, error) {
+	if allowEnd && token == "-" {
+		return length, nil
+	}
+	index, err := strconv.Atoi(token)
+	if err != nil || index < 0 || (token != "0" && strings.HasPrefix(token, "0")) {
+		return 0, fmt.Errorf("%w: invalid array index %q", ErrInvalidPatch, token)
+	}
+	max := length - 1
+	if allowEnd {
+		max = length
+	}
+	if index > max {
+		return 0, fmt.Errorf("%w: array index %d out of range", ErrInvalidPatch, index)
+	}
+	return index, nil
+}
+
+func get(doc interface{}, path []string) (interface{}, error) {
+	current := doc
+	for _, token := range path {
+		switch node := current.(type) {
+		case map[string]interface{}:
+			v, ok := node[token]
+			if !ok {
+				return nil, fmt.Errorf("%w: path /%s not found", ErrInvalidPatch, strings.Join(path, "/"))
+			}
+			current = v
+		case []interface{}:
+			index, err := arrayIndex(token, len(node), false)
+			if err != nil {
+				return nil, err
+			}
+			current = node[index]
+		default:
+			return nil, fmt.Errorf("%w: path /%s not found", ErrInvalidPatch, strings.Join(path, "/"))
+		}
+	}
+	return current, nil
+}
+
+// add mengembalikan dokumen baru karena menambah elemen array bisa mengganti slice induknya.
+func add(doc interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	parent, err := get(doc, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+	last := path[len(path)-1]
+
+	switch node := parent.(type) {
+	case map[string]interface{}:
+		node[last] = value
+		return doc, nil
+	case []interface{}:
+		index, err := arrayIndex(last, len(node), true)
+		if err != nil {
+			return nil, err
+		}
+		updated := make([]interface{}, 0, len(node)+1)
+		updated = append(updated, node[:index]...)
+		updated = append(updated, value)
+		updated = append(updated, node[index:]...)
+		return set(doc, path[:len(path)-1], updated)
+	default:
+		return nil, fmt.Errorf("%w: cannot add to /%s", ErrInvalidPatch, strings.Join(path[:len(path)-1], "/"))
+	}
+}
+
+// set mengganti nilai yang sudah ada di path tanpa menyisipkan, dipakai untuk memasang
+// slice baru ke induknya setelah elemen array ditambah atau dihapus.
+func set(doc interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	parent, err := get(doc, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+	last := path[len(path)-1]
+
+	switch node := parent.(type) {
+	case map[string]interface{}:
+		node[last] = value
+		return doc, nil
+	case []interface{}:
+		index, err := arrayIndex(last, len(node), false)
+		if err != nil {
+			return nil, err
+		}
+		node[index] = value
+		return doc, nil
+	default:
+		return nil, fmt.Errorf("%w: path /%s not found", ErrInvalidPatch, strings.Join(path, "/"))
+	}
+}
+
+func remove(doc interface{}, path []string) (interface{}, interface{}, error) {
+	if len(path) == 0 {
+		return nil, doc, nil
+	}
+
+	parent, err := get(doc, path[:len(path)-1])
+	if err != nil {
+		return nil, nil, err
+	}
+	last := path[len(path)-1]
+
+	switch node := parent.(type) {
+	case map[string]interface{}:
+		v, ok := node[last]
+		if !ok {
+			return nil, nil, fmt.Errorf("%w: path /%s not found", ErrInvalidPatch, strings.Join(path, "/"))
+		}
+		delete(node, last)
+		return doc, v, nil
+	case []interface{}:
+		index, err := arrayIndex(last, len(node), false)
+		if err != nil {
+			return nil, nil, err
+		}
+		v := node[index]
+		updated := make([]interface{}, 0, len(node)-1)
+		updated = append(updated, node[:index]...)
+		updated = append(updated, node[index+1:]...)
+		doc, err := set(doc, path[:len(path)-1], updated)
+		if err != nil {
+			return nil, nil, err
+		}
+		return doc, v, nil
+	default:
+		return nil, nil, fmt.Errorf("%w: path /%s not found", ErrInvalidPatch, strings.Join(path, "/"))
+	}
+}
+
+func deepCopy(v interface{}) interface{} {
+	b, _ := json.Marshal(v)
+	var out interface{}
+	json.Unmarshal(b, &out)
+	return out
+}
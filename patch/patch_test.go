@@ -0,0 +1,150 @@
+package patch
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// sameJSON membandingkan dua dokumen JSON tanpa melihat urutan field
+func sameJSON(t *testing.T, got []byte, want string) {
+	t.Helper()
+	var g, w interface{}
+	if err := json.Unmarshal(got, &g); err != nil {
+		t.Fatalf("invalid result %s: %v", got, err)
+	}
+	if err := json.Unmarshal([]byte(want), &w); err != nil {
+		t.Fatalf("invalid expectation %s: %v", want, err)
+	}
+	if !reflect.DeepEqual(g, w) {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
+
+// Contoh dari RFC 7396 Appendix A ditambah contoh dokumen produk
+func TestMergePatch(t *testing.T) {
+	cases := []struct {
+		doc   string
+		patch string
+		want  string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"e":null,"a":1}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+		{`{"name":"Teh","price":5000,"sku":"TEH-1"}`, `{"price":6000,"sku":null}`, `{"name":"Teh","price":6000}`},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.doc+" "+tc.patch, func(t *testing.T) {
+			got, err := MergePatch([]byte(tc.doc), []byte(tc.patch))
+			if err != nil {
+				t.Fatal(err)
+			}
+			sameJSON(t, got, tc.want)
+		})
+	}
+}
+
+func TestMergePatchInvalid(t *testing.T) {
+	_, err := MergePatch([]byte(`{"a":1}`), []byte(`{"a":`))
+	if !errors.Is(err, ErrInvalidPatch) {
+		t.Fatalf("got %v, want ErrInvalidPatch", err)
+	}
+}
+
+// Sebagian besar contoh dari RFC 6902 Appendix A
+func TestApply(t *testing.T) {
+	cases := []struct {
+		name  string
+		doc   string
+		patch string
+		want  string
+	}{
+		{"add object member", `{"foo":"bar"}`, `[{"op":"add","path":"/baz","value":"qux"}]`, `{"baz":"qux","foo":"bar"}`},
+		{"add array element", `{"foo":["bar","baz"]}`, `[{"op":"add","path":"/foo/1","value":"qux"}]`, `{"foo":["bar","qux","baz"]}`},
+		{"add to array end", `{"foo":["bar"]}`, `[{"op":"add","path":"/foo/-","value":"baz"}]`, `{"foo":["bar","baz"]}`},
+		{"add nested array element", `{"a":[[1,2]]}`, `[{"op":"add","path":"/a/0/1","value":9}]`, `{"a":[[1,9,2]]}`},
+		{"remove object member", `{"baz":"qux","foo":"bar"}`, `[{"op":"remove","path":"/baz"}]`, `{"foo":"bar"}`},
+		{"remove array element", `{"foo":["bar","qux","baz"]}`, `[{"op":"remove","path":"/foo/1"}]`, `{"foo":["bar","baz"]}`},
+		{"remove nested array element", `{"a":[[1,2,3]]}`, `[{"op":"remove","path":"/a/0/1"}]`, `{"a":[[1,3]]}`},
+		{"remove from root array", `[1,2,3]`, `[{"op":"remove","path":"/0"}]`, `[2,3]`},
+		{"replace", `{"baz":"qux","foo":"bar"}`, `[{"op":"replace","path":"/baz","value":"boo"}]`, `{"baz":"boo","foo":"bar"}`},
+		{"replace array element", `{"units":[{"unit":"box","factor":12}]}`, `[{"op":"replace","path":"/units/0/factor","value":10}]`, `{"units":[{"unit":"box","factor":10}]}`},
+		{"replace root", `{"a":1}`, `[{"op":"replace","path":"","value":[1]}]`, `[1]`},
+		{"move", `{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`, `[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`, `{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`},
+		{"move array element", `{"foo":["all","grass","cows","eat"]}`, `[{"op":"move","from":"/foo/1","path":"/foo/3"}]`, `{"foo":["all","cows","eat","grass"]}`},
+		{"copy", `{"a":{"b":1}}`, `[{"op":"copy","from":"/a","path":"/c"}]`, `{"a":{"b":1},"c":{"b":1}}`},
+		{"test then replace", `{"price":5000}`, `[{"op":"test","path":"/price","value":5000},{"op":"replace","path":"/price","value":6000}]`, `{"price":6000}`},
+		{"escaped pointer", `{"a/b":1,"m~n":2}`, `[{"op":"replace","path":"/a~1b","value":3},{"op":"remove","path":"/m~0n"}]`, `{"a/b":3}`},
+		{"add null value", `{"foo":"bar"}`, `[{"op":"add","path":"/baz","value":null}]`, `{"foo":"bar","baz":null}`},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Apply([]byte(tc.doc), []byte(tc.patch))
+			if err != nil {
+				t.Fatal(err)
+			}
+			sameJSON(t, got, tc.want)
+		})
+	}
+}
+
+func TestApplyErrors(t *testing.T) {
+	cases := []struct {
+		name  string
+		doc   string
+		patch string
+		want  error
+	}{
+		{"not an array", `{}`, `{"op":"add"}`, ErrInvalidPatch},
+		{"unknown op", `{}`, `[{"op":"merge","path":"/a","value":1}]`, ErrInvalidPatch},
+		{"missing path", `{}`, `[{"op":"add","value":1}]`, ErrInvalidPatch},
+		{"missing value", `{}`, `[{"op":"add","path":"/a"}]`, ErrInvalidPatch},
+		{"missing from", `{"a":1}`, `[{"op":"move","path":"/b"}]`, ErrInvalidPatch},
+		{"path without slash", `{"a":1}`, `[{"op":"remove","path":"a"}]`, ErrInvalidPatch},
+		{"remove missing member", `{"a":1}`, `[{"op":"remove","path":"/b"}]`, ErrInvalidPatch},
+		{"replace missing member", `{"a":1}`, `[{"op":"replace","path":"/b","value":2}]`, ErrInvalidPatch},
+		{"add to missing parent", `{"a":1}`, `[{"op":"add","path":"/b/c","value":2}]`, ErrInvalidPatch},
+		{"index out of range", `{"a":[1]}`, `[{"op":"add","path":"/a/2","value":2}]`, ErrInvalidPatch},
+		{"leading zero index", `{"a":[1,2]}`, `[{"op":"remove","path":"/a/01"}]`, ErrInvalidPatch},
+		{"end marker on remove", `{"a":[1]}`, `[{"op":"remove","path":"/a/-"}]`, ErrInvalidPatch},
+		{"move into itself", `{"a":{"b":1}}`, `[{"op":"move","from":"/a","path":"/a/c"}]`, ErrInvalidPatch},
+		{"test mismatch", `{"price":5000}`, `[{"op":"test","path":"/price","value":6000}]`, ErrTestFailed},
+		{"test number against string", `{"price":5000}`, `[{"op":"test","path":"/price","value":"5000"}]`, ErrTestFailed},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Apply([]byte(tc.doc), []byte(tc.patch))
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("got %v, want %v", err, tc.want)
+			}
+		})
+	}
+}
+
+// Operasi yang gagal di tengah tidak boleh mengubah dokumen asli
+func TestApplyIsAtomic(t *testing.T) {
+	doc := []byte(`{"price":5000}`)
+	_, err := Apply(doc, []byte(`[{"op":"replace","path":"/price","value":1},{"op":"remove","path":"/missing"}]`))
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if string(doc) != `{"price":5000}` {
+		t.Fatalf("document changed to %s", doc)
+	}
+}
@@ -0,0 +1,62 @@
+package receipts
+
+import (
+	"bytes"
+	"kasir-api/models"
+)
+
+// Perintah ESC/POS yang dipakai
+var (
+	escInit        = []byte{0x1B, 0x40}                   // ESC @
+	escAlignLeft   = []byte{0x1B, 0x61, 0x00}             // ESC a 0
+	escAlignCenter = []byte{0x1B, 0x61, 0x01}             // ESC a 1
+	escBoldOn      = []byte{0x1B, 0x45, 0x01}             // ESC E 1
+	escBoldOff     = []byte{0x1B, 0x45, 0x00}             // ESC E 0
+	escCut         = []byte{0x1D, 0x56, 0x42, 0x00}       // GS V 66 0: feed lalu potong sebagian
+	escDrawerKick  = []byte{0x1B, 0x70, 0x00, 0x19, 0xFA} // ESC p 0 25 250: pulsa ke pin 2 laci kas
+)
+
+// ESCPOS menghasilkan byte stream mentah untuk printer thermal. Laci kas hanya
+// dibuka jika openDrawer bernilai true, biasanya hanya pada cetakan pertama.
+func (p *Printer) ESCPOS(t *models.Transaction, paper int, isCopy bool, openDrawer bool) ([]byte, error) {
+	lines, err := p.lines(t, paper, isCopy)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.Write(escInit)
+	for _, l := range lines {
+		if l.center {
+			buf.Write(escAlignCenter)
+		} else {
+			buf.Write(escAlignLeft)
+		}
+		if l.bold {
+			buf.Write(escBoldOn)
+		}
+		buf.WriteString(ascii(l.text))
+		buf.WriteByte('\n')
+		if l.bold {
+			buf.Write(escBoldOff)
+		}
+	}
+	buf.Write(escAlignLeft)
+	buf.Write(escCut)
+	if openDrawer {
+		buf.Write(escDrawerKick)
+	}
+	return buf.Bytes(), nil
+}
+
+// ascii mengganti karakter di luar ASCII karena code page default printer tidak mengenal UTF-8.
+func ascii(text string) string {
+	out := make([]byte, 0, len(text))
+	for _, r := range text {
+		if r < 0x20 || r > 0x7E {
+			r = '?'
+		}
+		out = append(out, byte(r))
+	}
+	return string(out)
+}
@@ -0,0 +1,78 @@
+package receipts
+
+import (
+	"bytes"
+	"fmt"
+	"kasir-api/models"
+	"strings"
+)
+
+const (
+	pdfFontSize   = 7.0
+	pdfLineHeight = 9.0
+	pdfMargin     = 8.0
+	mmToPoint     = 72 / 25.4
+)
+
+// PDF menghasilkan struk PDF satu halaman selebar kertas thermal dengan font Courier,
+// sehingga tata letaknya sama dengan struk teks.
+func (p *Printer) PDF(t *models.Transaction, paper int, isCopy bool) ([]byte, error) {
+	lines, err := p.lines(t, paper, isCopy)
+	if err != nil {
+		return nil, err
+	}
+	width := Columns(paper)
+
+	pageWidth := float64(paper) * mmToPoint
+	pageHeight := pdfMargin*2 + float64(len(lines))*pdfLineHeight
+	// Lebar karakter Courier adalah 0.6 em, sesuaikan ukuran font agar satu baris muat
+	fontSize := (pageWidth - pdfMargin*2) / (float64(width) * 0.6)
+	if fontSize > pdfFontSize {
+		fontSize = pdfFontSize
+	}
+
+	var content bytes.Buffer
+	y := pageHeight - pdfMargin - fontSize
+	for _, l := range lines {
+		text := l.text
+		if l.center {
+			text = center(text, width)
+		}
+		font := "F1"
+		if l.bold {
+			font = "F2"
+		}
+		fmt.Fprintf(&content, "BT /%s %.2f Tf %.2f %.2f Td (%s) Tj ET\n", font, fontSize, pdfMargin, y, pdfEscape(ascii(text)))
+		y -= pdfLineHeight
+	}
+
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /Font << /F1 4 0 R /F2 5 0 R >> >> /Contents 6 0 R >>", pageWidth, pageHeight),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier-Bold >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()),
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	return buf.Bytes(), nil
+}
+
+func pdfEscape(text string) string {
+	return strings.NewReplacer(`\`, `\\`, "(", `\(`, ")", `\)`).Replace(text)
+}
@@ -0,0 +1,250 @@
+package receipts
+
+import (
+	"bytes"
+	"fmt"
+	"kasir-api/models"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// Lebar kertas yang didukung beserta jumlah karakter per baris (font A)
+const (
+	Paper58 = 58
+	Paper80 = 80
+)
+
+// Format keluaran struk
+const (
+	FormatText   = "text"
+	FormatESCPOS = "escpos"
+	FormatPDF    = "pdf"
+)
+
+const DefaultHeaderTemplate = `{{.Name}}
+{{if .Address}}{{.Address}}
+{{end}}{{if .Phone}}Telp. {{.Phone}}
+{{end}}{{if .NPWP}}NPWP {{.NPWP}}
+{{end}}`
+
+// Store berisi identitas toko yang dicetak di kepala dan kaki struk.
+type Store struct {
+	Name    string
+	Address string
+	Phone   string
+	NPWP    string
+	Footer  string
+}
+
+type Printer struct {
+	store  Store
+	header *template.Template
+}
+
+// NewPrinter membuat printer struk. headerTemplate memakai sintaks text/template dengan
+// field Store ({{.Name}}, {{.Address}}, {{.Phone}}, {{.NPWP}}); kosong berarti DefaultHeaderTemplate.
+func NewPrinter(store Store, headerTemplate string) (*Printer, error) {
+	if headerTemplate == "" {
+		headerTemplate = DefaultHeaderTemplate
+	}
+	tmpl, err := template.New("header").Parse(headerTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid receipt header template: %w", err)
+	}
+	return &Printer{store: store, header: tmpl}, nil
+}
+
+// WithStore mengembalikan printer dengan template kepala struk yang sama untuk toko lain, dipakai
+// pada mode multi-tenant. Footer kosong memakai footer printer asal.
+func (p *Printer) WithStore(store Store) *Printer {
+	if store.Footer == "" {
+		store.Footer = p.store.Footer
+	}
+	return &Printer{store: store, header: p.header}
+}
+
+func Columns(paper int) int {
+	if paper == Paper58 {
+		return 32
+	}
+	return 48
+}
+
+// line menyimpan satu baris struk beserta perataannya, dipakai bersama oleh semua format.
+type line struct {
+	text   string
+	center bool
+	bold   bool
+}
+
+// lines menyusun isi struk dalam baris dengan lebar tetap sesuai kertas.
+func (p *Printer) lines(t *models.Transaction, paper int, isCopy bool) ([]line, error) {
+	width := Columns(paper)
+	out := make([]line, 0)
+	separator := line{text: strings.Repeat("-", width)}
+
+	var header bytes.Buffer
+	if err := p.header.Execute(&header, p.store); err != nil {
+		return nil, err
+	}
+	for i, text := range strings.Split(strings.TrimRight(header.String(), "\n"), "\n") {
+		for _, wrapped := range wrap(text, width) {
+			out = append(out, line{text: wrapped, center: true, bold: i == 0})
+		}
+	}
+
+	out = append(out, separator)
+	if isCopy {
+		out = append(out, line{text: "*** SALINAN / COPY ***", center: true, bold: true}, separator)
+	}
+	out = append(out,
+		line{text: columns("No. Transaksi", "#"+strconv.Itoa(t.ID), width)},
+		line{text: columns("Tanggal", t.CreatedAt.Format("02/01/2006 15:04"), width)},
+		separator,
+	)
+
+	for _, d := range t.Details {
+		for _, wrapped := range wrap(d.ProductName, width) {
+			out = append(out, line{text: wrapped})
+		}
+		for _, m := range d.Modifiers {
+			for _, wrapped := range wrap("  + "+Modifier(m), width) {
+				out = append(out, line{text: wrapped})
+			}
+		}
+		// Harga satuan di struk sudah termasuk modifier sehingga kuantitas x harga = subtotal
+		qty := fmt.Sprintf("  %s %s x %s", Quantity(d.Quantity), d.Unit, Rupiah(d.UnitPrice+models.ModifierPrice(d.Modifiers)))
+		out = append(out, line{text: columns(qty, Rupiah(d.Subtotal), width)})
+	}
+
+	out = append(out, separator)
+	if t.DiscountAmount > 0 {
+		out = append(out,
+			line{text: columns("Subtotal", Rupiah(t.TotalAmount+t.DiscountAmount), width)},
+			line{text: columns("Diskon", "-"+Rupiah(t.DiscountAmount), width)},
+		)
+		if t.VoucherCode != "" {
+			out = append(out, line{text: "  Voucher " + t.VoucherCode})
+		}
+	}
+	out = append(out, line{text: columns("TOTAL", "Rp "+Rupiah(t.TotalAmount), width), bold: true})
+	if t.GiftCardAmount > 0 {
+		out = append(out,
+			line{text: columns("Kartu Hadiah", "-"+Rupiah(t.GiftCardAmount), width)},
+			line{text: columns("Sisa Bayar", "Rp "+Rupiah(t.AmountDue), width), bold: true},
+		)
+	}
+	out = append(out, separator)
+
+	if p.store.Footer != "" {
+		for _, text := range strings.Split(p.store.Footer, "\n") {
+			for _, wrapped := range wrap(text, width) {
+				out = append(out, line{text: wrapped, center: true})
+			}
+		}
+	}
+
+	return out, nil
+}
+
+// Text menghasilkan struk teks lebar tetap untuk kertas 58mm atau 80mm.
+func (p *Printer) Text(t *models.Transaction, paper int, isCopy bool) ([]byte, error) {
+	lines, err := p.lines(t, paper, isCopy)
+	if err != nil {
+		return nil, err
+	}
+	width := Columns(paper)
+
+	var buf bytes.Buffer
+	for _, l := range lines {
+		text := l.text
+		if l.center {
+			text = center(text, width)
+		}
+		buf.WriteString(strings.TrimRight(text, " "))
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
+
+// Rupiah memformat nominal dengan pemisah ribuan titik, contoh 15000 -> 15.000.
+func Rupiah(amount int) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	digits := strconv.Itoa(amount)
+	var b strings.Builder
+	for i, c := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(c)
+	}
+	return sign + b.String()
+}
+
+// Modifier menulis nama modifier beserta tambahan harganya, contoh "Extra shot (+5.000)".
+func Modifier(m models.SelectedModifier) string {
+	if m.Price == 0 {
+		return m.Name
+	}
+	return fmt.Sprintf("%s (+%s)", m.Name, Rupiah(m.Price))
+}
+
+// Quantity menulis kuantitas dengan koma desimal, contoh 2 atau 0,75.
+func Quantity(q float64) string {
+	return strings.Replace(models.FormatQuantity(q), ".", ",", 1)
+}
+
+func columns(left string, right string, width int) string {
+	space := width - len([]rune(left)) - len([]rune(right))
+	if space < 1 {
+		space = 1
+	}
+	return left + strings.Repeat(" ", space) + right
+}
+
+func center(text string, width int) string {
+	pad := (width - len([]rune(text))) / 2
+	if pad <= 0 {
+		return text
+	}
+	return strings.Repeat(" ", pad) + text
+}
+
+// wrap memecah teks per kata agar tidak melebihi lebar kertas.
+func wrap(text string, width int) []string {
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		return []string{""}
+	}
+
+	result := make([]string, 0)
+	current := ""
+	for _, word := range words {
+		for len([]rune(word)) > width {
+			if current != "" {
+				result = append(result, current)
+				current = ""
+			}
+			result = append(result, string([]rune(word)[:width]))
+			word = string([]rune(word)[width:])
+		}
+		switch {
+		case current == "":
+			current = word
+		case len([]rune(current))+1+len([]rune(word)) <= width:
+			current += " " + word
+		default:
+			result = append(result, current)
+			current = word
+		}
+	}
+	if current != "" {
+		result = append(result, current)
+	}
+	return result
+}
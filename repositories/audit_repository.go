@@ -0,0 +1,147 @@
+package repositories
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"kasir-api/models"
+	"reflect"
+)
+
+type AuditRepository struct {
+	db *sql.DB
+}
+
+func NewAuditRepository(db *sql.DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+// GetAll mengambil audit log terbaru lebih dulu sesuai filter.
+func (repo *AuditRepository) GetAll(filter models.AuditFilter) ([]models.AuditEntry, error) {
+	query := `SELECT id, actor, user_id, action, entity, entity_id, before, after, changes, ip, user_agent, request_id, created_at
+			FROM audit_log
+			WHERE created_at >= $1 AND created_at < $2`
+	args := []interface{}{filter.Start, filter.End}
+
+	if filter.Entity != "" {
+		args = append(args, filter.Entity)
+		query += fmt.Sprintf(" AND entity = $%d", len(args))
+	}
+	if filter.EntityID != 0 {
+		args = append(args, filter.EntityID)
+		query += fmt.Sprintf(" AND entity_id = $%d", len(args))
+	}
+	if filter.Action != "" {
+		args = append(args, filter.Action)
+		query += fmt.Sprintf(" AND action = $%d", len(args))
+	}
+	if filter.Actor != "" {
+		args = append(args, filter.Actor)
+		query += fmt.Sprintf(" AND actor = $%d", len(args))
+	}
+	args = append(args, filter.Limit)
+	query += fmt.Sprintf(" ORDER BY created_at DESC, id DESC LIMIT $%d", len(args))
+
+	rows, err := repo.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := make([]models.AuditEntry, 0)
+	for rows.Next() {
+		var e models.AuditEntry
+		var before, after, changes []byte
+		err := rows.Scan(&e.ID, &e.Actor, &e.UserID, &e.Action, &e.Entity, &e.EntityID, &before, &after, &changes, &e.IP, &e.UserAgent, &e.RequestID, &e.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		e.Before = before
+		e.After = after
+		if changes != nil {
+			if err := json.Unmarshal(changes, &e.Changes); err != nil {
+				return nil, err
+			}
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// recordAudit menulis satu baris audit log di dalam tx perubahan, sehingga perubahan tanpa
+// jejak audit tidak pernah tersimpan. before nil untuk create dan after nil untuk delete.
+func recordAudit(tx *sql.Tx, meta models.AuditMeta, action string, entity string, entityID int, before interface{}, after interface{}) error {
+	beforeJSON, err := auditJSON(before)
+	if err != nil {
+		return err
+	}
+	afterJSON, err := auditJSON(after)
+	if err != nil {
+		return err
+	}
+
+	var changesJSON []byte
+	if beforeJSON != nil && afterJSON != nil {
+		changes, err := auditChanges(beforeJSON, afterJSON)
+		if err != nil {
+			return err
+		}
+		// Update tanpa perubahan nilai tidak perlu dicatat
+		if len(changes) == 0 {
+			return nil
+		}
+		changesJSON, err = json.Marshal(changes)
+		if err != nil {
+			return err
+		}
+	}
+
+	actor := meta.Actor
+	if actor == "" {
+		actor = "anonymous"
+	}
+
+	_, err = tx.Exec(`INSERT INTO audit_log (actor, user_id, action, entity, entity_id, before, after, changes, ip, user_agent, request_id)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
+		actor, meta.UserID, action, entity, entityID, jsonParam(beforeJSON), jsonParam(afterJSON), jsonParam(changesJSON), meta.IP, meta.UserAgent, meta.RequestID)
+	return err
+}
+
+// jsonParam mengirim JSON sebagai teks; lib/pq mengirim []byte sebagai bytea yang tidak bisa dibaca JSONB.
+func jsonParam(b []byte) interface{} {
+	if b == nil {
+		return nil
+	}
+	return string(b)
+}
+
+func auditJSON(v interface{}) ([]byte, error) {
+	if v == nil || (reflect.ValueOf(v).Kind() == reflect.Ptr && reflect.ValueOf(v).IsNil()) {
+		return nil, nil
+	}
+	return json.Marshal(v)
+}
+
+// auditChanges membandingkan field JSON tingkat atas sebelum dan sesudah perubahan.
+func auditChanges(before []byte, after []byte) (map[string]models.AuditChange, error) {
+	var from, to map[string]interface{}
+	if err := json.Unmarshal(before, &from); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(after, &to); err != nil {
+		return nil, err
+	}
+
+	changes := make(map[string]models.AuditChange)
+	for key, value := range to {
+		if !reflect.DeepEqual(from[key], value) {
+			changes[key] = models.AuditChange{From: from[key], To: value}
+		}
+	}
+	for key, value := range from {
+		if _, ok := to[key]; !ok {
+			changes[key] = models.AuditChange{From: value, To: nil}
+		}
+	}
+	return changes, nil
+}
@@ -0,0 +1,560 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"kasir-api/apperror"
+	"kasir-api/models"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type CartRepository struct {
+	db *sql.DB
+}
+
+func NewCartRepository(db *sql.DB) *CartRepository {
+	return &CartRepository{db: db}
+}
+
+// reservedStock menyusun ekspresi SQL untuk stok (dalam satuan dasar) produk product yang sedang dipesan
+// keranjang di outlet selain keranjang cart, termasuk yang dipesan sebagai komponen paket atau resep.
+// product, cart dan outlet adalah ekspresi SQL; cart 0 berarti semua keranjang dihitung. Hanya keranjang
+// open/held dengan reservasi yang belum kedaluwarsa dihitung.
+func reservedStock(product string, cart string, outlet string) string {
+	return `COALESCE((SELECT SUM(rci.quantity * COALESCE(rpu.factor, 1) * COALESCE(rpc.quantity, 1))
+			FROM cart_items rci
+			JOIN carts rc ON rci.cart_id = rc.id
+			LEFT JOIN product_units rpu ON rpu.product_id = rci.product_id AND rpu.unit = rci.unit
+			LEFT JOIN product_components rpc ON rpc.product_id = rci.product_id AND rpc.component_id = ` + product + `
+			WHERE (rci.product_id = ` + product + ` OR rpc.component_id IS NOT NULL) AND rc.id <> ` + cart + ` AND rc.reserve
+				AND rc.outlet_id = ` + outlet + `
+				AND rc.status IN ('open', 'held') AND rc.reserved_until > NOW()), 0)`
+}
+
+// reservedByOtherCarts adalah reservedStock untuk query keranjang: p adalah produk dan $1 id keranjang
+// yang sedang diproses, dibandingkan dengan keranjang lain di outlet yang sama.
+var reservedByOtherCarts = reservedStock("p.id", "$1", "(SELECT outlet_id FROM carts WHERE id = $1)")
+
+// otherCartLines menyusun ekspresi SQL untuk stok produk p (dalam satuan dasar, termasuk sebagai komponen)
+// yang dipakai baris lain keranjang $1 selain baris line. Baris keranjang sendiri selalu dihitung, dengan
+// atau tanpa reservasi, karena semuanya di-checkout bersama.
+func otherCartLines(line string) string {
+	return `COALESCE((SELECT SUM(oci.quantity * COALESCE(opu.factor, 1) * COALESCE(opc.quantity, 1))
+			FROM cart_items oci
+			LEFT JOIN product_units opu ON opu.product_id = oci.product_id AND opu.unit = oci.unit
+			LEFT JOIN product_components opc ON opc.product_id = oci.product_id AND opc.component_id = p.id
+			WHERE (oci.product_id = p.id OR opc.component_id IS NOT NULL) AND oci.cart_id = $1 AND oci.id <> ` + line + `), 0)`
+}
+
+const cartColumns = "id, outlet_id, status, terminal, note, customer_id, reserve, reserved_until, transaction_id, created_at, updated_at"
+
+func scanCart(row interface{ Scan(...interface{}) error }) (*models.Cart, error) {
+	var c models.Cart
+	err := row.Scan(&c.ID, &c.OutletID, &c.Status, &c.Terminal, &c.Note, &c.CustomerID, &c.Reserve, &c.ReservedUntil, &c.TransactionID, &c.CreatedAt, &c.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	c.Items = make([]models.CartItem, 0)
+	return &c, nil
+}
+
+func (repo *CartRepository) Create(outletID int, cart *models.Cart, ttl time.Duration) error {
+	query := `INSERT INTO carts (outlet_id, terminal, note, customer_id, reserve, reserved_until)
+			VALUES ($1, $2, $3, $4, $5, CASE WHEN $5 THEN NOW() + $6::float8 * INTERVAL '1 second' END)
+			RETURNING ` + cartColumns
+	c, err := scanCart(repo.db.QueryRow(query, outletID, cart.Terminal, cart.Note, cart.CustomerID, cart.Reserve, ttl.Seconds()))
+	if err != nil {
+		return err
+	}
+	*cart = *c
+	return nil
+}
+
+// GetAll mengambil keranjang outlet berdasarkan status (kosong berarti open dan held),
+// supaya semua terminal di outlet bisa melihat keranjang yang sedang ditahan.
+func (repo *CartRepository) GetAll(outletID int, status string) ([]models.Cart, error) {
+	statuses := []string{models.CartOpen, models.CartHeld}
+	if status != "" {
+		statuses = []string{status}
+	}
+
+	rows, err := repo.db.Query("SELECT "+cartColumns+" FROM carts WHERE outlet_id = $1 AND status = ANY($2) ORDER BY updated_at DESC", outletID, pq.Array(statuses))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	carts := make([]models.Cart, 0)
+	for rows.Next() {
+		c, err := scanCart(rows)
+		if err != nil {
+			return nil, err
+		}
+		carts = append(carts, *c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	for i := range carts {
+		if err := repo.loadItems(repo.db, &carts[i]); err != nil {
+			return nil, err
+		}
+	}
+	return carts, nil
+}
+
+func (repo *CartRepository) GetByID(outletID int, id int) (*models.Cart, error) {
+	c, err := scanCart(repo.db.QueryRow("SELECT "+cartColumns+" FROM carts WHERE id = $1 AND outlet_id = $2", id, outletID))
+	if err == sql.ErrNoRows {
+		return nil, apperror.NotFound("cart not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := repo.loadItems(repo.db, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+type querier interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// loadItems mengisi item keranjang dengan harga dan stok tersedia terkini di outlet keranjang. Stok
+// tersedia setiap baris sudah dikurangi reservasi keranjang lain dan baris lain di keranjang ini.
+func (repo *CartRepository) loadItems(q querier, cart *models.Cart) error {
+	query := `SELECT ci.id, ci.product_id, p.name, p.unit, p.type, COALESCE(ci.unit, ''), COALESCE(op.price, p.price), pu.factor, pu.price,
+				ci.quantity, ci.modifier_ids, COALESCE(op.stock, 0) - ` + reservedByOtherCarts + ` - ` + otherCartLines("ci.id") + `
+			FROM cart_items ci
+			JOIN carts c ON ci.cart_id = c.id
+			JOIN products p ON ci.product_id = p.id
+			LEFT JOIN outlet_products op ON op.product_id = p.id AND op.outlet_id = c.outlet_id
+			LEFT JOIN product_units pu ON pu.product_id = p.id AND pu.unit = ci.unit
+			WHERE ci.cart_id = $1
+			ORDER BY p.name, ci.id`
+
+	rows, err := q.Query(query, cart.ID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	cart.Items = make([]models.CartItem, 0)
+	cart.TotalAmount = 0
+	composites := make(map[int]string)
+	saleUnits := make([]*saleUnit, 0)
+	modifierIDs := make([]pq.Int64Array, 0)
+	allModifierIDs := make([]int64, 0)
+	for rows.Next() {
+		var item models.CartItem
+		var baseUnit, productType, unit string
+		var basePrice int
+		var factor sql.NullFloat64
+		var price sql.NullInt64
+		var modifiers pq.Int64Array
+		err := rows.Scan(&item.ID, &item.ProductID, &item.ProductName, &baseUnit, &productType, &unit, &basePrice, &factor, &price, &item.Quantity, &modifiers, &item.AvailableStock)
+		if err != nil {
+			return err
+		}
+		su := newSaleUnit(baseUnit, basePrice, unit, factor, price)
+		item.Unit = su.Unit
+		item.Price = su.Price
+		item.BaseQuantity = su.BaseQuantity(item.Quantity)
+		item.AvailableStock = models.RoundQuantity(item.AvailableStock)
+		cart.Items = append(cart.Items, item)
+		saleUnits = append(saleUnits, su)
+		modifierIDs = append(modifierIDs, modifiers)
+		allModifierIDs = append(allModifierIDs, modifiers...)
+		if productType != models.ProductStandard {
+			composites[len(cart.Items)-1] = baseUnit
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	rows.Close()
+
+	// Modifier yang sudah dihapus dari produk tidak ditampilkan; checkout akan menolak item yang
+	// tidak lagi memenuhi batas pilihan
+	modifiers, err := modifiersByID(q, allModifierIDs)
+	if err != nil {
+		return err
+	}
+	for i := range cart.Items {
+		item := &cart.Items[i]
+		for _, id := range modifierIDs[i] {
+			if m, ok := modifiers[int(id)]; ok {
+				item.Modifiers = append(item.Modifiers, m)
+			}
+		}
+		item.Subtotal = saleUnits[i].Subtotal(item.Quantity, models.ModifierPrice(item.Modifiers))
+		cart.TotalAmount += item.Subtotal
+	}
+
+	// Stok paket dan resep dihitung dari komponennya setelah rows ditutup, karena tx tidak bisa
+	// menjalankan dua query sekaligus
+	for i, baseUnit := range composites {
+		available, err := componentsAvailable(q, cart.ID, cart.OutletID, cart.Items[i].ProductID, cart.Items[i].ID, baseUnit)
+		if err != nil {
+			return err
+		}
+		cart.Items[i].AvailableStock = available
+	}
+	return nil
+}
+
+// componentsAvailable menghitung stok paket atau resep productID yang masih bisa dipesan baris lineID
+// keranjang cartID, dari stok komponen di outlet dikurangi reservasi keranjang lain dan baris lain di
+// keranjang ini.
+func componentsAvailable(q querier, cartID int, outletID int, productID int, lineID int, unit string) (float64, error) {
+	rows, err := q.Query(`SELECT pc.quantity, COALESCE(op.stock, 0) - `+reservedByOtherCarts+` - `+otherCartLines("$4")+`
+			FROM product_components pc
+			JOIN products p ON p.id = pc.component_id
+			LEFT JOIN outlet_products op ON op.product_id = p.id AND op.outlet_id = $3
+			WHERE pc.product_id = $2`, cartID, productID, outletID, lineID)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	components := make([]componentStock, 0)
+	for rows.Next() {
+		var c componentStock
+		if err := rows.Scan(&c.quantity, &c.stock); err != nil {
+			return 0, err
+		}
+		components = append(components, c)
+	}
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	return availableFromComponents(unit, components), nil
+}
+
+// lockCart mengunci keranjang milik outlet selama transaksi database dan mengembalikan status serta opsi reservasinya.
+func lockCart(tx *sql.Tx, outletID int, id int) (*models.Cart, error) {
+	c, err := scanCart(tx.QueryRow("SELECT "+cartColumns+" FROM carts WHERE id = $1 AND outlet_id = $2 FOR UPDATE", id, outletID))
+	if err == sql.ErrNoRows {
+		return nil, apperror.NotFound("cart not found")
+	}
+	return c, err
+}
+
+// AddItem menambah produk ke keranjang dalam satuan jual unit (kosong berarti satuan dasar) dengan
+// modifier yang dipilih. Jumlahnya ditambahkan ke baris produk dengan satuan dan modifier yang sama;
+// satuan atau modifier lain menjadi baris baru.
+func (repo *CartRepository) AddItem(outletID int, cartID int, productID int, quantity float64, unit string, modifiers []int, ttl time.Duration) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockOpenCart(tx, outletID, cartID); err != nil {
+		return err
+	}
+
+	var baseUnit string
+	err = tx.QueryRow("SELECT unit FROM products WHERE id = $1", productID).Scan(&baseUnit)
+	if err == sql.ErrNoRows {
+		return apperror.NotFound("product id %d not found", productID)
+	}
+	if err != nil {
+		return err
+	}
+	if unit == baseUnit {
+		unit = ""
+	}
+
+	rows, err := tx.Query("SELECT id, quantity, COALESCE(unit, ''), modifier_ids FROM cart_items WHERE cart_id = $1 AND product_id = $2 ORDER BY id", cartID, productID)
+	if err != nil {
+		return err
+	}
+	line := cartLine{productID: productID, quantity: quantity, unit: unit, modifiers: modifiers}
+	for rows.Next() {
+		var id int
+		var current float64
+		var currentUnit string
+		var currentModifiers pq.Int64Array
+		if err := rows.Scan(&id, &current, &currentUnit, &currentModifiers); err != nil {
+			rows.Close()
+			return err
+		}
+		if line.id == 0 && currentUnit == unit && sameModifiers(currentModifiers, modifiers) {
+			line.id = id
+			line.quantity = models.RoundQuantity(quantity + current)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if err := saveItem(tx, outletID, cartID, line); err != nil {
+		return err
+	}
+	if err := touchCart(tx, cartID, ttl); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// SetItem mengganti jumlah baris keranjang itemID, jumlah 0 atau kurang menghapus baris. unit kosong
+// mempertahankan satuan baris dan modifiers nil mempertahankan modifier baris.
+func (repo *CartRepository) SetItem(outletID int, cartID int, itemID int, quantity float64, unit string, modifiers []int, ttl time.Duration) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockOpenCart(tx, outletID, cartID); err != nil {
+		return err
+	}
+
+	var productID int
+	var currentUnit, baseUnit string
+	var currentModifiers pq.Int64Array
+	err = tx.QueryRow(`SELECT ci.product_id, COALESCE(ci.unit, ''), ci.modifier_ids, p.unit
+			FROM cart_items ci
+			JOIN products p ON p.id = ci.product_id
+			WHERE ci.id = $1 AND ci.cart_id = $2`, itemID, cartID).Scan(&productID, &currentUnit, &currentModifiers, &baseUnit)
+	if err == sql.ErrNoRows {
+		return apperror.NotFound("cart item %d not found", itemID)
+	}
+	if err != nil {
+		return err
+	}
+
+	if quantity <= 0 {
+		_, err = tx.Exec("DELETE FROM cart_items WHERE id = $1", itemID)
+	} else {
+		// Satuan dasar disimpan sebagai NULL
+		if unit == "" {
+			unit = currentUnit
+		}
+		if unit == baseUnit {
+			unit = ""
+		}
+		if modifiers == nil {
+			for _, id := range currentModifiers {
+				modifiers = append(modifiers, int(id))
+			}
+		}
+		err = saveItem(tx, outletID, cartID, cartLine{id: itemID, productID: productID, quantity: quantity, unit: unit, modifiers: modifiers})
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := touchCart(tx, cartID, ttl); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// lockOpenCart mengunci keranjang dan memastikan item-nya masih boleh diubah.
+func lockOpenCart(tx *sql.Tx, outletID int, cartID int) error {
+	cart, err := lockCart(tx, outletID, cartID)
+	if err != nil {
+		return err
+	}
+	if cart.Status != models.CartOpen {
+		return apperror.Conflict("cart %d is %s, resume it before changing items", cartID, cart.Status)
+	}
+	return nil
+}
+
+// cartLine adalah satu baris item keranjang yang akan disimpan; id 0 berarti baris baru.
+type cartLine struct {
+	id        int
+	productID int
+	quantity  float64
+	unit      string
+	modifiers []int
+}
+
+// saveItem memeriksa satuan, modifier dan stok baris keranjang lalu menyimpannya. Stok divalidasi
+// terhadap stok outlet dikurangi reservasi keranjang lain dan baris lain di keranjang ini, dalam
+// satuan dasar.
+func saveItem(tx *sql.Tx, outletID int, cartID int, line cartLine) error {
+	// Kunci baris produk agar dua keranjang tidak memesan sisa stok yang sama
+	var baseUnit, productType string
+	var price int
+	var available float64
+	var active bool
+	err := tx.QueryRow(`SELECT p.unit, p.type, COALESCE(op.price, p.price), COALESCE(op.stock, 0) - `+reservedByOtherCarts+` - `+otherCartLines("$4")+`, p.deleted_at IS NULL
+			FROM products p
+			LEFT JOIN outlet_products op ON op.product_id = p.id AND op.outlet_id = $3
+			WHERE p.id = $2 FOR UPDATE OF p`, cartID, line.productID, outletID, line.id).Scan(&baseUnit, &productType, &price, &available, &active)
+	if err == sql.ErrNoRows || (err == nil && !active) {
+		return apperror.NotFound("product id %d not found", line.productID)
+	}
+	if err != nil {
+		return err
+	}
+	if productType != models.ProductStandard {
+		available, err = componentsAvailable(tx, cartID, outletID, line.productID, line.id, baseUnit)
+		if err != nil {
+			return err
+		}
+	}
+
+	su, err := resolveSaleUnit(tx, line.productID, baseUnit, price, line.unit, line.quantity)
+	if err != nil {
+		return err
+	}
+	selected, err := resolveModifiers(tx, line.productID, line.modifiers)
+	if err != nil {
+		return err
+	}
+	if base := su.BaseQuantity(line.quantity); base > models.RoundQuantity(available) {
+		return fmt.Errorf("%w for product id %d: available %s %s, requested %s %s", ErrInsufficientStock, line.productID,
+			models.FormatQuantity(available), baseUnit, models.FormatQuantity(base), baseUnit)
+	}
+
+	// Modifier disimpan sesuai urutan kelompok di produk
+	ids := make(pq.Int64Array, len(selected))
+	for i, m := range selected {
+		ids[i] = int64(m.ModifierID)
+	}
+	if line.id == 0 {
+		_, err = tx.Exec("INSERT INTO cart_items (cart_id, product_id, quantity, unit, modifier_ids) VALUES ($1, $2, $3, NULLIF($4, ''), $5)",
+			cartID, line.productID, line.quantity, line.unit, ids)
+	} else {
+		_, err = tx.Exec("UPDATE cart_items SET quantity = $1, unit = NULLIF($2, ''), modifier_ids = $3 WHERE id = $4",
+			line.quantity, line.unit, ids, line.id)
+	}
+	return err
+}
+
+// sameModifiers melaporkan apakah modifier item keranjang sama dengan pilihan baru, tanpa melihat urutan.
+func sameModifiers(current pq.Int64Array, selected []int) bool {
+	if len(current) != len(selected) {
+		return false
+	}
+	ids := make(map[int64]bool, len(current))
+	for _, id := range current {
+		ids[id] = true
+	}
+	for _, id := range selected {
+		if !ids[int64(id)] {
+			return false
+		}
+	}
+	return true
+}
+
+// touchCart memperbarui updated_at dan memperpanjang reservasi stok.
+func touchCart(tx *sql.Tx, cartID int, ttl time.Duration) error {
+	_, err := tx.Exec(`UPDATE carts SET updated_at = NOW(),
+				reserved_until = CASE WHEN reserve THEN NOW() + $2::float8 * INTERVAL '1 second' END
+			WHERE id = $1`, cartID, ttl.Seconds())
+	return err
+}
+
+// SetStatus memindahkan keranjang ke status to jika status sekarang termasuk from.
+func (repo *CartRepository) SetStatus(outletID int, cartID int, from []string, to string, ttl time.Duration) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	cart, err := lockCart(tx, outletID, cartID)
+	if err != nil {
+		return err
+	}
+
+	allowed := false
+	for _, status := range from {
+		if cart.Status == status {
+			allowed = true
+		}
+	}
+	if !allowed {
+		return apperror.Conflict("cannot change cart %d from %s to %s", cartID, cart.Status, to)
+	}
+
+	_, err = tx.Exec("UPDATE carts SET status = $1 WHERE id = $2", to, cartID)
+	if err != nil {
+		return err
+	}
+
+	if to == models.CartAbandoned {
+		// Keranjang batal melepas reservasi stoknya
+		_, err = tx.Exec("UPDATE carts SET updated_at = NOW(), reserved_until = NULL WHERE id = $1", cartID)
+	} else {
+		err = touchCart(tx, cartID, ttl)
+	}
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Checkout mengubah keranjang menjadi transaksi dalam satu transaksi database:
+// keranjang dikunci, stok divalidasi ulang, transaksi dibuat, lalu status keranjang ditutup.
+func (repo *CartRepository) Checkout(outletID int, cartID int, opts models.CartCheckoutRequest, rules models.LoyaltyRules, audit models.AuditMeta) (*models.Transaction, error) {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	cart, err := lockCart(tx, outletID, cartID)
+	if err != nil {
+		return nil, err
+	}
+	if cart.Status != models.CartOpen && cart.Status != models.CartHeld {
+		return nil, apperror.Conflict("cart %d is already %s", cartID, cart.Status)
+	}
+
+	if err := repo.loadItems(tx, cart); err != nil {
+		return nil, err
+	}
+	if len(cart.Items) == 0 {
+		return nil, apperror.Conflict("cart %d is empty", cartID)
+	}
+
+	req := models.CheckoutRequest{
+		Items:         make([]models.CheckoutItem, 0, len(cart.Items)),
+		CustomerID:    cart.CustomerID,
+		CustomerPhone: opts.CustomerPhone,
+		RedeemPoints:  opts.RedeemPoints,
+		VoucherCode:   opts.VoucherCode,
+		GiftCardCode:  opts.GiftCardCode,
+	}
+	for _, item := range cart.Items {
+		if item.BaseQuantity > item.AvailableStock {
+			return nil, fmt.Errorf("%w for product id %d: available %s, requested %s", ErrInsufficientStock, item.ProductID,
+				models.FormatQuantity(item.AvailableStock), models.FormatQuantity(item.BaseQuantity))
+		}
+		modifiers := make([]int, len(item.Modifiers))
+		for i, m := range item.Modifiers {
+			modifiers[i] = m.ModifierID
+		}
+		req.Items = append(req.Items, models.CheckoutItem{ProductID: item.ProductID, Quantity: item.Quantity, Unit: item.Unit, Modifiers: modifiers})
+	}
+
+	transaction, err := createTransaction(tx, outletID, req, rules, transactionMeta{audit: audit, cartID: cartID})
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec("UPDATE carts SET status = $1, transaction_id = $2, reserved_until = NULL, updated_at = NOW() WHERE id = $3", models.CartCheckedOut, transaction.ID, cartID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return transaction, nil
+}
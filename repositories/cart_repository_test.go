@@ -0,0 +1,32 @@
+package repositories
+
+import (
+	"testing"
+
+	"github.com/lib/pq"
+)
+
+func TestSameModifiers(t *testing.T) {
+	cases := []struct {
+		name     string
+		current  pq.Int64Array
+		selected []int
+		want     bool
+	}{
+		{"both empty", pq.Int64Array{}, nil, true},
+		{"same order", pq.Int64Array{1, 4}, []int{1, 4}, true},
+		{"different order", pq.Int64Array{1, 4}, []int{4, 1}, true},
+		{"one more", pq.Int64Array{1}, []int{1, 4}, false},
+		{"one less", pq.Int64Array{1, 4}, []int{1}, false},
+		{"other modifier", pq.Int64Array{1, 4}, []int{1, 5}, false},
+		{"none versus some", pq.Int64Array{}, []int{2}, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := sameModifiers(tc.current, tc.selected); got != tc.want {
+				t.Fatalf("sameModifiers(%v, %v) = %v, want %v", tc.current, tc.selected, got, tc.want)
+			}
+		})
+	}
+}
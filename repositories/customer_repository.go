@@ -0,0 +1,233 @@
+package repositories
+
+import (
+	"database/sql"
+	"kasir-api/apperror"
+	"kasir-api/models"
+	"time"
+)
+
+type CustomerRepository struct {
+	db *sql.DB
+}
+
+func NewCustomerRepository(db *sql.DB) *CustomerRepository {
+	return &CustomerRepository{db: db}
+}
+
+func (repo *CustomerRepository) GetAll(search string) ([]models.Customer, error) {
+	args := []interface{}{}
+	query := "SELECT id, name, COALESCE(phone, ''), COALESCE(email, ''), created_at FROM customers"
+	if search != "" {
+		query += " WHERE name ILIKE $1 OR phone ILIKE $1 OR email ILIKE $1"
+		args = append(args, "%"+search+"%")
+	}
+	query += " ORDER BY name"
+
+	rows, err := repo.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	customers := make([]models.Customer, 0)
+	for rows.Next() {
+		var c models.Customer
+		err := rows.Scan(&c.ID, &c.Name, &c.Phone, &c.Email, &c.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		customers = append(customers, c)
+	}
+	return customers, rows.Err()
+}
+
+func (repo *CustomerRepository) GetByID(id int) (*models.Customer, error) {
+	query := "SELECT id, name, COALESCE(phone, ''), COALESCE(email, ''), created_at FROM customers WHERE id = $1"
+
+	var c models.Customer
+	err := repo.db.QueryRow(query, id).Scan(&c.ID, &c.Name, &c.Phone, &c.Email, &c.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, apperror.NotFound("customer not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (repo *CustomerRepository) Create(customer *models.Customer, audit models.AuditMeta) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := "INSERT INTO customers (name, phone, email) VALUES ($1, NULLIF($2, ''), NULLIF($3, '')) RETURNING id, created_at"
+	err = tx.QueryRow(query, customer.Name, customer.Phone, customer.Email).Scan(&customer.ID, &customer.CreatedAt)
+	if err != nil {
+		return uniqueConflict(err, "customers_phone_key", "phone %s is already used by another customer", customer.Phone)
+	}
+
+	if err := recordAudit(tx, audit, models.AuditCreate, models.AuditEntityCustomer, customer.ID, nil, customer); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (repo *CustomerRepository) Update(customer *models.Customer, audit models.AuditMeta) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var before models.Customer
+	err = tx.QueryRow("SELECT id, name, COALESCE(phone, ''), COALESCE(email, ''), created_at FROM customers WHERE id = $1 FOR UPDATE", customer.ID).
+		Scan(&before.ID, &before.Name, &before.Phone, &before.Email, &before.CreatedAt)
+	if err == sql.ErrNoRows {
+		return apperror.NotFound("customer not found")
+	}
+	if err != nil {
+		return err
+	}
+
+	query := "UPDATE customers SET name = $1, phone = NULLIF($2, ''), email = NULLIF($3, '') WHERE id = $4 RETURNING created_at"
+	err = tx.QueryRow(query, customer.Name, customer.Phone, customer.Email, customer.ID).Scan(&customer.CreatedAt)
+	if err != nil {
+		return uniqueConflict(err, "customers_phone_key", "phone %s is already used by another customer", customer.Phone)
+	}
+
+	if err := recordAudit(tx, audit, models.AuditUpdate, models.AuditEntityCustomer, customer.ID, &before, customer); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetTransactions mengambil riwayat belanja pelanggan di semua outlet, transaksi terbaru lebih dulu.
+// Pelanggan dan poin berlaku lintas outlet.
+func (repo *CustomerRepository) GetTransactions(customerID int) ([]models.Transaction, error) {
+	query := `SELECT t.id, t.outlet_id, t.total_amount, t.discount_amount, t.gift_card_amount, t.created_at,
+				td.id, td.product_id, COALESCE(p.name, ''), td.quantity, td.unit, td.base_quantity, td.unit_price, td.subtotal
+			FROM transactions t
+			JOIN transaction_details td ON td.transaction_id = t.id
+			LEFT JOIN products p ON td.product_id = p.id
+			WHERE t.customer_id = $1
+			ORDER BY t.created_at DESC, t.id DESC, td.id`
+
+	rows, err := repo.db.Query(query, customerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	transactions := make([]models.Transaction, 0)
+	for rows.Next() {
+		var t models.Transaction
+		var d models.TransactionDetail
+		err := rows.Scan(&t.ID, &t.OutletID, &t.TotalAmount, &t.DiscountAmount, &t.GiftCardAmount, &t.CreatedAt, &d.ID, &d.ProductID, &d.ProductName, &d.Quantity, &d.Unit, &d.BaseQuantity, &d.UnitPrice, &d.Subtotal)
+		if err != nil {
+			return nil, err
+		}
+		t.AmountDue = t.TotalAmount - t.GiftCardAmount
+		d.TransactionID = t.ID
+
+		last := len(transactions) - 1
+		if last < 0 || transactions[last].ID != t.ID {
+			t.CustomerID = &customerID
+			t.Details = make([]models.TransactionDetail, 0)
+			transactions = append(transactions, t)
+			last++
+		}
+		transactions[last].Details = append(transactions[last].Details, d)
+	}
+	return transactions, rows.Err()
+}
+
+// GetLoyalty menghitung saldo poin setelah memproses poin kedaluwarsa, beserta buku poinnya.
+func (repo *CustomerRepository) GetLoyalty(customerID int) (int, []models.LoyaltyEntry, error) {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return 0, nil, err
+	}
+	defer tx.Rollback()
+
+	if err := lockCustomer(tx, customerID); err != nil {
+		return 0, nil, err
+	}
+
+	if err := expirePoints(tx, customerID, time.Now()); err != nil {
+		return 0, nil, err
+	}
+
+	balance, err := pointsBalance(tx, customerID)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	query := `SELECT id, customer_id, transaction_id, type, points, expires_at, created_at
+			FROM loyalty_ledger WHERE customer_id = $1 ORDER BY created_at DESC, id DESC`
+	rows, err := tx.Query(query, customerID)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer rows.Close()
+
+	ledger := make([]models.LoyaltyEntry, 0)
+	for rows.Next() {
+		var e models.LoyaltyEntry
+		err := rows.Scan(&e.ID, &e.CustomerID, &e.TransactionID, &e.Type, &e.Points, &e.ExpiresAt, &e.CreatedAt)
+		if err != nil {
+			return 0, nil, err
+		}
+		ledger = append(ledger, e)
+	}
+	if err := rows.Err(); err != nil {
+		return 0, nil, err
+	}
+	rows.Close()
+
+	if err := tx.Commit(); err != nil {
+		return 0, nil, err
+	}
+	return balance, ledger, nil
+}
+
+// lockCustomer mengunci baris pelanggan sampai transaksi database selesai,
+// sehingga penukaran poin dari dua checkout bersamaan diproses bergantian.
+func lockCustomer(tx *sql.Tx, customerID int) error {
+	var id int
+	err := tx.QueryRow("SELECT id FROM customers WHERE id = $1 FOR UPDATE", customerID).Scan(&id)
+	if err == sql.ErrNoRows {
+		return apperror.NotFound("customer not found")
+	}
+	return err
+}
+
+func pointsBalance(tx *sql.Tx, customerID int) (int, error) {
+	var balance int
+	err := tx.QueryRow("SELECT COALESCE(SUM(points), 0) FROM loyalty_ledger WHERE customer_id = $1", customerID).Scan(&balance)
+	return balance, err
+}
+
+// expirePoints mencatat entri expire untuk poin yang sudah lewat masa berlaku.
+// Poin dipakai secara FIFO: redeem dan expire sebelumnya dianggap menghabiskan poin
+// terlama lebih dulu, sehingga yang hangus adalah sisa poin kedaluwarsa yang belum terpakai.
+func expirePoints(tx *sql.Tx, customerID int, now time.Time) error {
+	var expired, consumed int
+	query := `SELECT
+				COALESCE(SUM(points) FILTER (WHERE type = 'earn' AND expires_at IS NOT NULL AND expires_at <= $2), 0),
+				COALESCE(-SUM(points) FILTER (WHERE type IN ('redeem', 'expire')), 0)
+			FROM loyalty_ledger WHERE customer_id = $1`
+	err := tx.QueryRow(query, customerID, now).Scan(&expired, &consumed)
+	if err != nil {
+		return err
+	}
+
+	if expired <= consumed {
+		return nil
+	}
+
+	_, err = tx.Exec("INSERT INTO loyalty_ledger (customer_id, type, points) VALUES ($1, $2, $3)", customerID, models.LoyaltyExpire, -(expired - consumed))
+	return err
+}
@@ -0,0 +1,16 @@
+package repositories
+
+import (
+	"kasir-api/apperror"
+
+	"github.com/lib/pq"
+)
+
+// uniqueConflict mengubah pelanggaran constraint unik tertentu menjadi error Conflict dengan pesan
+// format/args. Error lain dikembalikan apa adanya.
+func uniqueConflict(err error, constraint string, format string, args ...interface{}) error {
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" && pqErr.Constraint == constraint {
+		return apperror.Conflict(format, args...)
+	}
+	return err
+}
@@ -0,0 +1,34 @@
+package repositories
+
+import (
+	"errors"
+	"kasir-api/apperror"
+	"testing"
+
+	"github.com/lib/pq"
+)
+
+func TestUniqueConflict(t *testing.T) {
+	cases := []struct {
+		name       string
+		err        error
+		constraint string
+		want       apperror.Code
+	}{
+		{"duplicate voucher code", &pq.Error{Code: "23505", Constraint: "vouchers_code_key"}, "vouchers_code_key", apperror.CodeConflict},
+		{"duplicate gift card code", &pq.Error{Code: "23505", Constraint: "gift_cards_code_key"}, "gift_cards_code_key", apperror.CodeConflict},
+		{"duplicate customer phone", &pq.Error{Code: "23505", Constraint: "customers_phone_key"}, "customers_phone_key", apperror.CodeConflict},
+		{"constraint of another table", &pq.Error{Code: "23505", Constraint: "vouchers_code_key"}, "gift_cards_code_key", apperror.CodeInternal},
+		{"other unique constraint", &pq.Error{Code: "23505", Constraint: "customers_pkey"}, "customers_phone_key", apperror.CodeInternal},
+		{"other pq error", &pq.Error{Code: "23514", Constraint: "vouchers_code_key"}, "vouchers_code_key", apperror.CodeInternal},
+		{"not a pq error", errors.New("connection reset"), "vouchers_code_key", apperror.CodeInternal},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := apperror.CodeOf(uniqueConflict(tc.err, tc.constraint, "duplicate %s", "HEMAT10")); got != tc.want {
+				t.Fatalf("got %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
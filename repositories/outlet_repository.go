@@ -0,0 +1,187 @@
+package repositories
+
+import (
+	"database/sql"
+	"kasir-api/apperror"
+	"kasir-api/models"
+
+	"github.com/lib/pq"
+)
+
+// ErrOutletNotFound dikembalikan jika id outlet tidak ada
+var ErrOutletNotFound = apperror.NotFound("outlet not found")
+
+type OutletRepository struct {
+	db *sql.DB
+}
+
+func NewOutletRepository(db *sql.DB) *OutletRepository {
+	return &OutletRepository{db: db}
+}
+
+func (repo *OutletRepository) GetAll() ([]models.Outlet, error) {
+	rows, err := repo.db.Query("SELECT id, code, name, address, phone, created_at FROM outlets ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	outlets := make([]models.Outlet, 0)
+	for rows.Next() {
+		var o models.Outlet
+		err := rows.Scan(&o.ID, &o.Code, &o.Name, &o.Address, &o.Phone, &o.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		outlets = append(outlets, o)
+	}
+	return outlets, rows.Err()
+}
+
+func (repo *OutletRepository) GetByID(id int) (*models.Outlet, error) {
+	var o models.Outlet
+	err := repo.db.QueryRow("SELECT id, code, name, address, phone, created_at FROM outlets WHERE id = $1", id).
+		Scan(&o.ID, &o.Code, &o.Name, &o.Address, &o.Phone, &o.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrOutletNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
+
+func (repo *OutletRepository) Create(o *models.Outlet, audit models.AuditMeta) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := "INSERT INTO outlets (code, name, address, phone) VALUES ($1, $2, $3, $4) RETURNING id, created_at"
+	err = tx.QueryRow(query, o.Code, o.Name, o.Address, o.Phone).Scan(&o.ID, &o.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	if err := recordAudit(tx, audit, models.AuditCreate, models.AuditEntityOutlet, o.ID, nil, o); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (repo *OutletRepository) Update(o *models.Outlet, audit models.AuditMeta) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var before models.Outlet
+	err = tx.QueryRow("SELECT id, code, name, address, phone, created_at FROM outlets WHERE id = $1 FOR UPDATE", o.ID).
+		Scan(&before.ID, &before.Code, &before.Name, &before.Address, &before.Phone, &before.CreatedAt)
+	if err == sql.ErrNoRows {
+		return ErrOutletNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	query := "UPDATE outlets SET code = $1, name = $2, address = $3, phone = $4 WHERE id = $5 RETURNING created_at"
+	err = tx.QueryRow(query, o.Code, o.Name, o.Address, o.Phone, o.ID).Scan(&o.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	if err := recordAudit(tx, audit, models.AuditUpdate, models.AuditEntityOutlet, o.ID, &before, o); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetProducts mengambil seluruh katalog dengan stok, harga khusus dan stok dalam perjalanan ke outlet.
+func (repo *OutletRepository) GetProducts(outletID int) ([]models.OutletProduct, error) {
+	query := `SELECT p.id, p.name, p.price, op.price, COALESCE(op.price, p.price), COALESCE(op.stock, 0),
+				COALESCE((SELECT SUM(l.quantity_shipped)
+					FROM stock_transfer_lines l
+					JOIN stock_transfers t ON l.transfer_id = t.id
+					WHERE l.product_id = p.id AND t.to_outlet_id = $1 AND t.status = 'shipped'), 0)
+			FROM products p
+			LEFT JOIN outlet_products op ON op.product_id = p.id AND op.outlet_id = $1
+			WHERE p.deleted_at IS NULL
+			ORDER BY p.name`
+
+	rows, err := repo.db.Query(query, outletID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	products := make([]models.OutletProduct, 0)
+	for rows.Next() {
+		var p models.OutletProduct
+		err := rows.Scan(&p.ProductID, &p.ProductName, &p.BasePrice, &p.PriceOverride, &p.Price, &p.Stock, &p.InTransit)
+		if err != nil {
+			return nil, err
+		}
+		products = append(products, p)
+	}
+	return products, rows.Err()
+}
+
+// SetProduct mengubah stok dan/atau harga khusus produk di outlet. Stock nil mempertahankan stok,
+// PriceOverride nil menghapus harga khusus sehingga outlet kembali memakai harga katalog.
+// Perubahan stok dicatat di buku stok sebagai penyesuaian.
+func (repo *OutletRepository) SetProduct(outletID int, productID int, update models.OutletProductUpdate, audit models.AuditMeta) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := outletProductSnapshot(tx, outletID, productID)
+	if err != nil {
+		return err
+	}
+
+	if update.Stock != nil {
+		err = setOutletStock(tx, outletID, productID, *update.Stock, "penyesuaian outlet")
+	}
+	if err == nil {
+		_, err = tx.Exec(`INSERT INTO outlet_products (outlet_id, product_id, stock, price) VALUES ($1, $2, 0, $3)
+				ON CONFLICT (outlet_id, product_id) DO UPDATE SET price = EXCLUDED.price`, outletID, productID, update.PriceOverride)
+	}
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
+		return apperror.NotFound("outlet or product not found")
+	}
+	if err != nil {
+		return err
+	}
+
+	after, err := outletProductSnapshot(tx, outletID, productID)
+	if err != nil {
+		return err
+	}
+	if err := recordAudit(tx, audit, models.AuditUpdate, models.AuditEntityOutletProduct, productID, before, after); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// outletProductSnapshot membaca stok dan harga khusus produk di outlet untuk audit log.
+// Baris yang belum ada dianggap stok 0 tanpa harga khusus.
+func outletProductSnapshot(tx *sql.Tx, outletID int, productID int) (map[string]interface{}, error) {
+	stock := 0.0
+	var price *int
+	err := tx.QueryRow("SELECT stock, price FROM outlet_products WHERE outlet_id = $1 AND product_id = $2", outletID, productID).Scan(&stock, &price)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"outlet_id":      outletID,
+		"product_id":     productID,
+		"stock":          stock,
+		"price_override": price,
+	}, nil
+}
@@ -0,0 +1,193 @@
+package repositories
+
+import (
+	"database/sql"
+	"kasir-api/apperror"
+	"kasir-api/models"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// ErrPriceNotScheduled dikembalikan saat membatalkan harga yang tidak ada atau sudah berlaku
+var ErrPriceNotScheduled = apperror.NotFound("scheduled price not found or already applied")
+
+// catalogPriceAt adalah harga katalog yang berlaku pada waktu $3 menurut riwayat harga.
+// Bernilai NULL jika produk belum punya riwayat, sehingga pemanggil jatuh ke products.price.
+const catalogPriceAt = `(SELECT pp.price FROM product_prices pp
+					WHERE pp.product_id = p.id AND pp.effective_from <= $3
+					ORDER BY pp.effective_from DESC, pp.id DESC LIMIT 1)`
+
+type PriceRepository struct {
+	db *sql.DB
+}
+
+func NewPriceRepository(db *sql.DB) *PriceRepository {
+	return &PriceRepository{db: db}
+}
+
+// GetByProduct mengambil riwayat dan jadwal harga produk, yang berlaku paling akhir lebih dulu.
+func (repo *PriceRepository) GetByProduct(productID int) ([]models.ProductPrice, error) {
+	var exists bool
+	err := repo.db.QueryRow("SELECT EXISTS (SELECT 1 FROM products WHERE id = $1)", productID).Scan(&exists)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, apperror.NotFound("product not found")
+	}
+
+	rows, err := repo.db.Query(`SELECT id, product_id, price, effective_from, applied_at, note, created_by, created_at
+			FROM product_prices WHERE product_id = $1
+			ORDER BY effective_from DESC, id DESC`, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	prices := make([]models.ProductPrice, 0)
+	for rows.Next() {
+		var p models.ProductPrice
+		err := rows.Scan(&p.ID, &p.ProductID, &p.Price, &p.EffectiveFrom, &p.AppliedAt, &p.Note, &p.CreatedBy, &p.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		prices = append(prices, p)
+	}
+	return prices, rows.Err()
+}
+
+// Schedule menyimpan harga yang baru berlaku pada p.EffectiveFrom.
+func (repo *PriceRepository) Schedule(p *models.ProductPrice, audit models.AuditMeta) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRow(`INSERT INTO product_prices (product_id, price, effective_from, note, created_by)
+			SELECT id, $2, $3, $4, $5 FROM products WHERE id = $1 AND deleted_at IS NULL
+			RETURNING id, created_at`, p.ProductID, p.Price, p.EffectiveFrom, p.Note, audit.Actor).Scan(&p.ID, &p.CreatedAt)
+	if err == sql.ErrNoRows {
+		return apperror.NotFound("product not found")
+	}
+	if err != nil {
+		return err
+	}
+	p.CreatedBy = audit.Actor
+
+	if err := recordAudit(tx, audit, models.AuditCreate, models.AuditEntityProductPrice, p.ID, nil, p); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Cancel menghapus harga terjadwal yang belum berlaku. Riwayat harga yang sudah berlaku tidak bisa dihapus.
+func (repo *PriceRepository) Cancel(productID int, priceID int, audit models.AuditMeta) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var p models.ProductPrice
+	err = tx.QueryRow(`DELETE FROM product_prices WHERE id = $1 AND product_id = $2 AND applied_at IS NULL
+			RETURNING id, product_id, price, effective_from, applied_at, note, created_by, created_at`, priceID, productID).
+		Scan(&p.ID, &p.ProductID, &p.Price, &p.EffectiveFrom, &p.AppliedAt, &p.Note, &p.CreatedBy, &p.CreatedAt)
+	if err == sql.ErrNoRows {
+		return ErrPriceNotScheduled
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := recordAudit(tx, audit, models.AuditDelete, models.AuditEntityProductPrice, p.ID, &p, nil); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ApplyDue memberlakukan harga terjadwal yang sudah jatuh tempo pada now dan mengembalikan
+// jumlah produk yang harganya berubah. Harga produk menjadi harga dengan effective_from paling
+// akhir, sehingga perubahan langsung yang lebih baru tidak tertimpa jadwal yang terlambat diproses.
+func (repo *PriceRepository) ApplyDue(now time.Time, audit models.AuditMeta) (int, error) {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// SKIP LOCKED supaya beberapa instance server tidak memproses jadwal yang sama
+	rows, err := tx.Query(`SELECT id, product_id FROM product_prices
+			WHERE applied_at IS NULL AND effective_from <= $1
+			ORDER BY product_id, id
+			FOR UPDATE SKIP LOCKED`, now)
+	if err != nil {
+		return 0, err
+	}
+
+	ids := make([]int64, 0)
+	productIDs := make([]int, 0)
+	for rows.Next() {
+		var id int64
+		var productID int
+		if err := rows.Scan(&id, &productID); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		ids = append(ids, id)
+		if len(productIDs) == 0 || productIDs[len(productIDs)-1] != productID {
+			productIDs = append(productIDs, productID)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	_, err = tx.Exec("UPDATE product_prices SET applied_at = NOW() WHERE id = ANY($1)", pq.Array(ids))
+	if err != nil {
+		return 0, err
+	}
+
+	changed := 0
+	for _, productID := range productIDs {
+		var oldPrice, newPrice int
+		err := tx.QueryRow("SELECT price FROM products WHERE id = $1 FOR UPDATE", productID).Scan(&oldPrice)
+		if err != nil {
+			return 0, err
+		}
+		err = tx.QueryRow(`SELECT price FROM product_prices WHERE product_id = $1 AND effective_from <= $2
+				ORDER BY effective_from DESC, id DESC LIMIT 1`, productID, now).Scan(&newPrice)
+		if err != nil {
+			return 0, err
+		}
+		if oldPrice == newPrice {
+			continue
+		}
+
+		_, err = tx.Exec("UPDATE products SET price = $1 WHERE id = $2", newPrice, productID)
+		if err != nil {
+			return 0, err
+		}
+
+		before := map[string]interface{}{"id": productID, "price": oldPrice}
+		after := map[string]interface{}{"id": productID, "price": newPrice}
+		if err := recordAudit(tx, audit, models.AuditUpdate, models.AuditEntityProduct, productID, before, after); err != nil {
+			return 0, err
+		}
+		changed++
+	}
+
+	return changed, tx.Commit()
+}
+
+// recordPriceChange mencatat perubahan harga katalog yang langsung berlaku.
+func recordPriceChange(tx *sql.Tx, productID int, price interface{}, note string, audit models.AuditMeta) error {
+	_, err := tx.Exec(`INSERT INTO product_prices (product_id, price, effective_from, applied_at, note, created_by)
+			VALUES ($1, $2, NOW(), NOW(), $3, $4)`, productID, price, note, audit.Actor)
+	return err
+}
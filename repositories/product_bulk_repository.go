@@ -0,0 +1,68 @@
+package repositories
+
+import (
+	"fmt"
+	"kasir-api/models"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// Bulk menerapkan apply ke setiap produk yang dipilih target dalam satu transaksi, dengan
+// riwayat harga, buku stok dan audit log seperti perubahan satu per satu. Jika preview, semua
+// perubahan dibatalkan setelah dihitung. Mengembalikan jumlah produk yang terpilih.
+func (repo *ProductRepository) Bulk(outletID int, target models.BulkTarget, note string, preview bool, audit models.AuditMeta, apply func(*models.Product) error) (int, error) {
+	conditions := []string{"p.deleted_at IS NULL"}
+	args := make([]interface{}, 0)
+	if len(target.IDs) > 0 {
+		args = append(args, pq.Array(target.IDs))
+		conditions = append(conditions, fmt.Sprintf("p.id = ANY($%d)", len(args)))
+	}
+	if target.CategoryID != nil {
+		if *target.CategoryID == 0 {
+			conditions = append(conditions, "p.category_id IS NULL")
+		} else {
+			args = append(args, *target.CategoryID)
+			conditions = append(conditions, fmt.Sprintf("p.category_id = $%d", len(args)))
+		}
+	}
+	if target.Name != "" {
+		args = append(args, "%"+target.Name+"%")
+		conditions = append(conditions, fmt.Sprintf("p.name ILIKE $%d", len(args)))
+	}
+
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query("SELECT p.id FROM products p WHERE "+strings.Join(conditions, " AND ")+" ORDER BY p.id", args...)
+	if err != nil {
+		return 0, err
+	}
+	ids := make([]int, 0)
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for _, id := range ids {
+		if _, err := updateProduct(tx, outletID, id, "*", note, audit, apply); err != nil {
+			return 0, err
+		}
+	}
+
+	if preview {
+		return len(ids), nil
+	}
+	return len(ids), tx.Commit()
+}
@@ -0,0 +1,109 @@
+package repositories
+
+import (
+	"database/sql"
+	"kasir-api/models"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// ExistingSKUs mengembalikan id, satuan dasar dan jenis produk (yang belum dihapus) untuk setiap SKU yang sudah ada.
+func (repo *ProductRepository) ExistingSKUs(skus []string) (map[string]models.Product, error) {
+	rows, err := repo.db.Query("SELECT sku, id, unit, type FROM products WHERE sku = ANY($1) AND deleted_at IS NULL", pq.Array(skus))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	existing := make(map[string]models.Product)
+	for rows.Next() {
+		var p models.Product
+		if err := rows.Scan(&p.SKU, &p.ID, &p.Unit, &p.Type); err != nil {
+			return nil, err
+		}
+		existing[p.SKU] = p
+	}
+	return existing, rows.Err()
+}
+
+// Import membuat atau memperbarui produk berdasarkan SKU dalam satu transaksi: jika satu baris
+// gagal, seluruh baris di rows batal. Kategori dicari berdasarkan nama (tanpa membedakan huruf
+// besar kecil) dan dibuat jika belum ada. Hasil dikembalikan sesuai urutan rows.
+func (repo *ProductRepository) Import(outletID int, rows []models.ProductImportRow, audit models.AuditMeta) ([]models.ProductImportResult, error) {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	categories := make(map[string]int)
+	results := make([]models.ProductImportResult, 0, len(rows))
+	for _, row := range rows {
+		categoryID := 0
+		if row.Category != "" {
+			categoryID, err = resolveCategory(tx, audit, categories, row.Category)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		result := models.ProductImportResult{Row: row.Row, SKU: row.SKU}
+		var id int
+		err := tx.QueryRow("SELECT id FROM products WHERE sku = $1 AND deleted_at IS NULL", row.SKU).Scan(&id)
+		if err == sql.ErrNoRows {
+			product := models.Product{SKU: row.SKU, Name: row.Name, Price: row.Price, CategoryID: categoryID}
+			if row.Stock != nil {
+				product.Stock = *row.Stock
+			}
+			if err := createProduct(tx, outletID, &product, "impor produk", audit); err != nil {
+				return nil, err
+			}
+			result.Action = models.ImportCreate
+			result.ProductID = product.ID
+		} else if err != nil {
+			return nil, err
+		} else {
+			_, err := updateProduct(tx, outletID, id, "*", "impor produk", audit, func(p *models.Product) error {
+				p.Name = row.Name
+				p.Price = row.Price
+				if row.Stock != nil {
+					p.Stock = *row.Stock
+				}
+				if categoryID != 0 {
+					p.CategoryID = categoryID
+				}
+				return nil
+			})
+			if err != nil {
+				return nil, err
+			}
+			result.Action = models.ImportUpdate
+			result.ProductID = id
+		}
+		results = append(results, result)
+	}
+
+	return results, tx.Commit()
+}
+
+// resolveCategory mencari kategori berdasarkan nama atau membuatnya, dengan cache per impor.
+func resolveCategory(tx *sql.Tx, audit models.AuditMeta, cache map[string]int, name string) (int, error) {
+	key := strings.ToLower(name)
+	if id, ok := cache[key]; ok {
+		return id, nil
+	}
+
+	var id int
+	err := tx.QueryRow("SELECT id FROM categories WHERE LOWER(name) = $1 ORDER BY id LIMIT 1", key).Scan(&id)
+	if err == sql.ErrNoRows {
+		category := models.Categories{Name: name}
+		err = insertCategory(tx, audit, &category)
+		id = category.ID
+	}
+	if err != nil {
+		return 0, err
+	}
+	cache[key] = id
+	return id, nil
+}
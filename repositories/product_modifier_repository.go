@@ -0,0 +1,198 @@
+package repositories
+
+import (
+	"database/sql"
+	"kasir-api/apperror"
+	"kasir-api/models"
+
+	"github.com/lib/pq"
+)
+
+// loadModifierGroups mengisi kelompok modifier semua produk dengan satu query, sesuai urutan simpan.
+func loadModifierGroups(q querier, products []models.Product) error {
+	if len(products) == 0 {
+		return nil
+	}
+	ids := make([]int64, len(products))
+	index := make(map[int]int, len(products))
+	for i, p := range products {
+		ids[i] = int64(p.ID)
+		index[p.ID] = i
+	}
+
+	rows, err := q.Query(`SELECT g.product_id, g.id, g.name, g.min_select, g.max_select, m.id, m.name, m.price
+			FROM modifier_groups g
+			JOIN modifiers m ON m.group_id = g.id
+			WHERE g.product_id = ANY($1)
+			ORDER BY g.product_id, g.position, g.id, m.position, m.id`, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var productID int
+		var g models.ModifierGroup
+		var m models.Modifier
+		if err := rows.Scan(&productID, &g.ID, &g.Name, &g.Min, &g.Max, &m.ID, &m.Name, &m.Price); err != nil {
+			return err
+		}
+		p := &products[index[productID]]
+		if n := len(p.ModifierGroups); n == 0 || p.ModifierGroups[n-1].ID != g.ID {
+			p.ModifierGroups = append(p.ModifierGroups, g)
+		}
+		last := &p.ModifierGroups[len(p.ModifierGroups)-1]
+		last.Modifiers = append(last.Modifiers, m)
+	}
+	return rows.Err()
+}
+
+// saveModifierGroups menyimpan kelompok modifier produk sesuai urutan groups. Kelompok dan modifier
+// yang membawa id diperbarui sehingga id-nya tetap (keranjang menyimpan id modifier), yang tanpa id
+// ditambahkan dan id barunya diisi ke groups, sedangkan yang tidak dikirim lagi dihapus.
+func saveModifierGroups(tx *sql.Tx, productID int, groups []models.ModifierGroup) error {
+	keepGroups := make([]int64, 0, len(groups))
+	keepModifiers := make([]int64, 0)
+	for i := range groups {
+		g := &groups[i]
+		if g.ID > 0 {
+			result, err := tx.Exec("UPDATE modifier_groups SET name = $1, min_select = $2, max_select = $3, position = $4 WHERE id = $5 AND product_id = $6",
+				g.Name, g.Min, g.Max, i, g.ID, productID)
+			if err := expectUpdated(result, err, "modifier group", g.ID); err != nil {
+				return err
+			}
+		} else {
+			err := tx.QueryRow("INSERT INTO modifier_groups (product_id, name, min_select, max_select, position) VALUES ($1, $2, $3, $4, $5) RETURNING id",
+				productID, g.Name, g.Min, g.Max, i).Scan(&g.ID)
+			if err != nil {
+				return err
+			}
+		}
+		keepGroups = append(keepGroups, int64(g.ID))
+
+		for j := range g.Modifiers {
+			m := &g.Modifiers[j]
+			if m.ID > 0 {
+				// Modifier boleh pindah kelompok selama masih milik produk yang sama
+				result, err := tx.Exec(`UPDATE modifiers SET group_id = $1, name = $2, price = $3, position = $4
+						WHERE id = $5 AND group_id IN (SELECT id FROM modifier_groups WHERE product_id = $6)`,
+					g.ID, m.Name, m.Price, j, m.ID, productID)
+				if err := expectUpdated(result, err, "modifier", m.ID); err != nil {
+					return err
+				}
+			} else {
+				err := tx.QueryRow("INSERT INTO modifiers (group_id, name, price, position) VALUES ($1, $2, $3, $4) RETURNING id",
+					g.ID, m.Name, m.Price, j).Scan(&m.ID)
+				if err != nil {
+					return err
+				}
+			}
+			keepModifiers = append(keepModifiers, int64(m.ID))
+		}
+	}
+
+	_, err := tx.Exec("DELETE FROM modifiers WHERE group_id IN (SELECT id FROM modifier_groups WHERE product_id = $1) AND NOT (id = ANY($2))",
+		productID, pq.Array(keepModifiers))
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("DELETE FROM modifier_groups WHERE product_id = $1 AND NOT (id = ANY($2))", productID, pq.Array(keepGroups))
+	return err
+}
+
+// expectUpdated mengubah UPDATE yang tidak mengenai baris mana pun menjadi BadRequest: id yang dikirim
+// bukan milik produk ini.
+func expectUpdated(result sql.Result, err error, entity string, id int) error {
+	if err != nil {
+		return err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return apperror.BadRequest("%s id %d does not belong to this product", entity, id)
+	}
+	return nil
+}
+
+// resolveModifiers mencocokkan id modifier yang dipilih dengan kelompok modifier produk dan memeriksa
+// batas pilihan setiap kelompok. Hasilnya (tidak pernah nil) terurut sesuai urutan kelompok dan
+// modifier di produk.
+func resolveModifiers(q querier, productID int, selected []int) ([]models.SelectedModifier, error) {
+	chosen := make(map[int]bool, len(selected))
+	for _, id := range selected {
+		if chosen[id] {
+			return nil, apperror.BadRequest("product id %d: modifier id %d is selected more than once", productID, id)
+		}
+		chosen[id] = true
+	}
+
+	products := []models.Product{{ID: productID}}
+	if err := loadModifierGroups(q, products); err != nil {
+		return nil, err
+	}
+
+	result := make([]models.SelectedModifier, 0, len(selected))
+	for _, g := range products[0].ModifierGroups {
+		count := 0
+		for _, m := range g.Modifiers {
+			if chosen[m.ID] {
+				result = append(result, models.SelectedModifier{ModifierID: m.ID, Group: g.Name, Name: m.Name, Price: m.Price})
+				delete(chosen, m.ID)
+				count++
+			}
+		}
+		if count < g.Min {
+			return nil, apperror.BadRequest("product id %d: choose at least %d from %s", productID, g.Min, g.Name)
+		}
+		if count > g.Max {
+			return nil, apperror.BadRequest("product id %d: choose at most %d from %s", productID, g.Max, g.Name)
+		}
+	}
+	for _, id := range selected {
+		if chosen[id] {
+			return nil, apperror.BadRequest("modifier id %d is not available for product id %d", id, productID)
+		}
+	}
+	return result, nil
+}
+
+// modifiersByID mengambil modifier berdasarkan id tanpa memeriksa batas pilihan, untuk menampilkan
+// item keranjang. Modifier yang sudah dihapus tidak ada di hasil.
+func modifiersByID(q querier, ids []int64) (map[int]models.SelectedModifier, error) {
+	modifiers := make(map[int]models.SelectedModifier)
+	if len(ids) == 0 {
+		return modifiers, nil
+	}
+
+	rows, err := q.Query(`SELECT m.id, g.name, m.name, m.price
+			FROM modifiers m
+			JOIN modifier_groups g ON m.group_id = g.id
+			WHERE m.id = ANY($1)`, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var m models.SelectedModifier
+		if err := rows.Scan(&m.ModifierID, &m.Group, &m.Name, &m.Price); err != nil {
+			return nil, err
+		}
+		modifiers[m.ModifierID] = m
+	}
+	return modifiers, rows.Err()
+}
+
+// recordModifiers menyimpan modifier yang dipilih pada satu detail transaksi beserta harganya.
+func recordModifiers(tx *sql.Tx, detail models.TransactionDetail) error {
+	for i, m := range detail.Modifiers {
+		_, err := tx.Exec("INSERT INTO transaction_detail_modifiers (transaction_detail_id, modifier_id, group_name, name, price, position) VALUES ($1, $2, $3, $4, $5, $6)",
+			detail.ID, m.ModifierID, m.Group, m.Name, m.Price, i)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -2,10 +2,20 @@ package repositories
 
 import (
 	"database/sql"
-	"errors"
+	"kasir-api/apperror"
+	"kasir-api/images"
 	"kasir-api/models"
+	"math"
+
+	"github.com/lib/pq"
 )
 
+// ErrProductNotDeleted dikembalikan saat memulihkan produk yang tidak dihapus
+var ErrProductNotDeleted = apperror.Conflict("product is not deleted")
+
+// ErrVersionConflict dikembalikan jika ETag yang dikirim klien (If-Match) sudah tidak berlaku
+var ErrVersionConflict = apperror.New(apperror.CodePreconditionFailed, "product has been modified, reload and try again")
+
 type ProductRepository struct {
 	db *sql.DB
 }
@@ -14,13 +24,16 @@ func NewProductRepository(db *sql.DB) *ProductRepository {
 	return &ProductRepository{db: db}
 }
 
-func (r *ProductRepository) GetAll(name string) ([]models.Product, error) {
+// outletProductJoin menggabungkan katalog dengan stok dan harga khusus outlet ($1 adalah id outlet)
+const outletProductJoin = " LEFT JOIN outlet_products op ON op.product_id = p.id AND op.outlet_id = $1"
+
+func (r *ProductRepository) GetAll(outletID int, name string) ([]models.Product, error) {
 	// Implementation to fetch all products from the database
-	args := []interface{}{}
+	args := []interface{}{outletID}
 
-	query := "SELECT id, name, price, stock FROM products"
+	query := "SELECT p.id, COALESCE(p.sku, ''), COALESCE(p.image_key, ''), p.unit, p.type, p.name, COALESCE(op.price, p.price), COALESCE(op.stock, 0) FROM products p" + outletProductJoin + " WHERE p.deleted_at IS NULL"
 	if name != "" {
-		query += " WHERE name ILIKE $1"
+		query += " AND p.name ILIKE $2"
 		args = append(args, "%"+name+"%")
 	}
 
@@ -33,22 +46,23 @@ func (r *ProductRepository) GetAll(name string) ([]models.Product, error) {
 	products := make([]models.Product, 0)
 	for rows.Next() {
 		var p models.Product
-		err := rows.Scan(&p.ID, &p.Name, &p.Price, &p.Stock)
+		err := rows.Scan(&p.ID, &p.SKU, &p.ImageKey, &p.Unit, &p.Type, &p.Name, &p.Price, &p.Stock)
 		if err != nil {
 			return nil, err
 		}
+		p.Image = images.ProductImage(p.ImageKey)
 		products = append(products, p)
 	}
-	return products, nil
+	return products, loadProductRelations(r.db, outletID, products)
 }
 
-func (repo *ProductRepository) GetAllDetails(name string) ([]models.Product, error) {
-	args := []interface{}{}
-	query := `SELECT p.id, p.name, p.price, p.stock, c.name as category_name 
+func (repo *ProductRepository) GetAllDetails(outletID int, name string) ([]models.Product, error) {
+	args := []interface{}{outletID}
+	query := `SELECT p.id, COALESCE(p.sku, ''), COALESCE(p.image_key, ''), p.unit, p.type, p.name, COALESCE(op.price, p.price), COALESCE(op.stock, 0), c.name as category_name 
 				FROM products p 
-				LEFT JOIN categories c ON p.category_id = c.id`
+				LEFT JOIN categories c ON p.category_id = c.id` + outletProductJoin + " WHERE p.deleted_at IS NULL"
 	if name != "" {
-		query += " WHERE p.name ILIKE $1"
+		query += " AND p.name ILIKE $2"
 		args = append(args, "%"+name+"%")
 	}
 	rows, err := repo.db.Query(query, args...)
@@ -61,95 +75,643 @@ func (repo *ProductRepository) GetAllDetails(name string) ([]models.Product, err
 
 	for rows.Next() {
 		var p models.Product
-		err := rows.Scan(&p.ID, &p.Name, &p.Price, &p.Stock, &p.CategoryName)
+		err := rows.Scan(&p.ID, &p.SKU, &p.ImageKey, &p.Unit, &p.Type, &p.Name, &p.Price, &p.Stock, &p.CategoryName)
 		if err != nil {
 			return nil, err
 		}
+		p.Image = images.ProductImage(p.ImageKey)
 		products = append(products, p)
 	}
 
-	return products, nil
+	return products, loadProductRelations(repo.db, outletID, products)
+}
+
+// Create menambah produk ke katalog bersama. Stok awal dicatat di outlet pembuat,
+// outlet lain mulai dengan stok 0.
+func (repo *ProductRepository) Create(outletID int, product *models.Product, audit models.AuditMeta) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := createProduct(tx, outletID, product, "produk baru", audit); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
-func (repo *ProductRepository) Create(product *models.Product) error {
-	query := "INSERT INTO products ( name, price, stock, category_id) VALUES ($1, $2, $3, $4) RETURNING id"
-	err := repo.db.QueryRow(query, product.Name, product.Price, product.Stock, product.CategoryID).Scan(&product.ID)
-	return err
+// createProduct menambah produk di dalam tx. note dicatat di riwayat harga dan buku stok.
+func createProduct(tx *sql.Tx, outletID int, product *models.Product, note string, audit models.AuditMeta) error {
+	if product.Unit == "" {
+		product.Unit = models.UnitPcs
+	}
+	if product.Type == "" {
+		product.Type = models.ProductStandard
+	}
+	query := "INSERT INTO products ( name, price, category_id, sku, unit, type) VALUES ($1, $2, NULLIF($3, 0), NULLIF($4, ''), $5, $6) RETURNING id"
+	err := tx.QueryRow(query, product.Name, product.Price, product.CategoryID, product.SKU, product.Unit, product.Type).Scan(&product.ID)
+	if err != nil {
+		return uniqueConflict(err, "idx_products_sku", "sku %s is already used by another product", product.SKU)
+	}
+
+	if err := saveProductUnits(tx, product.ID, product.Units); err != nil {
+		return err
+	}
+	if err := saveProductComponents(tx, product.ID, product.Components); err != nil {
+		return err
+	}
+	if err := saveModifierGroups(tx, product.ID, product.ModifierGroups); err != nil {
+		return err
+	}
+
+	// Paket dan resep tidak punya stok sendiri
+	if !product.IsComposite() {
+		err = setOutletStock(tx, outletID, product.ID, product.Stock, note)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = recordPriceChange(tx, product.ID, product.Price, note, audit)
+	if err != nil {
+		return err
+	}
+
+	after, err := productSnapshot(tx, outletID, product.ID)
+	if err != nil {
+		return err
+	}
+	if err := recordAudit(tx, audit, models.AuditCreate, models.AuditEntityProduct, product.ID, nil, after); err != nil {
+		return err
+	}
+	product.Version = after.Version
+	product.StockVersion = after.StockVersion
+	return nil
 }
 
-func (repo *ProductRepository) GetByID(id int) (*models.Product, error) {
-	query := "SELECT id, name, price, stock FROM products WHERE id = $1"
+func (repo *ProductRepository) GetByID(outletID int, id int) (*models.Product, error) {
+	query := "SELECT p.id, COALESCE(p.sku, ''), COALESCE(p.image_key, ''), p.unit, p.type, p.name, COALESCE(op.price, p.price), COALESCE(op.stock, 0), p.version, COALESCE(op.sync_version, 0) FROM products p" + outletProductJoin + " WHERE p.id = $2 AND p.deleted_at IS NULL"
 
 	var p models.Product
-	err := repo.db.QueryRow(query, id).Scan(&p.ID, &p.Name, &p.Price, &p.Stock)
+	err := repo.db.QueryRow(query, outletID, id).Scan(&p.ID, &p.SKU, &p.ImageKey, &p.Unit, &p.Type, &p.Name, &p.Price, &p.Stock, &p.Version, &p.StockVersion)
 
 	if err == sql.ErrNoRows {
-		return nil, errors.New("product not found")
+		return nil, apperror.NotFound("product not found")
 	}
 
 	if err != nil {
 		return nil, err
 	}
 
+	p.Image = images.ProductImage(p.ImageKey)
+	if err := loadRelationsFor(repo.db, outletID, &p); err != nil {
+		return nil, err
+	}
 	return &p, nil
 }
 
-func (repo *ProductRepository) GetDetailsByID(id int) (*models.Product, error) {
-	query := `SELECT p.id, p.name, p.price, p.stock, c.name as category_name 
+func (repo *ProductRepository) GetDetailsByID(outletID int, id int) (*models.Product, error) {
+	query := `SELECT p.id, COALESCE(p.sku, ''), COALESCE(p.image_key, ''), p.unit, p.type, p.name, COALESCE(op.price, p.price), COALESCE(op.stock, 0), c.name as category_name, p.version, COALESCE(op.sync_version, 0)
 				FROM products p 
-				LEFT JOIN categories c ON p.category_id = c.id WHERE p.id = $1`
+				LEFT JOIN categories c ON p.category_id = c.id` + outletProductJoin + " WHERE p.id = $2 AND p.deleted_at IS NULL"
 
 	var p models.Product
-	err := repo.db.QueryRow(query, id).Scan(&p.ID, &p.Name, &p.Price, &p.Stock, &p.CategoryName)
+	err := repo.db.QueryRow(query, outletID, id).Scan(&p.ID, &p.SKU, &p.ImageKey, &p.Unit, &p.Type, &p.Name, &p.Price, &p.Stock, &p.CategoryName, &p.Version, &p.StockVersion)
 	if err == sql.ErrNoRows {
-		return nil, errors.New("product not found")
+		return nil, apperror.NotFound("product not found")
 	}
 
 	if err != nil {
 		return nil, err
 	}
 
+	p.Image = images.ProductImage(p.ImageKey)
+	if err := loadRelationsFor(repo.db, outletID, &p); err != nil {
+		return nil, err
+	}
 	return &p, nil
 }
 
-func (repo *ProductRepository) Update(product *models.Product) error {
-	query := "UPDATE products SET category_id = $1, name = $2, price = $3, stock = $4 WHERE id = $5"
-	result, err := repo.db.Exec(query, product.CategoryID, product.Name, product.Price, product.Stock, product.ID)
+// Patch mengubah produk dengan fungsi apply yang menerima data produk saat ini (harga katalog,
+// bukan harga khusus outlet). apply dipanggil di dalam transaksi setelah baris produk dikunci,
+// sehingga perubahan selalu diterapkan pada versi terbaru.
+func (repo *ProductRepository) Patch(outletID int, id int, ifMatch string, audit models.AuditMeta, apply func(*models.Product) error) (*models.Product, error) {
+	return repo.update(outletID, id, ifMatch, audit, apply)
+}
+
+func (repo *ProductRepository) update(outletID int, id int, ifMatch string, audit models.AuditMeta, apply func(*models.Product) error) (*models.Product, error) {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
 
+	after, err := updateProduct(tx, outletID, id, ifMatch, "ubah produk", audit, apply)
+	if err != nil {
+		return nil, err
+	}
+
+	return after, tx.Commit()
+}
+
+// updateProduct menerapkan apply pada produk di dalam tx. note dicatat di riwayat harga dan buku stok.
+func updateProduct(tx *sql.Tx, outletID int, id int, ifMatch string, note string, audit models.AuditMeta, apply func(*models.Product) error) (*models.Product, error) {
+	before, err := productSnapshot(tx, outletID, id)
+	if err != nil {
+		return nil, err
+	}
+	if before.DeletedAt != nil {
+		return nil, apperror.NotFound("product not found")
+	}
+	// Transfer hanya mengunci outlet_products, jadi stok di snapshot bisa sudah berubah. Barisnya
+	// dikunci setelah baris produk (urutan yang sama dengan checkout) dan stoknya dibaca ulang.
+	// Stok paket dan resep dihitung dari komponen dan tidak pernah ditulis di sini.
+	if !before.IsComposite() {
+		before.Stock, before.StockVersion, err = lockOutletStock(tx, outletID, id)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !models.ETagMatches(ifMatch, before.ETag()) {
+		return nil, ErrVersionConflict
+	}
+
+	// Harga katalog lama dibandingkan tanpa harga khusus outlet
+	var oldPrice float64
+	err = tx.QueryRow("SELECT price FROM products WHERE id = $1", id).Scan(&oldPrice)
+	if err != nil {
+		return nil, err
+	}
+
+	product := *before
+	product.Price = oldPrice
+	if err := apply(&product); err != nil {
+		return nil, err
+	}
+
+	query := "UPDATE products SET category_id = NULLIF($1, 0), name = $2, price = $3, sku = NULLIF($4, ''), unit = $5, type = $6 WHERE id = $7"
+	_, err = tx.Exec(query, product.CategoryID, product.Name, product.Price, product.SKU, product.Unit, product.Type, id)
+	if err != nil {
+		return nil, uniqueConflict(err, "idx_products_sku", "sku %s is already used by another product", product.SKU)
+	}
+
+	if err := saveProductUnits(tx, id, product.Units); err != nil {
+		return nil, err
+	}
+	if err := saveProductComponents(tx, id, product.Components); err != nil {
+		return nil, err
+	}
+	if err := saveModifierGroups(tx, id, product.ModifierGroups); err != nil {
+		return nil, err
+	}
+
+	if product.Price != oldPrice {
+		err = recordPriceChange(tx, id, product.Price, note, audit)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Stok hanya ditulis jika apply mengubahnya, agar perubahan harga atau kategori tidak ikut
+	// mencatat penyesuaian stok
+	if !product.IsComposite() && product.Stock != before.Stock {
+		err = setOutletStock(tx, outletID, id, product.Stock, note)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	after, err := productSnapshot(tx, outletID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := recordAudit(tx, audit, models.AuditUpdate, models.AuditEntityProduct, id, before, after); err != nil {
+		return nil, err
+	}
+
+	return after, nil
+}
+
+// Delete menghapus produk secara soft delete: produk hilang dari katalog dan checkout, tetapi
+// transaksi dan laporan lama tetap bisa membacanya. Data produk terakhir disimpan di audit log.
+// ifMatch diperiksa seperti pada Update.
+func (repo *ProductRepository) Delete(outletID int, id int, ifMatch string, audit models.AuditMeta) error {
+	tx, err := repo.db.Begin()
 	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
 
-	rows, err := result.RowsAffected()
+	before, err := productSnapshot(tx, outletID, id)
 	if err != nil {
 		return err
 	}
+	if before.DeletedAt != nil {
+		return apperror.NotFound("product not found")
+	}
+	if !models.ETagMatches(ifMatch, before.ETag()) {
+		return ErrVersionConflict
+	}
 
-	if rows == 0 {
-		return errors.New("product not found")
+	_, err = tx.Exec("UPDATE products SET deleted_at = NOW() WHERE id = $1", id)
+	if err != nil {
+		return err
 	}
 
-	return nil
+	if err := recordAudit(tx, audit, models.AuditDelete, models.AuditEntityProduct, id, before, nil); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
-func (repo *ProductRepository) Delete(id int) error {
-	query := "DELETE FROM products WHERE id = $1"
-	result, err := repo.db.Exec(query, id)
+// Restore memulihkan produk yang sudah dihapus beserta stok dan harga outlet yang masih tersimpan.
+func (repo *ProductRepository) Restore(outletID int, id int, audit models.AuditMeta) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
 
+	before, err := productSnapshot(tx, outletID, id)
 	if err != nil {
 		return err
 	}
+	if before.DeletedAt == nil {
+		return ErrProductNotDeleted
+	}
+
+	_, err = tx.Exec("UPDATE products SET deleted_at = NULL WHERE id = $1", id)
+	if err != nil {
+		return uniqueConflict(err, "idx_products_sku", "sku %s is already used by another product", before.SKU)
+	}
 
-	rows, err := result.RowsAffected()
+	after, err := productSnapshot(tx, outletID, id)
 	if err != nil {
 		return err
 	}
+	if err := recordAudit(tx, audit, models.AuditUpdate, models.AuditEntityProduct, id, before, after); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// SetImage mengganti key foto produk (key kosong berarti foto dihapus) dan mengembalikan key lama,
+// supaya file lama bisa dibuang dari storage setelah perubahan tersimpan.
+func (repo *ProductRepository) SetImage(outletID int, id int, key string, audit models.AuditMeta) (string, *models.Product, error) {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return "", nil, err
+	}
+	defer tx.Rollback()
+
+	before, err := productSnapshot(tx, outletID, id)
+	if err != nil {
+		return "", nil, err
+	}
+	if before.DeletedAt != nil {
+		return "", nil, apperror.NotFound("product not found")
+	}
 
-	if rows == 0 {
-		return errors.New("product not found")
+	_, err = tx.Exec("UPDATE products SET image_key = NULLIF($1, '') WHERE id = $2", key, id)
+	if err != nil {
+		return "", nil, err
 	}
 
-	return err
+	after, err := productSnapshot(tx, outletID, id)
+	if err != nil {
+		return "", nil, err
+	}
+	if err := recordAudit(tx, audit, models.AuditUpdate, models.AuditEntityProduct, id, before, after); err != nil {
+		return "", nil, err
+	}
+
+	return before.ImageKey, after, tx.Commit()
+}
+
+// GetDeleted mengambil produk yang sudah dihapus, terbaru lebih dulu.
+func (repo *ProductRepository) GetDeleted(outletID int) ([]models.Product, error) {
+	query := `SELECT p.id, COALESCE(p.sku, ''), COALESCE(p.image_key, ''), p.unit, p.type, p.name, COALESCE(op.price, p.price), COALESCE(op.stock, 0), COALESCE(p.category_id, 0), c.name, p.deleted_at
+				FROM products p
+				LEFT JOIN categories c ON p.category_id = c.id` + outletProductJoin + `
+				WHERE p.deleted_at IS NOT NULL
+				ORDER BY p.deleted_at DESC`
+	rows, err := repo.db.Query(query, outletID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	products := make([]models.Product, 0)
+	for rows.Next() {
+		var p models.Product
+		err := rows.Scan(&p.ID, &p.SKU, &p.ImageKey, &p.Unit, &p.Type, &p.Name, &p.Price, &p.Stock, &p.CategoryID, &p.CategoryName, &p.DeletedAt)
+		if err != nil {
+			return nil, err
+		}
+		p.Image = images.ProductImage(p.ImageKey)
+		products = append(products, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return products, loadProductRelations(repo.db, outletID, products)
+}
+
+// productSnapshot mengunci dan membaca produk (dengan stok dan harga di outlet) untuk audit log,
+// termasuk produk yang sudah dihapus.
+func productSnapshot(tx *sql.Tx, outletID int, id int) (*models.Product, error) {
+	query := `SELECT p.id, COALESCE(p.sku, ''), COALESCE(p.image_key, ''), p.unit, p.type, p.name, COALESCE(op.price, p.price), COALESCE(op.stock, 0), COALESCE(p.category_id, 0), p.deleted_at,
+				p.version, COALESCE(op.sync_version, 0)
+			FROM products p` + outletProductJoin + " WHERE p.id = $2 FOR UPDATE OF p"
+
+	var p models.Product
+	err := tx.QueryRow(query, outletID, id).Scan(&p.ID, &p.SKU, &p.ImageKey, &p.Unit, &p.Type, &p.Name, &p.Price, &p.Stock, &p.CategoryID, &p.DeletedAt, &p.Version, &p.StockVersion)
+	if err == sql.ErrNoRows {
+		return nil, apperror.NotFound("product not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	p.Image = images.ProductImage(p.ImageKey)
+	if err := loadRelationsFor(tx, outletID, &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// loadProductUnits mengisi satuan jual tambahan semua produk dengan satu query.
+func loadProductUnits(q querier, products []models.Product) error {
+	if len(products) == 0 {
+		return nil
+	}
+	ids := make([]int64, len(products))
+	index := make(map[int]int, len(products))
+	for i, p := range products {
+		ids[i] = int64(p.ID)
+		index[p.ID] = i
+	}
+
+	rows, err := q.Query("SELECT product_id, unit, factor, price FROM product_units WHERE product_id = ANY($1) ORDER BY product_id, factor", pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var productID int
+		var unit models.ProductUnit
+		var price sql.NullInt64
+		if err := rows.Scan(&productID, &unit.Unit, &unit.Factor, &price); err != nil {
+			return err
+		}
+		if price.Valid {
+			value := int(price.Int64)
+			unit.Price = &value
+		}
+		i := index[productID]
+		products[i].Units = append(products[i].Units, unit)
+	}
+	return rows.Err()
+}
+
+// loadProductComponents mengisi komponen paket dan resep, lalu menghitung stok produk tersebut di
+// outlet dari stok komponennya.
+func loadProductComponents(q querier, outletID int, products []models.Product) error {
+	ids := make([]int64, 0)
+	index := make(map[int]int)
+	for i, p := range products {
+		if p.IsComposite() {
+			ids = append(ids, int64(p.ID))
+			index[p.ID] = i
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	rows, err := q.Query(`SELECT pc.product_id, pc.component_id, c.name, c.unit, pc.quantity, COALESCE(op.stock, 0)
+			FROM product_components pc
+			JOIN products c ON c.id = pc.component_id
+			LEFT JOIN outlet_products op ON op.product_id = c.id AND op.outlet_id = $2
+			WHERE pc.product_id = ANY($1)
+			ORDER BY pc.product_id, c.name`, pq.Array(ids), outletID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	stocks := make(map[int][]componentStock)
+	for rows.Next() {
+		var productID int
+		var c models.ProductComponent
+		var stock float64
+		if err := rows.Scan(&productID, &c.ProductID, &c.ProductName, &c.Unit, &c.Quantity, &stock); err != nil {
+			return err
+		}
+		i := index[productID]
+		products[i].Components = append(products[i].Components, c)
+		stocks[productID] = append(stocks[productID], componentStock{quantity: c.Quantity, stock: stock})
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for id, i := range index {
+		products[i].Stock = availableFromComponents(products[i].Unit, stocks[id])
+	}
+	return nil
+}
+
+// componentStock adalah kebutuhan satu komponen per satuan produk beserta stok komponen tersebut.
+type componentStock struct {
+	quantity float64
+	stock    float64
+}
+
+// availableFromComponents menghitung berapa satuan paket atau resep yang bisa dijual dari stok
+// komponennya, dibulatkan ke bawah untuk satuan utuh.
+func availableFromComponents(unit string, components []componentStock) float64 {
+	if len(components) == 0 {
+		return 0
+	}
+	available := math.Inf(1)
+	for _, c := range components {
+		available = math.Min(available, c.stock/c.quantity)
+	}
+	if available <= 0 {
+		return 0
+	}
+	if !models.Units[unit].AllowDecimal {
+		return math.Floor(available + 1e-9)
+	}
+	scale := math.Pow10(models.QuantityDecimals)
+	return math.Floor(available*scale+1e-6) / scale
+}
+
+// loadProductRelations mengisi satuan jual tambahan, komponen paket dan resep, serta kelompok modifier.
+func loadProductRelations(q querier, outletID int, products []models.Product) error {
+	if err := loadProductUnits(q, products); err != nil {
+		return err
+	}
+	if err := loadProductComponents(q, outletID, products); err != nil {
+		return err
+	}
+	return loadModifierGroups(q, products)
+}
+
+func loadRelationsFor(q querier, outletID int, p *models.Product) error {
+	products := []models.Product{*p}
+	if err := loadProductRelations(q, outletID, products); err != nil {
+		return err
+	}
+	*p = products[0]
+	return nil
+}
+
+// saveProductComponents mengganti seluruh komponen paket atau resep.
+func saveProductComponents(tx *sql.Tx, productID int, components []models.ProductComponent) error {
+	if _, err := tx.Exec("DELETE FROM product_components WHERE product_id = $1", productID); err != nil {
+		return err
+	}
+	for _, c := range components {
+		_, err := tx.Exec("INSERT INTO product_components (product_id, component_id, quantity) VALUES ($1, $2, $3)", productID, c.ProductID, c.Quantity)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// saveProductUnits mengganti seluruh satuan jual tambahan produk.
+func saveProductUnits(tx *sql.Tx, productID int, units []models.ProductUnit) error {
+	if _, err := tx.Exec("DELETE FROM product_units WHERE product_id = $1", productID); err != nil {
+		return err
+	}
+	for _, u := range units {
+		_, err := tx.Exec("INSERT INTO product_units (product_id, unit, factor, price) VALUES ($1, $2, $3, $4)", productID, u.Unit, u.Factor, u.Price)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// saleUnit adalah satuan jual produk yang sudah dicocokkan: Factor mengubah kuantitas ke satuan
+// dasar dan Price adalah harga per satuan jual.
+type saleUnit struct {
+	Unit   string
+	Factor float64
+	Price  int
+}
+
+type rowQuerier interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// resolveSaleUnit mencari satuan jual unit milik produk lalu memeriksa kuantitasnya. unit kosong atau
+// sama dengan satuan dasar memakai faktor 1 dan basePrice (harga outlet per satuan dasar).
+func resolveSaleUnit(q rowQuerier, productID int, baseUnit string, basePrice int, unit string, quantity float64) (*saleUnit, error) {
+	var factor sql.NullFloat64
+	var price sql.NullInt64
+	if unit != "" && unit != baseUnit {
+		err := q.QueryRow("SELECT factor, price FROM product_units WHERE product_id = $1 AND unit = $2", productID, unit).Scan(&factor, &price)
+		if err == sql.ErrNoRows {
+			return nil, apperror.BadRequest("product id %d is not sold per %s", productID, unit)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	su := newSaleUnit(baseUnit, basePrice, unit, factor, price)
+	if !models.ValidQuantity(su.Unit, quantity) {
+		return nil, apperror.BadRequest("product id %d: quantity %s is not allowed for unit %s", productID, models.FormatQuantity(quantity), su.Unit)
+	}
+	return su, nil
+}
+
+// newSaleUnit menyusun satuan jual dari baris product_units (factor dan price NULL berarti satuan
+// dasar). Tanpa harga khusus, harga satuan jual adalah basePrice dikali faktor.
+func newSaleUnit(baseUnit string, basePrice int, unit string, factor sql.NullFloat64, price sql.NullInt64) *saleUnit {
+	if !factor.Valid {
+		return &saleUnit{Unit: baseUnit, Factor: 1, Price: basePrice}
+	}
+	su := &saleUnit{Unit: unit, Factor: factor.Float64, Price: int(math.Round(float64(basePrice) * factor.Float64))}
+	if price.Valid {
+		su.Price = int(price.Int64)
+	}
+	return su
+}
+
+// BaseQuantity mengubah kuantitas satuan jual ke satuan dasar.
+func (su *saleUnit) BaseQuantity(quantity float64) float64 {
+	return models.RoundQuantity(quantity * su.Factor)
+}
+
+// Subtotal adalah harga satuan jual ditambah harga modifier per satuan (extra), dikali kuantitas dan
+// dibulatkan ke rupiah terdekat.
+func (su *saleUnit) Subtotal(quantity float64, extra int) int {
+	return int(math.Round(float64(su.Price+extra) * quantity))
+}
+
+// CategoryExists memeriksa apakah kategori dengan id tersebut ada.
+func (repo *ProductRepository) CategoryExists(id int) (bool, error) {
+	var exists bool
+	err := repo.db.QueryRow("SELECT EXISTS (SELECT 1 FROM categories WHERE id = $1)", id).Scan(&exists)
+	return exists, err
+}
+
+// GetComponents mengambil nama, satuan dan jenis produk aktif yang akan dipakai sebagai komponen.
+func (repo *ProductRepository) GetComponents(ids []int) (map[int]models.Product, error) {
+	rows, err := repo.db.Query("SELECT id, name, unit, type FROM products WHERE id = ANY($1) AND deleted_at IS NULL", pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	products := make(map[int]models.Product)
+	for rows.Next() {
+		var p models.Product
+		if err := rows.Scan(&p.ID, &p.Name, &p.Unit, &p.Type); err != nil {
+			return nil, err
+		}
+		products[p.ID] = p
+	}
+	return products, rows.Err()
+}
+
+// UsedAsComponent melaporkan apakah produk menjadi komponen paket atau resep lain.
+func (repo *ProductRepository) UsedAsComponent(id int) (bool, error) {
+	var used bool
+	err := repo.db.QueryRow("SELECT EXISTS (SELECT 1 FROM product_components WHERE component_id = $1)", id).Scan(&used)
+	return used, err
+}
+
+// CreateCategory menambahkan kategori baru dan mengisi ID-nya.
+func (repo *ProductRepository) CreateCategory(category *models.Categories, audit models.AuditMeta) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := insertCategory(tx, audit, category); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// insertCategory menambahkan kategori dan mencatatnya di audit log dalam transaksi yang sama.
+func insertCategory(tx *sql.Tx, audit models.AuditMeta, category *models.Categories) error {
+	err := tx.QueryRow("INSERT INTO categories (name) VALUES ($1) RETURNING id", category.Name).Scan(&category.ID)
+	if err != nil {
+		return err
+	}
+	return recordAudit(tx, audit, models.AuditCreate, models.AuditEntityCategory, category.ID, nil, category)
 }
 
 func (repo *ProductRepository) GetCategories() ([]models.Categories, error) {
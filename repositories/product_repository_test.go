@@ -0,0 +1,97 @@
+package repositories
+
+import (
+	"database/sql"
+	"kasir-api/apperror"
+	"net/http"
+	"testing"
+)
+
+func TestSaleUnit(t *testing.T) {
+	cases := []struct {
+		name         string
+		factor       sql.NullFloat64
+		price        sql.NullInt64
+		quantity     float64
+		modifiers    int
+		wantUnit     string
+		wantPrice    int
+		wantBase     float64
+		wantSubtotal int
+	}{
+		{"base unit", sql.NullFloat64{}, sql.NullInt64{}, 3, 0, "pcs", 5000, 3, 15000},
+		{"box without own price", sql.NullFloat64{Float64: 12, Valid: true}, sql.NullInt64{}, 2, 0, "box", 60000, 24, 120000},
+		{"box with own price", sql.NullFloat64{Float64: 12, Valid: true}, sql.NullInt64{Int64: 55000, Valid: true}, 2, 0, "box", 55000, 24, 110000},
+		{"fractional factor", sql.NullFloat64{Float64: 0.25, Valid: true}, sql.NullInt64{}, 3, 0, "box", 1250, 0.75, 3750},
+		{"modifier price per unit", sql.NullFloat64{}, sql.NullInt64{}, 2, 3000, "pcs", 5000, 2, 16000},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			su := newSaleUnit("pcs", 5000, "box", tc.factor, tc.price)
+			if su.Unit != tc.wantUnit || su.Price != tc.wantPrice {
+				t.Fatalf("got %s at %d, want %s at %d", su.Unit, su.Price, tc.wantUnit, tc.wantPrice)
+			}
+			if got := su.BaseQuantity(tc.quantity); got != tc.wantBase {
+				t.Errorf("BaseQuantity(%v) = %v, want %v", tc.quantity, got, tc.wantBase)
+			}
+			if got := su.Subtotal(tc.quantity, tc.modifiers); got != tc.wantSubtotal {
+				t.Errorf("Subtotal(%v, %d) = %d, want %d", tc.quantity, tc.modifiers, got, tc.wantSubtotal)
+			}
+		})
+	}
+}
+
+func TestSaleUnitSubtotalRounding(t *testing.T) {
+	// 0,333 kg x Rp 25.000 = Rp 8.325; 0,125 kg x Rp 12.345 = Rp 1.543,125 dibulatkan ke rupiah terdekat
+	su := newSaleUnit("kg", 25000, "", sql.NullFloat64{}, sql.NullInt64{})
+	if got := su.Subtotal(0.333, 0); got != 8325 {
+		t.Errorf("Subtotal = %d, want 8325", got)
+	}
+	su = newSaleUnit("kg", 12345, "", sql.NullFloat64{}, sql.NullInt64{})
+	if got := su.Subtotal(0.125, 0); got != 1543 {
+		t.Errorf("Subtotal = %d, want 1543", got)
+	}
+}
+
+// Satuan dasar tidak perlu membaca product_units, jadi querier tidak dipakai
+func TestResolveBaseSaleUnit(t *testing.T) {
+	cases := []struct {
+		name     string
+		baseUnit string
+		unit     string
+		quantity float64
+		wantErr  bool
+	}{
+		{"whole pcs", "pcs", "", 2, false},
+		{"fraction of pcs", "pcs", "", 1.5, true},
+		{"base unit named", "kg", "kg", 0.25, false},
+		{"fraction of kg", "kg", "", 0.125, false},
+		{"more than three decimals", "kg", "", 0.1255, true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			su, err := resolveSaleUnit(nil, 1, tc.baseUnit, 1000, tc.unit, tc.quantity)
+			if tc.wantErr {
+				if apperror.CodeOf(err) != apperror.CodeBadRequest {
+					t.Fatalf("got %v, want bad request", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if su.Unit != tc.baseUnit || su.Factor != 1 {
+				t.Fatalf("got %s x %v, want %s x 1", su.Unit, su.Factor, tc.baseUnit)
+			}
+		})
+	}
+}
+
+// ETag lama pada If-Match dijawab 412, bukan 409 seperti konflik data lain
+func TestVersionConflictStatus(t *testing.T) {
+	if got := apperror.CodeOf(ErrVersionConflict).Status(); got != http.StatusPreconditionFailed {
+		t.Fatalf("status = %d, want %d", got, http.StatusPreconditionFailed)
+	}
+}
@@ -3,6 +3,7 @@ package repositories
 import (
 	"database/sql"
 	"kasir-api/models"
+	"time"
 )
 
 type ReportRepository struct {
@@ -13,24 +14,22 @@ func NewReportRepository(db *sql.DB) *ReportRepository {
 	return &ReportRepository{db: db}
 }
 
-func (r *ReportRepository) GetReport(start_date string, end_date string) ([]models.Report, error) {
+// GetReport mengambil ringkasan transaksi outlet dalam rentang [start, end).
+// Batas rentang sudah dihitung oleh service dalam zona waktu toko.
+func (r *ReportRepository) GetReport(outletID int, start time.Time, end time.Time) ([]models.Report, error) {
 	var report []models.Report
 	var scanReport models.Report
-	args := []interface{}{}
 
 	tx, err := r.db.Begin()
 	if err != nil {
 		return nil, err
 	}
 	defer tx.Rollback()
-	dateFilter := " WHERE DATE(t.created_at) = CURRENT_DATE"
-	if start_date != "" && end_date != "" {
-		dateFilter = " WHERE DATE(t.created_at) BETWEEN $1 AND $2"
-		args = append(args, start_date, end_date)
-	}
+	dateFilter := " WHERE t.created_at >= $1 AND t.created_at < $2 AND t.outlet_id = $3"
+	args := []interface{}{start, end, outletID}
 
 	summaryQuery := "SELECT COALESCE(SUM(total_amount), 0), COUNT(id) FROM transactions t" + dateFilter
-	err = r.db.QueryRow(summaryQuery, args...).Scan(&scanReport.TotalRevenue, &scanReport.TotalTransaksi)
+	err = tx.QueryRow(summaryQuery, args...).Scan(&scanReport.TotalRevenue, &scanReport.TotalTransaksi)
 
 	if err != nil {
 		return nil, err
@@ -38,14 +37,14 @@ func (r *ReportRepository) GetReport(start_date string, end_date string) ([]mode
 
 	topProductQuery := `SELECT 
 				p.name, 
-				SUM(td.quantity) as qty_terjual 
+				SUM(td.base_quantity) as qty_terjual 
 			FROM transaction_details td
 			JOIN products p ON td.product_id = p.id
 			JOIN transactions t ON td.transaction_id = t.id ` + dateFilter + ` GROUP BY p.name
 				ORDER BY qty_terjual DESC
 				LIMIT 1`
 
-	err = r.db.QueryRow(topProductQuery, args...).Scan(&scanReport.ProdukTerlaris.Nama, &scanReport.ProdukTerlaris.QtyTerjual)
+	err = tx.QueryRow(topProductQuery, args...).Scan(&scanReport.ProdukTerlaris.Nama, &scanReport.ProdukTerlaris.QtyTerjual)
 	if err == sql.ErrNoRows {
 		scanReport.ProdukTerlaris.Nama = "-"
 		scanReport.ProdukTerlaris.QtyTerjual = 0
@@ -62,3 +61,192 @@ func (r *ReportRepository) GetReport(start_date string, end_date string) ([]mode
 	return report, nil
 
 }
+
+// GetTimeSeries mengelompokkan transaksi per interval (hour, day, week, month).
+// Bucket dihitung dengan date_trunc pada waktu lokal zona timezone, sehingga nilai
+// bucket yang dikembalikan adalah jam dinding lokal toko tanpa informasi zona.
+// Bucket tanpa transaksi tidak dikembalikan; pengisian nol dilakukan di service.
+func (r *ReportRepository) GetTimeSeries(outletID int, start time.Time, end time.Time, interval string, timezone string) ([]models.TimeSeriesPoint, error) {
+	query := `SELECT
+				date_trunc($3, t.created_at AT TIME ZONE $4) AS bucket,
+				COALESCE(SUM(t.total_amount), 0),
+				COUNT(t.id),
+				COALESCE(SUM((SELECT SUM(td.base_quantity) FROM transaction_details td WHERE td.transaction_id = t.id)), 0)
+			FROM transactions t
+			WHERE t.created_at >= $1 AND t.created_at < $2 AND t.outlet_id = $5
+			GROUP BY bucket
+			ORDER BY bucket`
+
+	rows, err := r.db.Query(query, start, end, interval, timezone, outletID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	points := make([]models.TimeSeriesPoint, 0)
+	for rows.Next() {
+		var p models.TimeSeriesPoint
+		err := rows.Scan(&p.Bucket, &p.TotalRevenue, &p.TotalTransaksi, &p.QtyTerjual)
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, p)
+	}
+	return points, rows.Err()
+}
+
+// GetSummary mengambil total pendapatan dan jumlah transaksi outlet dalam rentang [start, end).
+func (r *ReportRepository) GetSummary(outletID int, start time.Time, end time.Time) (*models.PeriodSummary, error) {
+	var summary models.PeriodSummary
+	query := "SELECT COALESCE(SUM(total_amount), 0), COUNT(id) FROM transactions t WHERE t.created_at >= $1 AND t.created_at < $2 AND t.outlet_id = $3"
+	err := r.db.QueryRow(query, start, end, outletID).Scan(&summary.TotalRevenue, &summary.TotalTransaksi)
+	if err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
+
+// GetProductSales mengambil jumlah terjual per produk di outlet dalam rentang [start, end).
+func (r *ReportRepository) GetProductSales(outletID int, start time.Time, end time.Time) ([]models.ProductSales, error) {
+	query := `SELECT p.id, p.name, p.unit, SUM(td.base_quantity) as qty_terjual
+			FROM transaction_details td
+			JOIN products p ON td.product_id = p.id
+			JOIN transactions t ON td.transaction_id = t.id
+			WHERE t.created_at >= $1 AND t.created_at < $2 AND t.outlet_id = $3
+			GROUP BY p.id, p.name, p.unit
+			ORDER BY qty_terjual DESC`
+
+	rows, err := r.db.Query(query, start, end, outletID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sales := make([]models.ProductSales, 0)
+	for rows.Next() {
+		var s models.ProductSales
+		err := rows.Scan(&s.ProductID, &s.Nama, &s.Satuan, &s.QtyTerjual)
+		if err != nil {
+			return nil, err
+		}
+		sales = append(sales, s)
+	}
+	return sales, rows.Err()
+}
+
+// GetComponentUsage mengambil jumlah komponen yang terpakai oleh paket dan resep yang terjual di
+// outlet dalam rentang [start, end).
+func (r *ReportRepository) GetComponentUsage(outletID int, start time.Time, end time.Time) ([]models.ComponentUsage, error) {
+	query := `SELECT p.id, p.name, p.unit, SUM(tdc.quantity) as qty_terpakai
+			FROM transaction_detail_components tdc
+			JOIN transaction_details td ON tdc.transaction_detail_id = td.id
+			JOIN products p ON tdc.product_id = p.id
+			JOIN transactions t ON td.transaction_id = t.id
+			WHERE t.created_at >= $1 AND t.created_at < $2 AND t.outlet_id = $3
+			GROUP BY p.id, p.name, p.unit
+			ORDER BY qty_terpakai DESC`
+
+	rows, err := r.db.Query(query, start, end, outletID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	usage := make([]models.ComponentUsage, 0)
+	for rows.Next() {
+		var u models.ComponentUsage
+		err := rows.Scan(&u.ProductID, &u.Nama, &u.Satuan, &u.QtyTerpakai)
+		if err != nil {
+			return nil, err
+		}
+		usage = append(usage, u)
+	}
+	return usage, rows.Err()
+}
+
+// GetModifierSales menghitung berapa kali setiap modifier dipilih di outlet dalam rentang [start, end).
+// Modifier dikelompokkan berdasarkan nama kelompok dan nama yang tersimpan di transaksi, sehingga
+// modifier yang sudah dihapus tetap terhitung.
+func (r *ReportRepository) GetModifierSales(outletID int, start time.Time, end time.Time) ([]models.ModifierSales, error) {
+	query := `SELECT tdm.group_name, tdm.name, COUNT(*) as dipilih, SUM(td.quantity), COALESCE(SUM(ROUND(tdm.price * td.quantity)), 0)
+			FROM transaction_detail_modifiers tdm
+			JOIN transaction_details td ON tdm.transaction_detail_id = td.id
+			JOIN transactions t ON td.transaction_id = t.id
+			WHERE t.created_at >= $1 AND t.created_at < $2 AND t.outlet_id = $3
+			GROUP BY tdm.group_name, tdm.name
+			ORDER BY dipilih DESC, tdm.group_name, tdm.name`
+
+	rows, err := r.db.Query(query, start, end, outletID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sales := make([]models.ModifierSales, 0)
+	for rows.Next() {
+		var s models.ModifierSales
+		err := rows.Scan(&s.Grup, &s.Nama, &s.Dipilih, &s.QtyTerjual, &s.TotalTambahan)
+		if err != nil {
+			return nil, err
+		}
+		sales = append(sales, s)
+	}
+	return sales, rows.Err()
+}
+
+// GetConsolidated mengambil ringkasan per outlet (termasuk outlet tanpa transaksi) dan
+// produk terlaris gabungan semua outlet dalam rentang [start, end).
+func (r *ReportRepository) GetConsolidated(start time.Time, end time.Time, topLimit int) (*models.ConsolidatedReport, error) {
+	report := &models.ConsolidatedReport{
+		ProdukTerlaris: make([]models.ProductSales, 0),
+		Outlets:        make([]models.OutletReport, 0),
+	}
+
+	rows, err := r.db.Query(`SELECT o.id, o.name, COALESCE(SUM(t.total_amount), 0), COUNT(t.id)
+			FROM outlets o
+			LEFT JOIN transactions t ON t.outlet_id = o.id AND t.created_at >= $1 AND t.created_at < $2
+			GROUP BY o.id, o.name
+			ORDER BY o.id`, start, end)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var o models.OutletReport
+		err := rows.Scan(&o.OutletID, &o.OutletName, &o.TotalRevenue, &o.TotalTransaksi)
+		if err != nil {
+			return nil, err
+		}
+		report.TotalRevenue += o.TotalRevenue
+		report.TotalTransaksi += o.TotalTransaksi
+		report.Outlets = append(report.Outlets, o)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	rows, err = r.db.Query(`SELECT p.id, p.name, p.unit, SUM(td.base_quantity) as qty_terjual
+			FROM transaction_details td
+			JOIN products p ON td.product_id = p.id
+			JOIN transactions t ON td.transaction_id = t.id
+			WHERE t.created_at >= $1 AND t.created_at < $2
+			GROUP BY p.id, p.name, p.unit
+			ORDER BY qty_terjual DESC
+			LIMIT $3`, start, end, topLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var s models.ProductSales
+		err := rows.Scan(&s.ProductID, &s.Nama, &s.Satuan, &s.QtyTerjual)
+		if err != nil {
+			return nil, err
+		}
+		report.ProdukTerlaris = append(report.ProdukTerlaris, s)
+	}
+	return report, rows.Err()
+}
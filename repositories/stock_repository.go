@@ -0,0 +1,132 @@
+package repositories
+
+import (
+	"database/sql"
+	"kasir-api/apperror"
+	"kasir-api/models"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type StockRepository struct {
+	db *sql.DB
+}
+
+func NewStockRepository(db *sql.DB) *StockRepository {
+	return &StockRepository{db: db}
+}
+
+// GetMovements mengambil buku stok outlet dalam rentang [start, end), terbaru lebih dulu.
+// productID 0 berarti semua produk.
+func (repo *StockRepository) GetMovements(outletID int, productID int, start time.Time, end time.Time) ([]models.StockMovement, error) {
+	query := `SELECT m.id, m.outlet_id, m.product_id, p.name, m.quantity, m.type, m.transaction_id, m.transfer_id, m.note, m.created_at
+			FROM stock_movements m
+			JOIN products p ON m.product_id = p.id
+			WHERE m.outlet_id = $1 AND ($2 = 0 OR m.product_id = $2) AND m.created_at >= $3 AND m.created_at < $4
+			ORDER BY m.created_at DESC, m.id DESC`
+
+	rows, err := repo.db.Query(query, outletID, productID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	movements := make([]models.StockMovement, 0)
+	for rows.Next() {
+		var m models.StockMovement
+		err := rows.Scan(&m.ID, &m.OutletID, &m.ProductID, &m.ProductName, &m.Quantity, &m.Type, &m.TransactionID, &m.TransferID, &m.Note, &m.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		movements = append(movements, m)
+	}
+	return movements, rows.Err()
+}
+
+// recordStockMovement menulis satu baris buku stok tanpa mengubah stok.
+func recordStockMovement(tx *sql.Tx, m models.StockMovement) error {
+	_, err := tx.Exec(`INSERT INTO stock_movements (outlet_id, product_id, quantity, type, transaction_id, transfer_id, note)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)`, m.OutletID, m.ProductID, m.Quantity, m.Type, m.TransactionID, m.TransferID, m.Note)
+	return err
+}
+
+// changeOutletStock menambah (Quantity positif) atau mengurangi stok outlet lalu mencatatnya di buku stok.
+func changeOutletStock(tx *sql.Tx, m models.StockMovement) error {
+	_, err := tx.Exec(`INSERT INTO outlet_products (outlet_id, product_id, stock) VALUES ($1, $2, $3)
+			ON CONFLICT (outlet_id, product_id) DO UPDATE SET stock = outlet_products.stock + EXCLUDED.stock`,
+		m.OutletID, m.ProductID, m.Quantity)
+	if err != nil {
+		return err
+	}
+	return recordStockMovement(tx, m)
+}
+
+// checkStockQuantity memastikan produk punya stok sendiri (bukan paket atau resep) dan quantity cocok
+// dengan satuan dasarnya (bilangan bulat untuk pcs dan box).
+func checkStockQuantity(q rowQuerier, productID int, quantity float64) error {
+	var unit, productType string
+	err := q.QueryRow("SELECT unit, type FROM products WHERE id = $1", productID).Scan(&unit, &productType)
+	if err == sql.ErrNoRows {
+		return apperror.NotFound("product id %d not found", productID)
+	}
+	if err != nil {
+		return err
+	}
+	if productType != models.ProductStandard {
+		return apperror.BadRequest("product id %d is a %s, its stock follows its components", productID, productType)
+	}
+	if !models.ValidQuantity(unit, quantity) {
+		return apperror.BadRequest("product id %d: quantity %s is not allowed for unit %s", productID, models.FormatQuantity(quantity), unit)
+	}
+	return nil
+}
+
+// lockOutletStock mengunci baris stok produk di outlet dan mengembalikan stok serta versinya,
+// 0 jika produk belum punya stok di outlet tersebut.
+func lockOutletStock(tx *sql.Tx, outletID int, productID int) (float64, int64, error) {
+	var stock float64
+	var version int64
+	err := tx.QueryRow("SELECT stock, sync_version FROM outlet_products WHERE outlet_id = $1 AND product_id = $2 FOR UPDATE", outletID, productID).Scan(&stock, &version)
+	if err == sql.ErrNoRows {
+		return 0, 0, nil
+	}
+	return stock, version, err
+}
+
+// lockOutletStocks mengunci stok productIDs beserta komponen paket dan resepnya di outlet, urut
+// berdasarkan id produk. Checkout dan transfer memakai urutan yang sama agar tidak saling mengunci.
+func lockOutletStocks(tx *sql.Tx, outletID int, productIDs []int64) error {
+	_, err := tx.Exec(`SELECT 1 FROM outlet_products
+			WHERE outlet_id = $1
+				AND (product_id = ANY($2) OR product_id IN (SELECT component_id FROM product_components WHERE product_id = ANY($2)))
+			ORDER BY product_id
+			FOR UPDATE`, outletID, pq.Array(productIDs))
+	return err
+}
+
+// setOutletStock mengganti stok outlet menjadi stock dan mencatat selisihnya sebagai penyesuaian.
+func setOutletStock(tx *sql.Tx, outletID int, productID int, stock float64, note string) error {
+	if err := checkStockQuantity(tx, productID, stock); err != nil {
+		return err
+	}
+
+	current := 0.0
+	err := tx.QueryRow("SELECT stock FROM outlet_products WHERE outlet_id = $1 AND product_id = $2 FOR UPDATE", outletID, productID).Scan(&current)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	if stock == current {
+		_, err = tx.Exec("INSERT INTO outlet_products (outlet_id, product_id, stock) VALUES ($1, $2, $3) ON CONFLICT (outlet_id, product_id) DO NOTHING", outletID, productID, stock)
+		return err
+	}
+
+	return changeOutletStock(tx, models.StockMovement{
+		OutletID:  outletID,
+		ProductID: productID,
+		Quantity:  models.RoundQuantity(stock - current),
+		Type:      models.StockAdjustment,
+		Note:      note,
+	})
+}
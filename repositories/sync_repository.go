@@ -0,0 +1,129 @@
+package repositories
+
+import (
+	"database/sql"
+	"kasir-api/models"
+	"strconv"
+)
+
+type SyncRepository struct {
+	db *sql.DB
+}
+
+func NewSyncRepository(db *sql.DB) *SyncRepository {
+	return &SyncRepository{db: db}
+}
+
+// GetChanges mengambil produk, kategori dan data terhapus dengan sync_version lebih besar dari cursor,
+// maksimal limit baris secara total. Cursor berikutnya adalah sync_version terbesar yang dikirim.
+// Perubahan stok atau harga khusus outlet lain tidak ikut dikirim.
+func (repo *SyncRepository) GetChanges(outletID int, cursor int64, limit int) (*models.SyncChanges, error) {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Batas atas halaman ini: versi ke-limit setelah cursor (jika ada)
+	var upper int64
+	hasMore := true
+	err = tx.QueryRow(`SELECT v FROM (
+				SELECT sync_version AS v FROM products WHERE sync_version > $1
+				UNION ALL SELECT sync_version FROM categories WHERE sync_version > $1
+				UNION ALL SELECT sync_version FROM sync_tombstones WHERE sync_version > $1
+				UNION ALL SELECT sync_version FROM outlet_products WHERE sync_version > $1 AND outlet_id = $3
+			) versions ORDER BY v LIMIT 1 OFFSET $2`, cursor, limit, outletID).Scan(&upper)
+	if err == sql.ErrNoRows {
+		hasMore = false
+		err = tx.QueryRow(`SELECT GREATEST(
+				(SELECT COALESCE(MAX(sync_version), 0) FROM products),
+				(SELECT COALESCE(MAX(sync_version), 0) FROM categories),
+				(SELECT COALESCE(MAX(sync_version), 0) FROM sync_tombstones),
+				(SELECT COALESCE(MAX(sync_version), 0) FROM outlet_products WHERE outlet_id = $2),
+				$1)`, cursor, outletID).Scan(&upper)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if hasMore {
+		// Baris ke-(limit+1) ada, jadi halaman ini berakhir tepat sebelum versi tersebut
+		upper--
+	}
+
+	changes := &models.SyncChanges{
+		HasMore:    hasMore,
+		Products:   make([]models.Product, 0),
+		Categories: make([]models.Categories, 0),
+		Deleted:    make([]models.SyncTombstone, 0),
+	}
+
+	// Produk dikirim ulang jika data katalog atau stok/harga di outlet ini berubah.
+	// Produk yang dihapus dikirim sebagai tombstone, bukan di daftar ini.
+	rows, err := tx.Query(`SELECT p.id, p.name, p.unit, p.type, COALESCE(op.price, p.price), COALESCE(op.stock, 0), COALESCE(p.category_id, 0), c.name
+			FROM products p
+			LEFT JOIN categories c ON p.category_id = c.id
+			LEFT JOIN outlet_products op ON op.product_id = p.id AND op.outlet_id = $3
+			WHERE p.deleted_at IS NULL
+				AND GREATEST(p.sync_version, COALESCE(op.sync_version, 0)) > $1
+				AND GREATEST(p.sync_version, COALESCE(op.sync_version, 0)) <= $2
+			ORDER BY GREATEST(p.sync_version, COALESCE(op.sync_version, 0))`, cursor, upper, outletID)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var p models.Product
+		if err := rows.Scan(&p.ID, &p.Name, &p.Unit, &p.Type, &p.Price, &p.Stock, &p.CategoryID, &p.CategoryName); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		changes.Products = append(changes.Products, p)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if err := loadProductRelations(tx, outletID, changes.Products); err != nil {
+		return nil, err
+	}
+
+	rows, err = tx.Query("SELECT id, name FROM categories WHERE sync_version > $1 AND sync_version <= $2 ORDER BY sync_version", cursor, upper)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var c models.Categories
+		if err := rows.Scan(&c.ID, &c.Name); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		changes.Categories = append(changes.Categories, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = tx.Query("SELECT entity, entity_id, deleted_at FROM sync_tombstones WHERE sync_version > $1 AND sync_version <= $2 ORDER BY sync_version", cursor, upper)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var t models.SyncTombstone
+		if err := rows.Scan(&t.Entity, &t.ID, &t.DeletedAt); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		changes.Deleted = append(changes.Deleted, t)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	changes.Cursor = strconv.FormatInt(upper, 10)
+	return changes, nil
+}
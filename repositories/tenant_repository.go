@@ -0,0 +1,123 @@
+package repositories
+
+import (
+	"database/sql"
+	"kasir-api/apperror"
+	"kasir-api/models"
+
+	"github.com/lib/pq"
+)
+
+// ErrTenantNotFound dikembalikan jika tenant tidak terdaftar
+var ErrTenantNotFound = apperror.NotFound("tenant not found")
+
+// TenantRepository membaca tabel public.tenants. Hanya dipakai pada mode multi-tenant
+// dengan koneksi ke schema public, bukan koneksi milik tenant.
+type TenantRepository struct {
+	db *sql.DB
+}
+
+func NewTenantRepository(db *sql.DB) *TenantRepository {
+	return &TenantRepository{db: db}
+}
+
+const tenantQuery = "SELECT id, slug, name, schema_name, api_key_hash, active, created_at, store_address, store_phone, store_npwp, receipt_footer FROM public.tenants"
+
+func scanTenant(row interface{ Scan(...interface{}) error }) (*models.Tenant, error) {
+	var t models.Tenant
+	err := row.Scan(&t.ID, &t.Slug, &t.Name, &t.Schema, &t.APIKeyHash, &t.Active, &t.CreatedAt, &t.Address, &t.Phone, &t.NPWP, &t.ReceiptFooter)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (repo *TenantRepository) GetAll() ([]models.Tenant, error) {
+	rows, err := repo.db.Query(tenantQuery + " ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tenants := make([]models.Tenant, 0)
+	for rows.Next() {
+		t, err := scanTenant(rows)
+		if err != nil {
+			return nil, err
+		}
+		tenants = append(tenants, *t)
+	}
+	return tenants, rows.Err()
+}
+
+func (repo *TenantRepository) GetByID(id int) (*models.Tenant, error) {
+	t, err := scanTenant(repo.db.QueryRow(tenantQuery+" WHERE id = $1", id))
+	if err == sql.ErrNoRows {
+		return nil, ErrTenantNotFound
+	}
+	return t, err
+}
+
+func (repo *TenantRepository) GetBySlug(slug string) (*models.Tenant, error) {
+	t, err := scanTenant(repo.db.QueryRow(tenantQuery+" WHERE slug = $1", slug))
+	if err == sql.ErrNoRows {
+		return nil, ErrTenantNotFound
+	}
+	return t, err
+}
+
+func (repo *TenantRepository) GetByAPIKeyHash(hash string) (*models.Tenant, error) {
+	t, err := scanTenant(repo.db.QueryRow(tenantQuery+" WHERE api_key_hash = $1", hash))
+	if err == sql.ErrNoRows {
+		return nil, ErrTenantNotFound
+	}
+	return t, err
+}
+
+// CreateSchema membuat schema kosong untuk tenant baru. Nama schema di-quote agar aman.
+func (repo *TenantRepository) CreateSchema(schema string) error {
+	_, err := repo.db.Exec("CREATE SCHEMA " + pq.QuoteIdentifier(schema))
+	return err
+}
+
+// DropSchema menghapus schema tenant beserta isinya; dipakai untuk membatalkan provisioning yang gagal.
+func (repo *TenantRepository) DropSchema(schema string) error {
+	_, err := repo.db.Exec("DROP SCHEMA IF EXISTS " + pq.QuoteIdentifier(schema) + " CASCADE")
+	return err
+}
+
+func (repo *TenantRepository) Create(t *models.Tenant) error {
+	err := repo.db.QueryRow(`INSERT INTO public.tenants (slug, name, schema_name, api_key_hash, store_address, store_phone, store_npwp, receipt_footer)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id, active, created_at`,
+		t.Slug, t.Name, t.Schema, t.APIKeyHash, t.Address, t.Phone, t.NPWP, t.ReceiptFooter).Scan(&t.ID, &t.Active, &t.CreatedAt)
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+		return apperror.Conflict("tenant slug already exists")
+	}
+	return err
+}
+
+// Update mengubah nama, status aktif dan identitas struk tenant.
+func (repo *TenantRepository) Update(t *models.Tenant) error {
+	err := repo.db.QueryRow(`UPDATE public.tenants SET name = $1, active = $2, store_address = $3, store_phone = $4, store_npwp = $5, receipt_footer = $6
+			WHERE id = $7 RETURNING slug, schema_name, api_key_hash, created_at`,
+		t.Name, t.Active, t.Address, t.Phone, t.NPWP, t.ReceiptFooter, t.ID).Scan(&t.Slug, &t.Schema, &t.APIKeyHash, &t.CreatedAt)
+	if err == sql.ErrNoRows {
+		return ErrTenantNotFound
+	}
+	return err
+}
+
+func (repo *TenantRepository) SetAPIKeyHash(id int, apiKeyHash string) error {
+	result, err := repo.db.Exec("UPDATE public.tenants SET api_key_hash = $1 WHERE id = $2", apiKeyHash, id)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrTenantNotFound
+	}
+	return nil
+}
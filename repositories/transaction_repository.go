@@ -3,66 +3,368 @@ package repositories
 import (
 	"database/sql"
 	"fmt"
+	"kasir-api/apperror"
 	"kasir-api/models"
+	"time"
+
+	"github.com/lib/pq"
 )
 
+// ErrInsufficientStock menandai checkout yang jumlahnya melebihi stok produk
+var ErrInsufficientStock = apperror.InsufficientStock("insufficient stock")
+
 type TransactionRepository struct {
 	db *sql.DB
 }
 
+// transactionMeta berisi data tambahan transaksi: pelaku untuk audit log, client_uuid dan waktu
+// asli untuk transaksi hasil sinkronisasi offline, serta keranjang yang di-checkout (0 jika bukan
+// dari keranjang) agar reservasinya sendiri tidak mengurangi stok yang tersedia.
+type transactionMeta struct {
+	audit      models.AuditMeta
+	clientUUID string
+	createdAt  time.Time
+	cartID     int
+}
+
 func NewTransactionRepository(db *sql.DB) *TransactionRepository {
 	return &TransactionRepository{db: db}
 }
 
-func (r *TransactionRepository) CreateTransaction(items []models.CheckoutItem) (*models.Transaction, error) {
+func (r *TransactionRepository) CreateTransaction(outletID int, req models.CheckoutRequest, rules models.LoyaltyRules, audit models.AuditMeta) (*models.Transaction, error) {
 	tx, err := r.db.Begin()
 	if err != nil {
 		return nil, err
 	}
 	defer tx.Rollback()
 
+	transaction, err := createTransaction(tx, outletID, req, rules, transactionMeta{audit: audit})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return transaction, nil
+}
+
+// ApplySynced menyimpan transaksi offline secara idempoten. Jika client_uuid sudah pernah
+// disimpan di outlet yang sama, transaksi tidak dibuat ulang dan duplicate bernilai true.
+func (r *TransactionRepository) ApplySynced(outletID int, sync models.SyncTransaction, rules models.LoyaltyRules, audit models.AuditMeta) (*models.Transaction, bool, error) {
+	existing, err := r.syncedTransaction(outletID, sync.ClientUUID)
+	if err == nil {
+		return existing, true, nil
+	}
+	if err != sql.ErrNoRows {
+		return nil, false, err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, false, err
+	}
+	defer tx.Rollback()
+
+	transaction, err := createTransaction(tx, outletID, sync.CheckoutRequest, rules, transactionMeta{audit: audit, clientUUID: sync.ClientUUID, createdAt: sync.CreatedAt})
+	// Upload yang sama dari dua koneksi bersamaan: unique constraint client_uuid menolak yang kedua
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" && pqErr.Constraint == "transactions_client_uuid_key" {
+		tx.Rollback()
+		existing, err := r.syncedTransaction(outletID, sync.ClientUUID)
+		if err != nil {
+			return nil, false, err
+		}
+		return existing, true, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, false, err
+	}
+	return transaction, false, nil
+}
+
+// syncedTransaction mencari transaksi dengan clientUUID, atau sql.ErrNoRows jika belum ada. UUID milik
+// transaksi outlet lain ditolak agar terminal tidak bisa membaca transaksi di luar outletnya.
+func (r *TransactionRepository) syncedTransaction(outletID int, clientUUID string) (*models.Transaction, error) {
+	var id, existingOutletID int
+	err := r.db.QueryRow("SELECT id, outlet_id FROM transactions WHERE client_uuid = $1", clientUUID).Scan(&id, &existingOutletID)
+	if err != nil {
+		return nil, err
+	}
+	if existingOutletID != outletID {
+		return nil, apperror.Conflict("client_uuid %s is already used by a transaction of another outlet", clientUUID)
+	}
+	return &models.Transaction{ID: id}, nil
+}
+
+// lockCheckoutProducts mengunci produk yang dibeli beserta komponen paket dan resepnya sekaligus,
+// urut berdasarkan id, lalu stok outlet-nya dengan urutan yang sama. Tanpa ini checkout dengan urutan
+// item [A, B] dan [B, A], atau checkout yang bersamaan dengan perubahan massal atau transfer (yang
+// juga mengunci urut id), bisa saling mengunci (deadlock).
+func lockCheckoutProducts(tx *sql.Tx, outletID int, items []models.CheckoutItem) error {
+	ids := make([]int64, len(items))
+	for i, item := range items {
+		ids[i] = int64(item.ProductID)
+	}
+	_, err := tx.Exec(`SELECT id FROM products
+			WHERE id = ANY($1) OR id IN (SELECT component_id FROM product_components WHERE product_id = ANY($1))
+			ORDER BY id
+			FOR UPDATE`, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	return lockOutletStocks(tx, outletID, ids)
+}
+
+// consumeComponents mengurangi stok komponen paket atau resep productID untuk quantity satuan produk
+// dan mengembalikan jumlah tiap komponen yang terpakai (tidak pernah nil). Baris komponen sudah
+// dikunci oleh lockCheckoutProducts.
+func consumeComponents(tx *sql.Tx, outletID int, cartID int, productID int, quantity float64) ([]models.ProductComponent, error) {
+	rows, err := tx.Query(`SELECT pc.component_id, c.name, c.unit, pc.quantity
+			FROM product_components pc
+			JOIN products c ON c.id = pc.component_id
+			WHERE pc.product_id = $1
+			ORDER BY pc.component_id`, productID)
+	if err != nil {
+		return nil, err
+	}
+	components := make([]models.ProductComponent, 0)
+	for rows.Next() {
+		var c models.ProductComponent
+		if err := rows.Scan(&c.ProductID, &c.ProductName, &c.Unit, &c.Quantity); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		c.Quantity = models.RoundQuantity(c.Quantity * quantity)
+		components = append(components, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, c := range components {
+		taken, stock, err := takeStock(tx, outletID, cartID, c.ProductID, c.Quantity)
+		if err != nil {
+			return nil, err
+		}
+		if !taken {
+			return nil, fmt.Errorf("%w: product id %d needs %s %s of %s, %s %s left", ErrInsufficientStock, productID,
+				models.FormatQuantity(c.Quantity), c.Unit, c.ProductName, models.FormatQuantity(stock), c.Unit)
+		}
+	}
+	return components, nil
+}
+
+// takeStock mengurangi stok outlet sebanyak quantity hanya jika stok dikurangi reservasi keranjang
+// lain (selain cartID) masih cukup. Syarat stok diperiksa oleh UPDATE itu sendiri, yang menunggu
+// checkout lain pada baris yang sama selesai, sehingga dua checkout bersamaan tidak bisa menjual stok
+// yang sama. Jika stok tidak cukup, taken bernilai false dan stock berisi stok yang masih tersedia.
+func takeStock(tx *sql.Tx, outletID int, cartID int, productID int, quantity float64) (taken bool, stock float64, err error) {
+	result, err := tx.Exec(`UPDATE outlet_products op SET stock = op.stock - $1
+			WHERE op.outlet_id = $2 AND op.product_id = $3 AND `+availableStock("$4")+` >= $1`, quantity, outletID, productID, cartID)
+	if err != nil {
+		return false, 0, err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, 0, err
+	}
+	if n > 0 {
+		return true, 0, nil
+	}
+
+	err = tx.QueryRow("SELECT "+availableStock("$3")+" FROM outlet_products op WHERE op.outlet_id = $1 AND op.product_id = $2", outletID, productID, cartID).Scan(&stock)
+	if err != nil && err != sql.ErrNoRows {
+		return false, 0, err
+	}
+	return false, stock, nil
+}
+
+// availableStock adalah stok baris outlet_products op dikurangi reservasi keranjang selain cart.
+func availableStock(cart string) string {
+	return "op.stock - " + reservedStock("op.product_id", cart, "op.outlet_id")
+}
+
+// recordComponents menyimpan komponen yang terpakai oleh satu detail transaksi dan mencatatnya di buku stok.
+func recordComponents(tx *sql.Tx, outletID int, transactionID int, detail models.TransactionDetail) error {
+	for _, c := range detail.Components {
+		_, err := tx.Exec("INSERT INTO transaction_detail_components (transaction_detail_id, product_id, quantity) VALUES ($1, $2, $3)", detail.ID, c.ProductID, c.Quantity)
+		if err != nil {
+			return err
+		}
+
+		err = recordStockMovement(tx, models.StockMovement{
+			OutletID:      outletID,
+			ProductID:     c.ProductID,
+			Quantity:      -c.Quantity,
+			Type:          models.StockSale,
+			TransactionID: &transactionID,
+			Note:          "komponen " + detail.ProductName,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createTransaction menjalankan seluruh proses checkout di dalam tx milik pemanggil,
+// sehingga bisa digabung dengan perubahan lain (misalnya status keranjang) secara atomik.
+// Harga dan stok diambil dari outlet tempat transaksi terjadi.
+func createTransaction(tx *sql.Tx, outletID int, req models.CheckoutRequest, rules models.LoyaltyRules, meta transactionMeta) (*models.Transaction, error) {
+	customerID, err := resolveCustomer(tx, req)
+	if err != nil {
+		return nil, err
+	}
+
 	totalAmount := 0
+	now := time.Now()
+
+	// Harga serta masa berlaku voucher dan kartu hadiah mengikuti waktu penjualan; transaksi offline
+	// memakai waktu di terminal. now hanya dipakai untuk pencatatan seperti kedaluwarsa poin.
+	saleTime := now
+	if !meta.createdAt.IsZero() {
+		saleTime = meta.createdAt
+	}
+
+	if err := lockCheckoutProducts(tx, outletID, req.Items); err != nil {
+		return nil, err
+	}
 
 	details := make([]models.TransactionDetail, 0)
+	lines := make([]voucherLine, 0)
 
-	for _, item := range items {
-		var productName string
-		var price, stock int
-		err := tx.QueryRow("SELECT name, price, stock from products WHERE id=$1", item.ProductID).Scan(&productName, &price, &stock)
+	for _, item := range req.Items {
+		var productName, baseUnit, productType string
+		var price, categoryID int
+		err := tx.QueryRow(`SELECT p.name, p.unit, p.type, COALESCE(op.price, `+catalogPriceAt+`, p.price), COALESCE(p.category_id, 0)
+				FROM products p
+				LEFT JOIN outlet_products op ON op.product_id = p.id AND op.outlet_id = $2
+				WHERE p.id = $1 AND p.deleted_at IS NULL`, item.ProductID, outletID, saleTime).Scan(&productName, &baseUnit, &productType, &price, &categoryID)
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("product id %d not found", item.ProductID)
+			return nil, apperror.NotFound("product id %d not found", item.ProductID)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// Harga dan subtotal dalam satuan jual, stok dalam satuan dasar
+		unit, err := resolveSaleUnit(tx, item.ProductID, baseUnit, price, item.Unit, item.Quantity)
+		if err != nil {
+			return nil, err
 		}
+		modifiers, err := resolveModifiers(tx, item.ProductID, item.Modifiers)
 		if err != nil {
 			return nil, err
 		}
-		subtotal := item.Quantity * price
+		baseQuantity := unit.BaseQuantity(item.Quantity)
+		subtotal := unit.Subtotal(item.Quantity, models.ModifierPrice(modifiers))
 		totalAmount += subtotal
 
-		if stock == 0 {
-			return nil, fmt.Errorf("Product id %d sold out", item.ProductID)
+		detail := models.TransactionDetail{
+			ProductID:    item.ProductID,
+			ProductName:  productName,
+			Quantity:     item.Quantity,
+			Unit:         unit.Unit,
+			BaseQuantity: baseQuantity,
+			UnitPrice:    unit.Price,
+			Subtotal:     subtotal,
+			Modifiers:    modifiers,
+		}
+
+		// Paket dan resep mengurangi stok komponennya, bukan stok produk itu sendiri
+		if productType != models.ProductStandard {
+			detail.Components, err = consumeComponents(tx, outletID, meta.cartID, item.ProductID, baseQuantity)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			taken, stock, err := takeStock(tx, outletID, meta.cartID, item.ProductID, baseQuantity)
+			if err != nil {
+				return nil, err
+			}
+			if !taken && stock <= 0 {
+				return nil, fmt.Errorf("%w: Product id %d sold out", ErrInsufficientStock, item.ProductID)
+			}
+			if !taken {
+				return nil, fmt.Errorf("%w: product id %d has %s %s left, requested %s %s", ErrInsufficientStock, item.ProductID,
+					models.FormatQuantity(stock), baseUnit, models.FormatQuantity(baseQuantity), baseUnit)
+			}
+		}
+
+		details = append(details, detail)
+		lines = append(lines, voucherLine{productID: item.ProductID, categoryID: categoryID, subtotal: subtotal})
+	}
+
+	// Voucher dihitung dari subtotal sebelum potongan lain
+	voucherID, voucherDiscount := 0, 0
+	if req.VoucherCode != "" {
+		voucherID, voucherDiscount, err = applyVoucher(tx, req.VoucherCode, lines, totalAmount, saleTime)
+		if err != nil {
+			return nil, err
+		}
+		totalAmount -= voucherDiscount
+	}
+
+	// Penukaran poin sebagai potongan harga
+	pointsDiscount := 0
+	if req.RedeemPoints > 0 {
+		if customerID == nil {
+			return nil, apperror.BadRequest("redeem_points requires a customer")
+		}
+
+		if err := expirePoints(tx, *customerID, now); err != nil {
+			return nil, err
 		}
 
-		_, err = tx.Exec("UPDATE products SET stock = stock - $1 WHERE id = $2", item.Quantity, item.ProductID)
+		balance, err := pointsBalance(tx, *customerID)
 		if err != nil {
 			return nil, err
 		}
+		if req.RedeemPoints > balance {
+			return nil, apperror.Conflict("insufficient points: balance %d, requested %d", balance, req.RedeemPoints)
+		}
 
-		details = append(details, models.TransactionDetail{
-			ProductID:   item.ProductID,
-			ProductName: productName,
-			Quantity:    item.Quantity,
-			Subtotal:    subtotal,
-		})
+		pointsDiscount = req.RedeemPoints * rules.PointValue
+		if pointsDiscount > totalAmount {
+			return nil, apperror.BadRequest("redeemed points worth Rp%d exceed the transaction total Rp%d", pointsDiscount, totalAmount)
+		}
+		totalAmount -= pointsDiscount
+	}
+	discountAmount := voucherDiscount + pointsDiscount
+
+	// Kartu hadiah adalah alat bayar, tidak mengurangi total transaksi
+	giftCardID, giftCardAmount := 0, 0
+	if req.GiftCardCode != "" {
+		giftCardID, giftCardAmount, err = applyGiftCard(tx, req.GiftCardCode, totalAmount, saleTime)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	var transactionID int
-	err = tx.QueryRow("INSERT INTO transactions (total_amount) VALUES ($1) RETURNING id", totalAmount).Scan(&transactionID)
+	var createdAt time.Time
+	var clientUUID, clientCreatedAt interface{}
+	if meta.clientUUID != "" {
+		clientUUID = meta.clientUUID
+	}
+	if !meta.createdAt.IsZero() {
+		clientCreatedAt = meta.createdAt
+	}
+	err = tx.QueryRow(`INSERT INTO transactions (outlet_id, total_amount, discount_amount, gift_card_amount, customer_id, client_uuid, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, COALESCE($7, NOW())) RETURNING id, created_at`,
+		outletID, totalAmount, discountAmount, giftCardAmount, customerID, clientUUID, clientCreatedAt).Scan(&transactionID, &createdAt)
 
 	if err != nil {
 		return nil, err
 	}
 
-	stmt, err := tx.Prepare("INSERT INTO transaction_details (transaction_id, product_id, quantity, subtotal) VALUES ($1, $2, $3, $4)")
+	stmt, err := tx.Prepare("INSERT INTO transaction_details (transaction_id, product_id, quantity, unit, base_quantity, unit_price, subtotal) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id")
 
 	if err != nil {
 		return nil, err
@@ -71,19 +373,329 @@ func (r *TransactionRepository) CreateTransaction(items []models.CheckoutItem) (
 
 	for i, detail := range details {
 		details[i].TransactionID = transactionID
-		_, err := stmt.Exec(transactionID, detail.ProductID, detail.Quantity, detail.Subtotal)
+		err := stmt.QueryRow(transactionID, detail.ProductID, detail.Quantity, detail.Unit, detail.BaseQuantity, detail.UnitPrice, detail.Subtotal).Scan(&details[i].ID)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := recordModifiers(tx, details[i]); err != nil {
+			return nil, err
+		}
+
+		// Components tidak nil untuk paket dan resep: buku stok mencatat komponen yang terpakai
+		if detail.Components != nil {
+			if err := recordComponents(tx, outletID, transactionID, details[i]); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		err = recordStockMovement(tx, models.StockMovement{
+			OutletID:      outletID,
+			ProductID:     detail.ProductID,
+			Quantity:      -detail.BaseQuantity,
+			Type:          models.StockSale,
+			TransactionID: &transactionID,
+		})
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	if err := tx.Commit(); err != nil {
+	if voucherID != 0 {
+		_, err = tx.Exec("INSERT INTO redemptions (transaction_id, voucher_id, amount) VALUES ($1, $2, $3)", transactionID, voucherID, voucherDiscount)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if giftCardID != 0 {
+		_, err = tx.Exec("INSERT INTO redemptions (transaction_id, gift_card_id, amount) VALUES ($1, $2, $3)", transactionID, giftCardID, giftCardAmount)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	pointsEarned := 0
+	if customerID != nil {
+		if req.RedeemPoints > 0 {
+			_, err = tx.Exec("INSERT INTO loyalty_ledger (customer_id, transaction_id, type, points) VALUES ($1, $2, $3, $4)", *customerID, transactionID, models.LoyaltyRedeem, -req.RedeemPoints)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if rules.EarnAmount > 0 {
+			pointsEarned = totalAmount / rules.EarnAmount
+		}
+		if pointsEarned > 0 {
+			var expiresAt *time.Time
+			if rules.ExpiryDays > 0 {
+				expiry := createdAt.AddDate(0, 0, rules.ExpiryDays)
+				expiresAt = &expiry
+			}
+			_, err = tx.Exec("INSERT INTO loyalty_ledger (customer_id, transaction_id, type, points, expires_at) VALUES ($1, $2, $3, $4, $5)", *customerID, transactionID, models.LoyaltyEarn, pointsEarned, expiresAt)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	transaction := &models.Transaction{
+		ID:              transactionID,
+		OutletID:        outletID,
+		TotalAmount:     totalAmount,
+		DiscountAmount:  discountAmount,
+		VoucherCode:     req.VoucherCode,
+		VoucherDiscount: voucherDiscount,
+		GiftCardCode:    req.GiftCardCode,
+		GiftCardAmount:  giftCardAmount,
+		AmountDue:       totalAmount - giftCardAmount,
+		CustomerID:      customerID,
+		PointsEarned:    pointsEarned,
+		PointsRedeemed:  req.RedeemPoints,
+		CreatedAt:       createdAt,
+		Details:         details,
+	}
+
+	if err := recordAudit(tx, meta.audit, models.AuditCreate, models.AuditEntityTransaction, transactionID, nil, transaction); err != nil {
+		return nil, err
+	}
+	return transaction, nil
+}
+
+// resolveCustomer mencari pelanggan dari customer_id atau customer_phone dan menguncinya
+// selama checkout. Mengembalikan nil jika checkout tanpa pelanggan.
+func resolveCustomer(tx *sql.Tx, req models.CheckoutRequest) (*int, error) {
+	var customerID int
+	switch {
+	case req.CustomerID != nil:
+		customerID = *req.CustomerID
+	case req.CustomerPhone != "":
+		err := tx.QueryRow("SELECT id FROM customers WHERE phone = $1", req.CustomerPhone).Scan(&customerID)
+		if err == sql.ErrNoRows {
+			return nil, apperror.NotFound("customer with phone %s not found", req.CustomerPhone)
+		}
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, nil
+	}
+
+	if err := lockCustomer(tx, customerID); err != nil {
 		return nil, err
 	}
+	return &customerID, nil
+}
+
+// StreamTransactions membaca detail transaksi dalam rentang [start, end) baris per baris,
+// urut per transaksi, dan memanggil fn untuk setiap detail beserta modifier-nya tanpa menampung
+// hasil di memori.
+func (r *TransactionRepository) StreamTransactions(outletID int, start time.Time, end time.Time, fn func(models.Transaction, models.TransactionDetail) error) error {
+	query := `SELECT t.id, t.outlet_id, t.total_amount, t.discount_amount, t.gift_card_amount, t.customer_id, t.created_at,
+				td.id, td.product_id, COALESCE(p.name, ''), td.quantity, td.unit, td.base_quantity, td.unit_price, td.subtotal,
+				tdm.modifier_id, tdm.group_name, tdm.name, tdm.price
+			FROM transactions t
+			JOIN transaction_details td ON td.transaction_id = t.id
+			LEFT JOIN products p ON td.product_id = p.id
+			LEFT JOIN transaction_detail_modifiers tdm ON tdm.transaction_detail_id = td.id
+			WHERE t.outlet_id = $1 AND t.created_at >= $2 AND t.created_at < $3
+			ORDER BY t.id, td.id, tdm.position`
+
+	rows, err := r.db.Query(query, outletID, start, end)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
 
-	return &models.Transaction{
-		ID:          transactionID,
-		TotalAmount: totalAmount,
-		Details:     details,
-	}, nil
+	// Detail dengan beberapa modifier muncul di beberapa baris berurutan; fn dipanggil setelah
+	// semua modifier detail terkumpul
+	var pending *models.TransactionDetail
+	var pendingTx models.Transaction
+	for rows.Next() {
+		var t models.Transaction
+		var d models.TransactionDetail
+		var modifierID sql.NullInt64
+		var group, name sql.NullString
+		var price sql.NullInt64
+		err := rows.Scan(&t.ID, &t.OutletID, &t.TotalAmount, &t.DiscountAmount, &t.GiftCardAmount, &t.CustomerID, &t.CreatedAt,
+			&d.ID, &d.ProductID, &d.ProductName, &d.Quantity, &d.Unit, &d.BaseQuantity, &d.UnitPrice, &d.Subtotal,
+			&modifierID, &group, &name, &price)
+		if err != nil {
+			return err
+		}
+
+		if pending == nil || pending.ID != d.ID {
+			if pending != nil {
+				if err := fn(pendingTx, *pending); err != nil {
+					return err
+				}
+			}
+			t.AmountDue = t.TotalAmount - t.GiftCardAmount
+			d.TransactionID = t.ID
+			pending, pendingTx = &d, t
+		}
+		if group.Valid {
+			pending.Modifiers = append(pending.Modifiers, models.SelectedModifier{
+				ModifierID: int(modifierID.Int64), Group: group.String, Name: name.String, Price: int(price.Int64),
+			})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if pending != nil {
+		return fn(pendingTx, *pending)
+	}
+	return nil
+}
+
+func (r *TransactionRepository) GetByID(outletID int, id int) (*models.Transaction, error) {
+	var t models.Transaction
+	query := `SELECT t.id, t.outlet_id, t.total_amount, t.discount_amount, t.gift_card_amount, t.customer_id, t.created_at,
+				COALESCE(v.code, ''), COALESCE(rv.amount, 0), COALESCE(g.code, '')
+			FROM transactions t
+			LEFT JOIN redemptions rv ON rv.transaction_id = t.id AND rv.voucher_id IS NOT NULL
+			LEFT JOIN vouchers v ON rv.voucher_id = v.id
+			LEFT JOIN redemptions rg ON rg.transaction_id = t.id AND rg.gift_card_id IS NOT NULL
+			LEFT JOIN gift_cards g ON rg.gift_card_id = g.id
+			WHERE t.id = $1 AND t.outlet_id = $2`
+	err := r.db.QueryRow(query, id, outletID).Scan(&t.ID, &t.OutletID, &t.TotalAmount, &t.DiscountAmount, &t.GiftCardAmount, &t.CustomerID, &t.CreatedAt,
+		&t.VoucherCode, &t.VoucherDiscount, &t.GiftCardCode)
+	if err == sql.ErrNoRows {
+		return nil, apperror.NotFound("transaction not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	t.AmountDue = t.TotalAmount - t.GiftCardAmount
+
+	query = `SELECT td.id, td.product_id, COALESCE(p.name, ''), td.quantity, td.unit, td.base_quantity, td.unit_price, td.subtotal
+			FROM transaction_details td
+			LEFT JOIN products p ON td.product_id = p.id
+			WHERE td.transaction_id = $1
+			ORDER BY td.id`
+	rows, err := r.db.Query(query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	t.Details = make([]models.TransactionDetail, 0)
+	for rows.Next() {
+		d := models.TransactionDetail{TransactionID: id}
+		err := rows.Scan(&d.ID, &d.ProductID, &d.ProductName, &d.Quantity, &d.Unit, &d.BaseQuantity, &d.UnitPrice, &d.Subtotal)
+		if err != nil {
+			return nil, err
+		}
+		t.Details = append(t.Details, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	if err := r.loadDetailComponents(id, t.Details); err != nil {
+		return nil, err
+	}
+	if err := r.loadDetailModifiers(id, t.Details); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// loadDetailModifiers mengisi modifier yang dipilih pada setiap detail transaksi.
+func (r *TransactionRepository) loadDetailModifiers(transactionID int, details []models.TransactionDetail) error {
+	rows, err := r.db.Query(`SELECT tdm.transaction_detail_id, COALESCE(tdm.modifier_id, 0), tdm.group_name, tdm.name, tdm.price
+			FROM transaction_detail_modifiers tdm
+			JOIN transaction_details td ON tdm.transaction_detail_id = td.id
+			WHERE td.transaction_id = $1
+			ORDER BY tdm.transaction_detail_id, tdm.position`, transactionID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	index := make(map[int]int, len(details))
+	for i, d := range details {
+		index[d.ID] = i
+	}
+	for rows.Next() {
+		var detailID int
+		var m models.SelectedModifier
+		if err := rows.Scan(&detailID, &m.ModifierID, &m.Group, &m.Name, &m.Price); err != nil {
+			return err
+		}
+		i := index[detailID]
+		details[i].Modifiers = append(details[i].Modifiers, m)
+	}
+	return rows.Err()
+}
+
+// loadDetailComponents mengisi komponen yang terpakai oleh detail paket dan resep.
+func (r *TransactionRepository) loadDetailComponents(transactionID int, details []models.TransactionDetail) error {
+	rows, err := r.db.Query(`SELECT tdc.transaction_detail_id, tdc.product_id, COALESCE(p.name, ''), COALESCE(p.unit, ''), tdc.quantity
+			FROM transaction_detail_components tdc
+			JOIN transaction_details td ON tdc.transaction_detail_id = td.id
+			LEFT JOIN products p ON tdc.product_id = p.id
+			WHERE td.transaction_id = $1
+			ORDER BY tdc.transaction_detail_id, p.name`, transactionID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	index := make(map[int]int, len(details))
+	for i, d := range details {
+		index[d.ID] = i
+	}
+	for rows.Next() {
+		var detailID int
+		var c models.ProductComponent
+		if err := rows.Scan(&detailID, &c.ProductID, &c.ProductName, &c.Unit, &c.Quantity); err != nil {
+			return err
+		}
+		i := index[detailID]
+		details[i].Components = append(details[i].Components, c)
+	}
+	return rows.Err()
+}
+
+// RecordPrint mengunci transaksi, memanggil render dengan jumlah cetak sebelumnya, lalu menambah
+// print_count hanya jika render berhasil. Cetak bersamaan menunggu giliran sehingga hanya satu
+// yang dianggap cetakan asli.
+func (r *TransactionRepository) RecordPrint(outletID int, id int, render func(printed int) error) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var printed int
+	err = tx.QueryRow("SELECT print_count FROM transactions WHERE id = $1 AND outlet_id = $2 FOR UPDATE", id, outletID).Scan(&printed)
+	if err == sql.ErrNoRows {
+		return apperror.NotFound("transaction not found")
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := render(printed); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("UPDATE transactions SET print_count = print_count + 1 WHERE id = $1", id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *TransactionRepository) GetPrintCount(outletID int, id int) (int, error) {
+	var count int
+	err := r.db.QueryRow("SELECT print_count FROM transactions WHERE id = $1 AND outlet_id = $2", id, outletID).Scan(&count)
+	if err == sql.ErrNoRows {
+		return 0, apperror.NotFound("transaction not found")
+	}
+	return count, err
 }
@@ -0,0 +1,316 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"kasir-api/apperror"
+	"kasir-api/models"
+
+	"github.com/lib/pq"
+)
+
+type TransferRepository struct {
+	db *sql.DB
+}
+
+func NewTransferRepository(db *sql.DB) *TransferRepository {
+	return &TransferRepository{db: db}
+}
+
+const transferColumns = "id, from_outlet_id, to_outlet_id, status, note, created_at, shipped_at, received_at"
+
+func scanTransfer(row interface{ Scan(...interface{}) error }) (*models.StockTransfer, error) {
+	var t models.StockTransfer
+	err := row.Scan(&t.ID, &t.FromOutletID, &t.ToOutletID, &t.Status, &t.Note, &t.CreatedAt, &t.ShippedAt, &t.ReceivedAt)
+	if err != nil {
+		return nil, err
+	}
+	t.Lines = make([]models.StockTransferLine, 0)
+	return &t, nil
+}
+
+func (repo *TransferRepository) Create(t *models.StockTransfer) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := "INSERT INTO stock_transfers (from_outlet_id, to_outlet_id, note) VALUES ($1, $2, $3) RETURNING " + transferColumns
+	created, err := scanTransfer(tx.QueryRow(query, t.FromOutletID, t.ToOutletID, t.Note))
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
+		return apperror.NotFound("outlet not found")
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, line := range t.Lines {
+		if err := checkStockQuantity(tx, line.ProductID, line.Quantity); err != nil {
+			return err
+		}
+		_, err = tx.Exec("INSERT INTO stock_transfer_lines (transfer_id, product_id, quantity) VALUES ($1, $2, $3)", created.ID, line.ProductID, line.Quantity)
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
+			return apperror.NotFound("product id %d not found", line.ProductID)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := loadTransferLines(tx, created); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	*t = *created
+	return nil
+}
+
+// GetAll mengambil transfer yang keluar dari atau masuk ke outlet, terbaru lebih dulu.
+func (repo *TransferRepository) GetAll(outletID int, status string) ([]models.StockTransfer, error) {
+	rows, err := repo.db.Query(`SELECT `+transferColumns+` FROM stock_transfers
+			WHERE (from_outlet_id = $1 OR to_outlet_id = $1) AND ($2 = '' OR status = $2)
+			ORDER BY created_at DESC, id DESC`, outletID, status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	transfers := make([]models.StockTransfer, 0)
+	for rows.Next() {
+		t, err := scanTransfer(rows)
+		if err != nil {
+			return nil, err
+		}
+		transfers = append(transfers, *t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	for i := range transfers {
+		if err := loadTransferLines(repo.db, &transfers[i]); err != nil {
+			return nil, err
+		}
+	}
+	return transfers, nil
+}
+
+func (repo *TransferRepository) GetByID(id int) (*models.StockTransfer, error) {
+	t, err := scanTransfer(repo.db.QueryRow("SELECT "+transferColumns+" FROM stock_transfers WHERE id = $1", id))
+	if err == sql.ErrNoRows {
+		return nil, apperror.NotFound("transfer not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := loadTransferLines(repo.db, t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func loadTransferLines(q querier, t *models.StockTransfer) error {
+	rows, err := q.Query(`SELECT l.product_id, p.name, l.quantity, l.quantity_shipped, l.quantity_received
+			FROM stock_transfer_lines l
+			JOIN products p ON l.product_id = p.id
+			WHERE l.transfer_id = $1
+			ORDER BY p.name`, t.ID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	t.Lines = make([]models.StockTransferLine, 0)
+	for rows.Next() {
+		var line models.StockTransferLine
+		err := rows.Scan(&line.ProductID, &line.ProductName, &line.Quantity, &line.QuantityShipped, &line.QuantityReceived)
+		if err != nil {
+			return err
+		}
+		if line.QuantityShipped != nil && line.QuantityReceived != nil {
+			line.Discrepancy = models.RoundQuantity(*line.QuantityShipped - *line.QuantityReceived)
+		}
+		t.Lines = append(t.Lines, line)
+	}
+	return rows.Err()
+}
+
+// lockTransfer mengunci dokumen transfer beserta barisnya selama transaksi database.
+func lockTransfer(tx *sql.Tx, id int) (*models.StockTransfer, error) {
+	t, err := scanTransfer(tx.QueryRow("SELECT "+transferColumns+" FROM stock_transfers WHERE id = $1 FOR UPDATE", id))
+	if err == sql.ErrNoRows {
+		return nil, apperror.NotFound("transfer not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return t, loadTransferLines(tx, t)
+}
+
+// transferProductIDs mengembalikan id produk semua baris transfer untuk lockOutletStocks.
+func transferProductIDs(t *models.StockTransfer) []int64 {
+	ids := make([]int64, len(t.Lines))
+	for i, line := range t.Lines {
+		ids[i] = int64(line.ProductID)
+	}
+	return ids
+}
+
+// Ship mengirim transfer: stok outlet asal dikurangi sebesar jumlah kirim (shipped[product_id],
+// default jumlah diminta) dan dicatat sebagai transfer_out di buku stok.
+func (repo *TransferRepository) Ship(id int, shipped map[int]float64) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	t, err := lockTransfer(tx, id)
+	if err != nil {
+		return err
+	}
+	if t.Status != models.TransferRequested {
+		return apperror.Conflict("transfer %d is already %s", id, t.Status)
+	}
+	if err := lockOutletStocks(tx, t.FromOutletID, transferProductIDs(t)); err != nil {
+		return err
+	}
+
+	for _, line := range t.Lines {
+		quantity, ok := shipped[line.ProductID]
+		if !ok {
+			quantity = line.Quantity
+		}
+		if quantity > line.Quantity {
+			return apperror.BadRequest("product id %d: shipping %s is more than requested %s", line.ProductID, models.FormatQuantity(quantity), models.FormatQuantity(line.Quantity))
+		}
+
+		if quantity > 0 {
+			if err := checkStockQuantity(tx, line.ProductID, quantity); err != nil {
+				return err
+			}
+			stock := 0.0
+			err := tx.QueryRow("SELECT stock FROM outlet_products WHERE outlet_id = $1 AND product_id = $2 FOR UPDATE", t.FromOutletID, line.ProductID).Scan(&stock)
+			if err != nil && err != sql.ErrNoRows {
+				return err
+			}
+			if stock < quantity {
+				return fmt.Errorf("%w: product id %d has %s left at outlet %d, shipping %s", ErrInsufficientStock, line.ProductID, models.FormatQuantity(stock), t.FromOutletID, models.FormatQuantity(quantity))
+			}
+
+			err = changeOutletStock(tx, models.StockMovement{
+				OutletID:   t.FromOutletID,
+				ProductID:  line.ProductID,
+				Quantity:   -quantity,
+				Type:       models.StockTransferOut,
+				TransferID: &t.ID,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = tx.Exec("UPDATE stock_transfer_lines SET quantity_shipped = $1 WHERE transfer_id = $2 AND product_id = $3", quantity, id, line.ProductID)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec("UPDATE stock_transfers SET status = $1, shipped_at = NOW() WHERE id = $2", models.TransferShipped, id)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Receive menerima transfer: stok outlet tujuan ditambah sebesar jumlah terima (received[product_id],
+// default jumlah kirim) dan dicatat sebagai transfer_in. Jika ada selisih, status menjadi
+// received_with_discrepancy.
+func (repo *TransferRepository) Receive(id int, received map[int]float64) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	t, err := lockTransfer(tx, id)
+	if err != nil {
+		return err
+	}
+	if t.Status != models.TransferShipped {
+		return apperror.Conflict("transfer %d is %s, only shipped transfers can be received", id, t.Status)
+	}
+	if err := lockOutletStocks(tx, t.ToOutletID, transferProductIDs(t)); err != nil {
+		return err
+	}
+
+	status := models.TransferReceived
+	for _, line := range t.Lines {
+		shipped := 0.0
+		if line.QuantityShipped != nil {
+			shipped = *line.QuantityShipped
+		}
+
+		quantity, ok := received[line.ProductID]
+		if !ok {
+			quantity = shipped
+		}
+		if quantity > shipped {
+			return apperror.BadRequest("product id %d: received %s is more than shipped %s", line.ProductID, models.FormatQuantity(quantity), models.FormatQuantity(shipped))
+		}
+		if quantity != shipped {
+			status = models.TransferDiscrepancy
+		}
+
+		if quantity > 0 {
+			if err := checkStockQuantity(tx, line.ProductID, quantity); err != nil {
+				return err
+			}
+			err = changeOutletStock(tx, models.StockMovement{
+				OutletID:   t.ToOutletID,
+				ProductID:  line.ProductID,
+				Quantity:   quantity,
+				Type:       models.StockTransferIn,
+				TransferID: &t.ID,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = tx.Exec("UPDATE stock_transfer_lines SET quantity_received = $1 WHERE transfer_id = $2 AND product_id = $3", quantity, id, line.ProductID)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec("UPDATE stock_transfers SET status = $1, received_at = NOW() WHERE id = $2", status, id)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Cancel membatalkan transfer yang belum dikirim. Stok tidak berubah.
+func (repo *TransferRepository) Cancel(id int) error {
+	result, err := repo.db.Exec("UPDATE stock_transfers SET status = $1 WHERE id = $2 AND status = $3", models.TransferCancelled, id, models.TransferRequested)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		if _, err := repo.GetByID(id); err != nil {
+			return err
+		}
+		return apperror.Conflict("transfer %d can only be cancelled while requested", id)
+	}
+	return nil
+}
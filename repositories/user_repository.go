@@ -0,0 +1,220 @@
+package repositories
+
+import (
+	"database/sql"
+	"kasir-api/apperror"
+	"kasir-api/models"
+
+	"github.com/lib/pq"
+)
+
+type UserRepository struct {
+	db *sql.DB
+}
+
+func NewUserRepository(db *sql.DB) *UserRepository {
+	return &UserRepository{db: db}
+}
+
+const userQuery = `SELECT u.id, u.name, u.role, u.created_at,
+				COALESCE(ARRAY_AGG(uo.outlet_id ORDER BY uo.outlet_id) FILTER (WHERE uo.outlet_id IS NOT NULL), '{}')
+			FROM users u
+			LEFT JOIN user_outlets uo ON uo.user_id = u.id`
+
+func scanUser(row interface{ Scan(...interface{}) error }) (*models.User, error) {
+	var u models.User
+	var outletIDs pq.Int64Array
+	err := row.Scan(&u.ID, &u.Name, &u.Role, &u.CreatedAt, &outletIDs)
+	if err != nil {
+		return nil, err
+	}
+	u.OutletIDs = toInts(outletIDs)
+	return &u, nil
+}
+
+func (repo *UserRepository) GetAll() ([]models.User, error) {
+	rows, err := repo.db.Query(userQuery + " GROUP BY u.id ORDER BY u.id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := make([]models.User, 0)
+	for rows.Next() {
+		u, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, *u)
+	}
+	return users, rows.Err()
+}
+
+func (repo *UserRepository) GetByID(id int) (*models.User, error) {
+	u, err := scanUser(repo.db.QueryRow(userQuery+" WHERE u.id = $1 GROUP BY u.id", id))
+	if err == sql.ErrNoRows {
+		return nil, apperror.NotFound("user not found")
+	}
+	return u, err
+}
+
+// GetByAPIKeyHash mencari pengguna dari hash API key. Mengembalikan nil tanpa error jika tidak ada.
+func (repo *UserRepository) GetByAPIKeyHash(hash string) (*models.User, error) {
+	u, err := scanUser(repo.db.QueryRow(userQuery+" WHERE u.api_key_hash = $1 GROUP BY u.id", hash))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return u, err
+}
+
+func (repo *UserRepository) Create(u *models.User, apiKeyHash string, audit models.AuditMeta) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRow("INSERT INTO users (name, role, api_key_hash) VALUES ($1, $2, $3) RETURNING id, created_at", u.Name, u.Role, apiKeyHash).Scan(&u.ID, &u.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	if err := setUserOutlets(tx, u.ID, u.OutletIDs); err != nil {
+		return err
+	}
+
+	after, err := userSnapshot(tx, u.ID)
+	if err != nil {
+		return err
+	}
+	if err := recordAudit(tx, audit, models.AuditCreate, models.AuditEntityUser, u.ID, nil, after); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Update mengubah nama, peran dan penugasan outlet pengguna.
+func (repo *UserRepository) Update(u *models.User, audit models.AuditMeta) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := userSnapshot(tx, u.ID)
+	if err != nil {
+		return err
+	}
+
+	err = tx.QueryRow("UPDATE users SET name = $1, role = $2 WHERE id = $3 RETURNING created_at", u.Name, u.Role, u.ID).Scan(&u.CreatedAt)
+	if err == sql.ErrNoRows {
+		return apperror.NotFound("user not found")
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := setUserOutlets(tx, u.ID, u.OutletIDs); err != nil {
+		return err
+	}
+
+	after, err := userSnapshot(tx, u.ID)
+	if err != nil {
+		return err
+	}
+	if err := recordAudit(tx, audit, models.AuditUpdate, models.AuditEntityUser, u.ID, before, after); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (repo *UserRepository) SetAPIKeyHash(id int, apiKeyHash string, audit models.AuditMeta) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := userSnapshot(tx, id)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("UPDATE users SET api_key_hash = $1 WHERE id = $2", apiKeyHash, id)
+	if err != nil {
+		return err
+	}
+
+	after, err := userSnapshot(tx, id)
+	if err != nil {
+		return err
+	}
+	if err := recordAudit(tx, audit, models.AuditUpdate, models.AuditEntityUser, id, before, after); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (repo *UserRepository) Delete(id int, audit models.AuditMeta) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := userSnapshot(tx, id)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("DELETE FROM users WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+
+	if err := recordAudit(tx, audit, models.AuditDelete, models.AuditEntityUser, id, before, nil); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// userSnapshot mengunci dan membaca pengguna untuk audit log. Hanya 8 karakter awal hash API key
+// yang dicatat, cukup untuk melihat kapan key diganti.
+func userSnapshot(tx *sql.Tx, id int) (map[string]interface{}, error) {
+	var name, role, keyPrefix string
+	err := tx.QueryRow("SELECT name, role, LEFT(api_key_hash, 8) FROM users WHERE id = $1 FOR UPDATE", id).Scan(&name, &role, &keyPrefix)
+	if err == sql.ErrNoRows {
+		return nil, apperror.NotFound("user not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var outletIDs pq.Int64Array
+	err = tx.QueryRow("SELECT COALESCE(ARRAY_AGG(outlet_id ORDER BY outlet_id), '{}') FROM user_outlets WHERE user_id = $1", id).Scan(&outletIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"id":             id,
+		"name":           name,
+		"role":           role,
+		"outlet_ids":     toInts(outletIDs),
+		"api_key_prefix": keyPrefix,
+	}, nil
+}
+
+// setUserOutlets mengganti seluruh penugasan outlet pengguna.
+func setUserOutlets(tx *sql.Tx, userID int, outletIDs []int) error {
+	_, err := tx.Exec("DELETE FROM user_outlets WHERE user_id = $1", userID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("INSERT INTO user_outlets (user_id, outlet_id) SELECT $1, UNNEST($2::int[])", userID, pq.Array(outletIDs))
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
+		return apperror.NotFound("outlet not found")
+	}
+	return err
+}
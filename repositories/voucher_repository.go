@@ -0,0 +1,289 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"kasir-api/apperror"
+	"kasir-api/models"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type VoucherRepository struct {
+	db *sql.DB
+}
+
+func NewVoucherRepository(db *sql.DB) *VoucherRepository {
+	return &VoucherRepository{db: db}
+}
+
+const voucherColumns = `id, code, type, value, max_discount, min_spend, usage_limit, used_count,
+	product_ids, category_ids, starts_at, expires_at, active, created_at`
+
+func scanVoucher(row interface{ Scan(...interface{}) error }) (*models.Voucher, error) {
+	var v models.Voucher
+	var productIDs, categoryIDs pq.Int64Array
+	err := row.Scan(&v.ID, &v.Code, &v.Type, &v.Value, &v.MaxDiscount, &v.MinSpend, &v.UsageLimit, &v.UsedCount,
+		&productIDs, &categoryIDs, &v.StartsAt, &v.ExpiresAt, &v.Active, &v.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	v.ProductIDs = toInts(productIDs)
+	v.CategoryIDs = toInts(categoryIDs)
+	return &v, nil
+}
+
+func (repo *VoucherRepository) GetVouchers() ([]models.Voucher, error) {
+	rows, err := repo.db.Query("SELECT " + voucherColumns + " FROM vouchers ORDER BY created_at DESC")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	vouchers := make([]models.Voucher, 0)
+	for rows.Next() {
+		v, err := scanVoucher(rows)
+		if err != nil {
+			return nil, err
+		}
+		vouchers = append(vouchers, *v)
+	}
+	return vouchers, rows.Err()
+}
+
+func (repo *VoucherRepository) GetVoucherByCode(code string) (*models.Voucher, error) {
+	v, err := scanVoucher(repo.db.QueryRow("SELECT "+voucherColumns+" FROM vouchers WHERE code = $1", code))
+	if err == sql.ErrNoRows {
+		return nil, apperror.NotFound("voucher not found")
+	}
+	return v, err
+}
+
+func (repo *VoucherRepository) CreateVoucher(v *models.Voucher, audit models.AuditMeta) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO vouchers (code, type, value, max_discount, min_spend, usage_limit, product_ids, category_ids, starts_at, expires_at, active)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING id, used_count, created_at`
+	err = tx.QueryRow(query, v.Code, v.Type, v.Value, v.MaxDiscount, v.MinSpend, v.UsageLimit,
+		pq.Array(v.ProductIDs), pq.Array(v.CategoryIDs), v.StartsAt, v.ExpiresAt, v.Active).Scan(&v.ID, &v.UsedCount, &v.CreatedAt)
+	if err != nil {
+		return uniqueConflict(err, "vouchers_code_key", "voucher code %s already exists", v.Code)
+	}
+
+	if err := recordAudit(tx, audit, models.AuditCreate, models.AuditEntityVoucher, v.ID, nil, v); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (repo *VoucherRepository) GetGiftCards() ([]models.GiftCard, error) {
+	rows, err := repo.db.Query("SELECT id, code, initial_balance, balance, expires_at, active, created_at FROM gift_cards ORDER BY created_at DESC")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cards := make([]models.GiftCard, 0)
+	for rows.Next() {
+		var g models.GiftCard
+		err := rows.Scan(&g.ID, &g.Code, &g.InitialBalance, &g.Balance, &g.ExpiresAt, &g.Active, &g.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		cards = append(cards, g)
+	}
+	return cards, rows.Err()
+}
+
+func (repo *VoucherRepository) GetGiftCardByCode(code string) (*models.GiftCard, error) {
+	var g models.GiftCard
+	query := "SELECT id, code, initial_balance, balance, expires_at, active, created_at FROM gift_cards WHERE code = $1"
+	err := repo.db.QueryRow(query, code).Scan(&g.ID, &g.Code, &g.InitialBalance, &g.Balance, &g.ExpiresAt, &g.Active, &g.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, apperror.NotFound("gift card not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
+
+func (repo *VoucherRepository) CreateGiftCard(g *models.GiftCard, audit models.AuditMeta) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO gift_cards (code, initial_balance, balance, expires_at, active)
+			VALUES ($1, $2, $2, $3, $4) RETURNING id, balance, created_at`
+	err = tx.QueryRow(query, g.Code, g.InitialBalance, g.ExpiresAt, g.Active).Scan(&g.ID, &g.Balance, &g.CreatedAt)
+	if err != nil {
+		return uniqueConflict(err, "gift_cards_code_key", "gift card code %s already exists", g.Code)
+	}
+
+	if err := recordAudit(tx, audit, models.AuditCreate, models.AuditEntityGiftCard, g.ID, nil, g); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// getRedemptions mengambil riwayat pemakaian satu kode voucher atau kartu hadiah.
+// column adalah voucher_id atau gift_card_id.
+func (repo *VoucherRepository) getRedemptions(table string, column string, code string) ([]models.Redemption, error) {
+	query := fmt.Sprintf(`SELECT r.id, r.transaction_id, c.code, r.amount, r.created_at
+			FROM redemptions r
+			JOIN %s c ON r.%s = c.id
+			WHERE c.code = $1
+			ORDER BY r.created_at DESC, r.id DESC`, table, column)
+
+	rows, err := repo.db.Query(query, code)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	redemptions := make([]models.Redemption, 0)
+	for rows.Next() {
+		var r models.Redemption
+		err := rows.Scan(&r.ID, &r.TransactionID, &r.Code, &r.Amount, &r.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		redemptions = append(redemptions, r)
+	}
+	return redemptions, rows.Err()
+}
+
+func (repo *VoucherRepository) GetVoucherRedemptions(code string) ([]models.Redemption, error) {
+	return repo.getRedemptions("vouchers", "voucher_id", code)
+}
+
+func (repo *VoucherRepository) GetGiftCardRedemptions(code string) ([]models.Redemption, error) {
+	return repo.getRedemptions("gift_cards", "gift_card_id", code)
+}
+
+// applyVoucher memvalidasi voucher dan menghitung potongannya di dalam transaksi checkout.
+// Baris voucher dikunci dengan FOR UPDATE sehingga checkout bersamaan tidak bisa
+// melewati batas pemakaian. lines berisi subtotal dan kategori setiap item. Masa berlaku diperiksa
+// pada saleTime, yaitu waktu di terminal untuk transaksi offline.
+func applyVoucher(tx *sql.Tx, code string, lines []voucherLine, subtotal int, saleTime time.Time) (int, int, error) {
+	v, err := scanVoucher(tx.QueryRow("SELECT "+voucherColumns+" FROM vouchers WHERE code = $1 FOR UPDATE", code))
+	if err == sql.ErrNoRows {
+		return 0, 0, apperror.NotFound("voucher %s not found", code)
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+
+	switch {
+	case !v.Active:
+		return 0, 0, apperror.Conflict("voucher %s is not active", code)
+	case v.StartsAt != nil && saleTime.Before(*v.StartsAt):
+		return 0, 0, apperror.Conflict("voucher %s is not valid yet", code)
+	case v.ExpiresAt != nil && !saleTime.Before(*v.ExpiresAt):
+		return 0, 0, apperror.Conflict("voucher %s has expired", code)
+	case v.UsageLimit != nil && v.UsedCount >= *v.UsageLimit:
+		return 0, 0, apperror.Conflict("voucher %s usage limit reached", code)
+	case subtotal < v.MinSpend:
+		return 0, 0, apperror.Conflict("voucher %s requires a minimum spend of Rp%d", code, v.MinSpend)
+	}
+
+	eligible := 0
+	for _, l := range lines {
+		if voucherApplies(v, l) {
+			eligible += l.subtotal
+		}
+	}
+	if eligible == 0 {
+		return 0, 0, apperror.Conflict("voucher %s does not apply to any item in the cart", code)
+	}
+
+	discount := v.Value
+	if v.Type == models.VoucherPercent {
+		discount = eligible * v.Value / 100
+		if v.MaxDiscount != nil && discount > *v.MaxDiscount {
+			discount = *v.MaxDiscount
+		}
+	}
+	if discount > eligible {
+		discount = eligible
+	}
+
+	_, err = tx.Exec("UPDATE vouchers SET used_count = used_count + 1 WHERE id = $1", v.ID)
+	if err != nil {
+		return 0, 0, err
+	}
+	return v.ID, discount, nil
+}
+
+type voucherLine struct {
+	productID  int
+	categoryID int
+	subtotal   int
+}
+
+func voucherApplies(v *models.Voucher, l voucherLine) bool {
+	if len(v.ProductIDs) == 0 && len(v.CategoryIDs) == 0 {
+		return true
+	}
+	for _, id := range v.ProductIDs {
+		if id == l.productID {
+			return true
+		}
+	}
+	for _, id := range v.CategoryIDs {
+		if id == l.categoryID {
+			return true
+		}
+	}
+	return false
+}
+
+// applyGiftCard memotong saldo kartu hadiah sebesar maksimal due di dalam transaksi checkout.
+// Masa berlaku diperiksa pada saleTime seperti voucher.
+func applyGiftCard(tx *sql.Tx, code string, due int, saleTime time.Time) (int, int, error) {
+	var id, balance int
+	var active bool
+	var expiresAt *time.Time
+	err := tx.QueryRow("SELECT id, balance, active, expires_at FROM gift_cards WHERE code = $1 FOR UPDATE", code).Scan(&id, &balance, &active, &expiresAt)
+	if err == sql.ErrNoRows {
+		return 0, 0, apperror.NotFound("gift card %s not found", code)
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+
+	switch {
+	case !active:
+		return 0, 0, apperror.Conflict("gift card %s is not active", code)
+	case expiresAt != nil && !saleTime.Before(*expiresAt):
+		return 0, 0, apperror.Conflict("gift card %s has expired", code)
+	case balance == 0:
+		return 0, 0, apperror.Conflict("gift card %s has no remaining balance", code)
+	}
+
+	amount := balance
+	if amount > due {
+		amount = due
+	}
+
+	_, err = tx.Exec("UPDATE gift_cards SET balance = balance - $1 WHERE id = $2", amount, id)
+	if err != nil {
+		return 0, 0, err
+	}
+	return id, amount, nil
+}
+
+func toInts(values pq.Int64Array) []int {
+	ints := make([]int, len(values))
+	for i, v := range values {
+		ints[i] = int(v)
+	}
+	return ints
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"database/sql"
+	"kasir-api/database"
+	"kasir-api/handlers"
+	"kasir-api/middlewares"
+	"kasir-api/models"
+	"kasir-api/receipts"
+	"kasir-api/repositories"
+	"kasir-api/services"
+	"kasir-api/storage"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// appOptions berisi pengaturan toko yang sama untuk semua tenant
+type appOptions struct {
+	location       *time.Location
+	loyaltyRules   models.LoyaltyRules
+	receiptPrinter *receipts.Printer
+	cartTTL        time.Duration
+	imageStore     storage.Storage
+	// publicCatalog membuka GET katalog dan laporan tanpa API key (mode satu toko)
+	publicCatalog bool
+}
+
+// newRouter menyusun semua endpoint /api untuk satu database. Pada mode multi-tenant
+// setiap tenant mendapat router sendiri dengan koneksi yang hanya melihat schema tenant tersebut.
+func newRouter(db *sql.DB, ownerKeyHash string, opts appOptions) *http.ServeMux {
+	mux := http.NewServeMux()
+
+	userRepo := repositories.NewUserRepository(db)
+	userService := services.NewUserService(userRepo, ownerKeyHash)
+	userHandler := handlers.NewUserHandler(userService)
+
+	outletRepo := repositories.NewOutletRepository(db)
+	outletService := services.NewOutletService(outletRepo)
+	outletHandler := handlers.NewOutletHandler(outletService)
+
+	// Key pemilik berlaku sebagai owner, pengguna lain memakai API key masing-masing.
+	// outletMiddleware memilih outlet dari X-Outlet-ID / outlet_id untuk semua query yang dibatasi per outlet.
+	apiKeyMiddleware := middlewares.APIkey(userService.Authenticate)
+	outletMiddleware := middlewares.Outlet(outletService.Resolve)
+
+	// Pada mode multi-tenant katalog dan laporan juga memerlukan API key milik tenant tersebut,
+	// jadi mengetahui slug tenant saja tidak cukup untuk membaca atau mengubah datanya. Pada mode
+	// satu toko hanya GET yang publik; menambah kategori atau produk tetap memerlukan API key.
+	catalogMiddleware := apiKeyMiddleware
+	if opts.publicCatalog {
+		catalogMiddleware = middlewares.PublicReads(apiKeyMiddleware)
+	}
+
+	// var categories = models.DataCategories
+	productRepo := repositories.NewProductRepository(db)
+	productService := services.NewProductService(productRepo)
+	priceRepo := repositories.NewPriceRepository(db)
+	priceService := services.NewPriceService(priceRepo, opts.location)
+	imageService := services.NewProductImageService(productRepo, opts.imageStore)
+	productHandler := handlers.NewProductHandler(productService, priceService, imageService)
+	imageHandler := handlers.NewImageHandler(imageService)
+
+	transactionRepo := repositories.NewTransactionRepository(db)
+	transactionService := services.NewTransactionService(transactionRepo, opts.location, opts.loyaltyRules)
+	transactionHandler := handlers.NewTransactionHandler(transactionService, opts.receiptPrinter)
+
+	customerRepo := repositories.NewCustomerRepository(db)
+	customerService := services.NewCustomerService(customerRepo, opts.location, opts.loyaltyRules)
+	customerHandler := handlers.NewCustomerHandler(customerService)
+
+	cartRepo := repositories.NewCartRepository(db)
+	cartService := services.NewCartService(cartRepo, opts.location, opts.loyaltyRules, opts.cartTTL)
+	cartHandler := handlers.NewCartHandler(cartService)
+
+	voucherRepo := repositories.NewVoucherRepository(db)
+	voucherService := services.NewVoucherService(voucherRepo)
+	voucherHandler := handlers.NewVoucherHandler(voucherService)
+
+	transferRepo := repositories.NewTransferRepository(db)
+	transferService := services.NewTransferService(transferRepo, opts.location)
+	transferHandler := handlers.NewTransferHandler(transferService)
+
+	stockRepo := repositories.NewStockRepository(db)
+	stockService := services.NewStockService(stockRepo, opts.location)
+	stockHandler := handlers.NewStockHandler(stockService)
+
+	syncRepo := repositories.NewSyncRepository(db)
+	syncService := services.NewSyncService(transactionRepo, syncRepo, opts.location, opts.loyaltyRules)
+	syncHandler := handlers.NewSyncHandler(syncService)
+
+	auditRepo := repositories.NewAuditRepository(db)
+	auditService := services.NewAuditService(auditRepo, opts.location)
+	auditHandler := handlers.NewAuditHandler(auditService)
+
+	reportRepo := repositories.NewReportRepository(db)
+	reportService := services.NewReportService(reportRepo, opts.location)
+	reportHandler := handlers.NewReportHandler(reportService)
+
+	mux.HandleFunc("/api/kategori", middlewares.CORS(middlewares.Logger(catalogMiddleware(productHandler.HandleCategories))))
+	mux.HandleFunc("/api/produk", middlewares.CORS(middlewares.Logger(catalogMiddleware(outletMiddleware(productHandler.HandleProducts)))))
+	mux.HandleFunc("/api/produk/", middlewares.CORS(middlewares.Logger(apiKeyMiddleware(outletMiddleware(productHandler.HandleProductByID)))))
+	mux.HandleFunc("/api/gambar/", middlewares.CORS(middlewares.Logger(imageHandler.Serve)))
+	mux.HandleFunc("/api/outlet", middlewares.CORS(middlewares.Logger(apiKeyMiddleware(outletHandler.HandleOutlets))))
+	mux.HandleFunc("/api/outlet/", middlewares.CORS(middlewares.Logger(apiKeyMiddleware(outletHandler.HandleOutletByID))))
+	mux.HandleFunc("/api/transfer", middlewares.CORS(middlewares.Logger(apiKeyMiddleware(outletMiddleware(transferHandler.HandleTransfers)))))
+	mux.HandleFunc("/api/transfer/", middlewares.CORS(middlewares.Logger(apiKeyMiddleware(transferHandler.HandleTransferByID))))
+	mux.HandleFunc("/api/stok/mutasi", middlewares.CORS(middlewares.Logger(apiKeyMiddleware(outletMiddleware(stockHandler.GetMovements)))))
+	mux.HandleFunc("/api/pengguna", middlewares.CORS(middlewares.Logger(apiKeyMiddleware(middlewares.OwnerOnly(userHandler.HandleUsers)))))
+	mux.HandleFunc("/api/pengguna/", middlewares.CORS(middlewares.Logger(apiKeyMiddleware(middlewares.OwnerOnly(userHandler.HandleUserByID)))))
+	mux.HandleFunc("/api/pelanggan", middlewares.CORS(middlewares.Logger(apiKeyMiddleware(customerHandler.HandleCustomers))))
+	mux.HandleFunc("/api/pelanggan/", middlewares.CORS(middlewares.Logger(apiKeyMiddleware(customerHandler.HandleCustomerByID))))
+	mux.HandleFunc("/api/keranjang", middlewares.CORS(middlewares.Logger(apiKeyMiddleware(outletMiddleware(cartHandler.HandleCarts)))))
+	mux.HandleFunc("/api/keranjang/", middlewares.CORS(middlewares.Logger(apiKeyMiddleware(outletMiddleware(cartHandler.HandleCartByID)))))
+	mux.HandleFunc("/api/voucher", middlewares.CORS(middlewares.Logger(apiKeyMiddleware(voucherHandler.HandleVouchers))))
+	mux.HandleFunc("/api/voucher/", middlewares.CORS(middlewares.Logger(apiKeyMiddleware(voucherHandler.HandleVoucherByCode))))
+	mux.HandleFunc("/api/gift-card", middlewares.CORS(middlewares.Logger(apiKeyMiddleware(voucherHandler.HandleGiftCards))))
+	mux.HandleFunc("/api/gift-card/", middlewares.CORS(middlewares.Logger(apiKeyMiddleware(voucherHandler.HandleGiftCardByCode))))
+	mux.HandleFunc("/api/audit", middlewares.CORS(middlewares.Logger(apiKeyMiddleware(middlewares.OwnerOnly(auditHandler.GetAll)))))
+	mux.HandleFunc("/api/report/", middlewares.CORS(middlewares.Logger(catalogMiddleware(outletMiddleware(reportHandler.HandleReport)))))
+	mux.HandleFunc("/api/report/konsolidasi", middlewares.CORS(middlewares.Logger(apiKeyMiddleware(middlewares.OwnerOnly(reportHandler.GetConsolidated)))))
+	mux.HandleFunc("/api/transaksi", middlewares.CORS(middlewares.Logger(apiKeyMiddleware(outletMiddleware(transactionHandler.HandleTransactions)))))
+	mux.HandleFunc("/api/transaksi/", middlewares.CORS(middlewares.Logger(apiKeyMiddleware(outletMiddleware(transactionHandler.HandleTransactionByID)))))
+	mux.HandleFunc("/api/checkout", middlewares.CORS(middlewares.Logger(apiKeyMiddleware(outletMiddleware(transactionHandler.HandleCheckout)))))
+	mux.HandleFunc("/api/sync/transaksi", middlewares.CORS(middlewares.Logger(apiKeyMiddleware(outletMiddleware(syncHandler.HandleTransactions)))))
+	mux.HandleFunc("/api/sync/changes", middlewares.CORS(middlewares.Logger(apiKeyMiddleware(outletMiddleware(syncHandler.HandleChanges)))))
+
+	return mux
+}
+
+// startPriceScheduler memberlakukan harga terjadwal milik db setiap menit.
+// Fungsi yang dikembalikan menghentikan scheduler.
+func startPriceScheduler(db *sql.DB, opts appOptions) func() {
+	priceService := services.NewPriceService(repositories.NewPriceRepository(db), opts.location)
+	stop := make(chan struct{})
+	go priceService.RunScheduler(time.Minute, stop)
+	return func() { close(stop) }
+}
+
+type tenantRouter struct {
+	db            *sql.DB
+	keyHash       string
+	store         receipts.Store
+	handler       http.Handler
+	stopScheduler func()
+}
+
+// tenantRouters menyimpan koneksi dan router per tenant. Koneksi dibuka (dan schema dimigrasi)
+// saat tenant pertama kali diakses; router disusun ulang jika API key pemilik tenant atau identitas
+// struknya diganti.
+// Checkout tetap memakai harga sesuai riwayat, jadi harga terjadwal tenant yang belum pernah
+// diakses tetap benar walaupun scheduler-nya baru berjalan saat tenant dibuka.
+type tenantRouters struct {
+	mu      sync.Mutex
+	dbConn  string
+	opts    appOptions
+	routers map[int]*tenantRouter
+}
+
+func newTenantRouters(dbConn string, opts appOptions) *tenantRouters {
+	return &tenantRouters{dbConn: dbConn, opts: opts, routers: make(map[int]*tenantRouter)}
+}
+
+func (t *tenantRouters) handlerFor(tenant *models.Tenant) (http.Handler, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	router, ok := t.routers[tenant.ID]
+	if !ok {
+		db, err := database.OpenSchema(t.dbConn, tenant.Schema)
+		if err != nil {
+			return nil, err
+		}
+		// Schema tenant lama ikut mendapat migrasi yang ditambahkan setelah tenant dibuat
+		if err := database.Migrate(db); err != nil {
+			db.Close()
+			return nil, err
+		}
+		router = &tenantRouter{db: db, stopScheduler: startPriceScheduler(db, t.opts)}
+		t.routers[tenant.ID] = router
+	}
+
+	store := receipts.Store{Name: tenant.Name, Address: tenant.Address, Phone: tenant.Phone, NPWP: tenant.NPWP, Footer: tenant.ReceiptFooter}
+	if router.handler == nil || router.keyHash != tenant.APIKeyHash || router.store != store {
+		router.keyHash = tenant.APIKeyHash
+		router.store = store
+		// File gambar tiap tenant disimpan di bawah folder schema-nya dan struk memakai identitas tenant
+		opts := t.opts
+		opts.imageStore = storage.WithPrefix(t.opts.imageStore, tenant.Schema)
+		opts.receiptPrinter = t.opts.receiptPrinter.WithStore(store)
+		router.handler = newRouter(router.db, tenant.APIKeyHash, opts)
+	}
+	return router.handler, nil
+}
+
+func (t *tenantRouters) Close() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, router := range t.routers {
+		router.stopScheduler()
+		router.db.Close()
+	}
+}
@@ -0,0 +1,280 @@
+package main
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"io"
+	"kasir-api/apperror"
+	"kasir-api/database"
+	"kasir-api/middlewares"
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"kasir-api/services"
+	"kasir-api/storage"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// emptyDriver adalah driver database/sql yang setiap query-nya tidak mengembalikan baris,
+// cukup untuk menguji autentikasi router tanpa Postgres.
+type emptyDriver struct{}
+
+func (emptyDriver) Open(string) (driver.Conn, error) { return emptyConn{}, nil }
+
+type emptyConn struct{}
+
+func (emptyConn) Prepare(string) (driver.Stmt, error) { return emptyStmt{}, nil }
+func (emptyConn) Close() error                        { return nil }
+func (emptyConn) Begin() (driver.Tx, error)           { return emptyTx{}, nil }
+
+type emptyTx struct{}
+
+func (emptyTx) Commit() error   { return nil }
+func (emptyTx) Rollback() error { return nil }
+
+type emptyStmt struct{}
+
+func (emptyStmt) Close() error                               { return nil }
+func (emptyStmt) NumInput() int                              { return -1 }
+func (emptyStmt) Exec([]driver.Value) (driver.Result, error) { return driver.RowsAffected(0), nil }
+func (emptyStmt) Query([]driver.Value) (driver.Rows, error)  { return emptyRows{}, nil }
+
+type emptyRows struct{}
+
+func (emptyRows) Columns() []string         { return nil }
+func (emptyRows) Close() error              { return nil }
+func (emptyRows) Next([]driver.Value) error { return io.EOF }
+
+func init() {
+	sql.Register("empty", emptyDriver{})
+}
+
+func TestTenantRoutesRequireTenantKey(t *testing.T) {
+	db, err := sql.Open("empty", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	opts := appOptions{location: time.UTC, cartTTL: time.Minute, imageStore: storage.NewLocal(t.TempDir())}
+	tenants := map[string]*models.Tenant{
+		"toko-a": {ID: 1, Slug: "toko-a", Active: true, APIKeyHash: services.HashAPIKey("key-a")},
+		"toko-b": {ID: 2, Slug: "toko-b", Active: true, APIKeyHash: services.HashAPIKey("key-b")},
+	}
+	routers := map[int]http.Handler{
+		1: newRouter(db, tenants["toko-a"].APIKeyHash, opts),
+		2: newRouter(db, tenants["toko-b"].APIKeyHash, opts),
+	}
+	handler := middlewares.Tenant(
+		func(host, requested, apiKey string) (*models.Tenant, error) {
+			tenant, ok := tenants[requested]
+			if !ok {
+				return nil, apperror.NotFound("tenant not found")
+			}
+			return tenant, nil
+		},
+		func(tenant *models.Tenant) (http.Handler, error) { return routers[tenant.ID], nil },
+	)
+
+	routes := []struct {
+		method string
+		path   string
+		body   string
+	}{
+		{http.MethodGet, "/api/kategori", ""},
+		{http.MethodPost, "/api/kategori", `{"name":"Minuman"}`},
+		{http.MethodGet, "/api/produk", ""},
+		{http.MethodPost, "/api/produk", `{"name":"Teh","price":5000}`},
+		{http.MethodGet, "/api/produk/1", ""},
+		{http.MethodGet, "/api/report/hari-ini", ""},
+		{http.MethodGet, "/api/transaksi", ""},
+		{http.MethodGet, "/api/transaksi/1", ""},
+		{http.MethodPost, "/api/checkout", `{"items":[{"product_id":1,"quantity":1}]}`},
+	}
+	cases := []struct {
+		name   string
+		tenant string
+		key    string
+		denied bool
+	}{
+		{"without key", "toko-b", "", true},
+		{"key of another tenant", "toko-b", "key-a", true},
+		{"other tenant key the other way", "toko-a", "key-b", true},
+		{"unknown key", "toko-b", "key-c", true},
+		{"own key", "toko-b", "key-b", false},
+	}
+
+	for _, route := range routes {
+		for _, tc := range cases {
+			t.Run(route.method+" "+route.path+" "+tc.name, func(t *testing.T) {
+				req := httptest.NewRequest(route.method, route.path, strings.NewReader(route.body))
+				req.Header.Set("X-Tenant-ID", tc.tenant)
+				if tc.key != "" {
+					req.Header.Set("X-Api-Key", tc.key)
+				}
+				rec := httptest.NewRecorder()
+				handler.ServeHTTP(rec, req)
+
+				denied := rec.Code == http.StatusUnauthorized
+				if denied != tc.denied {
+					t.Fatalf("status %d, want denied=%v: %s", rec.Code, tc.denied, rec.Body.String())
+				}
+			})
+		}
+	}
+}
+
+func TestSingleStoreCatalogWritesRequireKey(t *testing.T) {
+	db, err := sql.Open("empty", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	opts := appOptions{location: time.UTC, cartTTL: time.Minute, imageStore: storage.NewLocal(t.TempDir()), publicCatalog: true}
+	router := newRouter(db, services.HashAPIKey("owner-key"), opts)
+
+	cases := []struct {
+		method string
+		path   string
+		body   string
+		key    string
+		denied bool
+	}{
+		{http.MethodGet, "/api/kategori", "", "", false},
+		{http.MethodPost, "/api/kategori", `{"name":"Minuman"}`, "", true},
+		{http.MethodPost, "/api/kategori", `{"name":"Minuman"}`, "wrong-key", true},
+		{http.MethodPost, "/api/kategori", `{"name":"Minuman"}`, "owner-key", false},
+		{http.MethodPost, "/api/produk", `{"name":"Teh","price":5000}`, "", true},
+		{http.MethodGet, "/api/produk", "", "wrong-key", true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.method+" "+tc.path+" key="+tc.key, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+			if tc.key != "" {
+				req.Header.Set("X-Api-Key", tc.key)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			denied := rec.Code == http.StatusUnauthorized
+			if denied != tc.denied {
+				t.Fatalf("status %d, want denied=%v: %s", rec.Code, tc.denied, rec.Body.String())
+			}
+		})
+	}
+}
+
+// TestTenantIsolation membutuhkan Postgres dan dilewati jika TEST_DB_CONN kosong. Dua tenant
+// diprovision di schema masing-masing; produk dan transaksi tenant A tidak boleh terbaca atau
+// berubah lewat router tenant B.
+func TestTenantIsolation(t *testing.T) {
+	conn := os.Getenv("TEST_DB_CONN")
+	if conn == "" {
+		t.Skip("TEST_DB_CONN is not set")
+	}
+	db, err := database.InitDB(conn)
+	if err != nil {
+		t.Skipf("postgres unavailable: %v", err)
+	}
+	defer db.Close()
+	if err := database.MigratePlatform(db); err != nil {
+		t.Fatal(err)
+	}
+
+	tenantRepo := repositories.NewTenantRepository(db)
+	tenantService := services.NewTenantService(tenantRepo, conn, "")
+	suffix := strconv.FormatInt(time.Now().UnixNano()%1000000000, 36)
+	tenantA := &models.Tenant{Slug: "iso-a-" + suffix, Name: "Toko A"}
+	tenantB := &models.Tenant{Slug: "iso-b-" + suffix, Name: "Toko B"}
+	for _, tenant := range []*models.Tenant{tenantA, tenantB} {
+		if err := tenantService.Provision(tenant); err != nil {
+			t.Fatal(err)
+		}
+		tenant := tenant
+		t.Cleanup(func() {
+			db.Exec("DELETE FROM tenants WHERE id = $1", tenant.ID)
+			tenantRepo.DropSchema(tenant.Schema)
+		})
+	}
+
+	opts := appOptions{location: time.UTC, cartTTL: time.Minute, imageStore: storage.NewLocal(t.TempDir())}
+	routers := newTenantRouters(conn, opts)
+	defer routers.Close()
+	handler := middlewares.Tenant(tenantService.Resolve, routers.handlerFor)
+
+	do := func(tenant *models.Tenant, method string, path string, body string, ifMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("X-Tenant-ID", tenant.Slug)
+		req.Header.Set("X-Api-Key", tenant.APIKey)
+		req.Header.Set("Content-Type", "application/json")
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+	created := func(rec *httptest.ResponseRecorder) int {
+		t.Helper()
+		if rec.Code != http.StatusCreated && rec.Code != http.StatusOK {
+			t.Fatalf("status %d: %s", rec.Code, rec.Body.String())
+		}
+		var body struct {
+			ID int `json:"id"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || body.ID == 0 {
+			t.Fatalf("no id in %s", rec.Body.String())
+		}
+		return body.ID
+	}
+
+	productID := created(do(tenantA, http.MethodPost, "/api/produk", `{"name":"Teh Tenant A","price":5000,"stock":10}`, ""))
+	transactionID := created(do(tenantA, http.MethodPost, "/api/checkout", fmt.Sprintf(`{"items":[{"product_id":%d,"quantity":1}]}`, productID), ""))
+	product := fmt.Sprintf("/api/produk/%d", productID)
+	etag := do(tenantA, http.MethodGet, product, "", "").Header().Get("ETag")
+
+	cases := []struct {
+		method  string
+		path    string
+		body    string
+		ifMatch string
+	}{
+		{http.MethodGet, product, "", ""},
+		{http.MethodPut, product, `{"name":"Diubah Tenant B","price":1}`, etag},
+		{http.MethodPut, product, `{"name":"Diubah Tenant B","price":1}`, "*"},
+		{http.MethodDelete, product, "", "*"},
+		{http.MethodGet, fmt.Sprintf("/api/transaksi/%d", transactionID), "", ""},
+	}
+	for _, tc := range cases {
+		t.Run("tenant B "+tc.method+" "+tc.path, func(t *testing.T) {
+			rec := do(tenantB, tc.method, tc.path, tc.body, tc.ifMatch)
+			if rec.Code != http.StatusNotFound {
+				t.Fatalf("status %d, want 404: %s", rec.Code, rec.Body.String())
+			}
+			if strings.Contains(rec.Body.String(), "Tenant A") {
+				t.Fatalf("tenant A data leaked: %s", rec.Body.String())
+			}
+		})
+	}
+
+	// List tenant B kosong dan data tenant A tetap utuh
+	if rec := do(tenantB, http.MethodGet, "/api/produk", "", ""); strings.Contains(rec.Body.String(), "Tenant A") {
+		t.Fatalf("tenant B lists tenant A products: %s", rec.Body.String())
+	}
+	rec := do(tenantA, http.MethodGet, product, "", "")
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "Teh Tenant A") || rec.Header().Get("ETag") != etag {
+		t.Fatalf("tenant A product changed: %d %s", rec.Code, rec.Body.String())
+	}
+	if rec := do(tenantA, http.MethodGet, fmt.Sprintf("/api/transaksi/%d", transactionID), "", ""); rec.Code != http.StatusOK {
+		t.Fatalf("tenant A transaction: %d %s", rec.Code, rec.Body.String())
+	}
+}
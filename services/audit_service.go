@@ -0,0 +1,70 @@
+package services
+
+import (
+	"fmt"
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultAuditLimit = 100
+	maxAuditLimit     = 1000
+)
+
+type AuditService struct {
+	repo     *repositories.AuditRepository
+	location *time.Location
+}
+
+func NewAuditService(repo *repositories.AuditRepository, location *time.Location) *AuditService {
+	return &AuditService{repo: repo, location: location}
+}
+
+// GetAll mengambil audit log dalam rentang tanggal dengan filter opsional entity, entity_id, action dan actor.
+func (s *AuditService) GetAll(entity string, entity_id string, action string, actor string, start_date string, end_date string, limit string) ([]models.AuditEntry, error) {
+	filter := models.AuditFilter{
+		Entity: strings.TrimSpace(entity),
+		Action: strings.TrimSpace(action),
+		Actor:  strings.TrimSpace(actor),
+		Limit:  defaultAuditLimit,
+	}
+
+	if filter.Action != "" && filter.Action != models.AuditCreate && filter.Action != models.AuditUpdate && filter.Action != models.AuditDelete {
+		return nil, fmt.Errorf("%w: action must be create, update or delete", ErrInvalidQuery)
+	}
+
+	if entity_id != "" {
+		id, err := strconv.Atoi(entity_id)
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("%w: invalid entity_id %q", ErrInvalidQuery, entity_id)
+		}
+		filter.EntityID = id
+	}
+
+	if limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n <= 0 || n > maxAuditLimit {
+			return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidQuery, maxAuditLimit)
+		}
+		filter.Limit = n
+	}
+
+	start, end, err := parseDateRange(start_date, end_date, s.location)
+	if err != nil {
+		return nil, err
+	}
+	filter.Start = start
+	filter.End = end
+
+	entries, err := s.repo.GetAll(filter)
+	if err != nil {
+		return nil, err
+	}
+	for i := range entries {
+		entries[i].CreatedAt = entries[i].CreatedAt.In(s.location)
+	}
+	return entries, nil
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"fmt"
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"time"
+)
+
+type CartService struct {
+	repo           *repositories.CartRepository
+	location       *time.Location
+	loyalty        models.LoyaltyRules
+	reservationTTL time.Duration
+}
+
+func NewCartService(repo *repositories.CartRepository, location *time.Location, loyalty models.LoyaltyRules, reservationTTL time.Duration) *CartService {
+	return &CartService{repo: repo, location: location, loyalty: loyalty, reservationTTL: reservationTTL}
+}
+
+func (s *CartService) Create(outletID int, cart *models.Cart) error {
+	if err := s.repo.Create(outletID, cart, s.reservationTTL); err != nil {
+		return err
+	}
+	s.localize(cart)
+	return nil
+}
+
+func (s *CartService) GetAll(outletID int, status string) ([]models.Cart, error) {
+	if status != "" && status != models.CartOpen && status != models.CartHeld && status != models.CartCheckedOut && status != models.CartAbandoned {
+		return nil, fmt.Errorf("%w: invalid cart status %q", ErrInvalidQuery, status)
+	}
+
+	carts, err := s.repo.GetAll(outletID, status)
+	if err != nil {
+		return nil, err
+	}
+	for i := range carts {
+		s.localize(&carts[i])
+	}
+	return carts, nil
+}
+
+func (s *CartService) GetByID(outletID int, id int) (*models.Cart, error) {
+	cart, err := s.repo.GetByID(outletID, id)
+	if err != nil {
+		return nil, err
+	}
+	s.localize(cart)
+	return cart, nil
+}
+
+// AddItem menambah produk ke keranjang. Produk dengan satuan dan modifier yang sama ditambahkan ke
+// baris yang sudah ada, selain itu menjadi baris baru.
+func (s *CartService) AddItem(outletID int, cartID int, item models.CheckoutItem) (*models.Cart, error) {
+	if item.Quantity <= 0 {
+		return nil, fmt.Errorf("%w: quantity must be positive", ErrInvalidInput)
+	}
+	if err := s.repo.AddItem(outletID, cartID, item.ProductID, item.Quantity, item.Unit, item.Modifiers, s.reservationTTL); err != nil {
+		return nil, err
+	}
+	return s.GetByID(outletID, cartID)
+}
+
+// UpdateItem mengganti jumlah baris keranjang itemID, jumlah 0 menghapus baris. unit kosong
+// mempertahankan satuan baris dan modifiers nil mempertahankan modifier baris.
+func (s *CartService) UpdateItem(outletID int, cartID int, itemID int, quantity float64, unit string, modifiers []int) (*models.Cart, error) {
+	if quantity < 0 {
+		return nil, fmt.Errorf("%w: quantity must not be negative", ErrInvalidInput)
+	}
+	if err := s.repo.SetItem(outletID, cartID, itemID, quantity, unit, modifiers, s.reservationTTL); err != nil {
+		return nil, err
+	}
+	return s.GetByID(outletID, cartID)
+}
+
+func (s *CartService) RemoveItem(outletID int, cartID int, itemID int) (*models.Cart, error) {
+	return s.UpdateItem(outletID, cartID, itemID, 0, "", nil)
+}
+
+func (s *CartService) Hold(outletID int, cartID int) (*models.Cart, error) {
+	return s.setStatus(outletID, cartID, []string{models.CartOpen}, models.CartHeld)
+}
+
+func (s *CartService) Resume(outletID int, cartID int) (*models.Cart, error) {
+	return s.setStatus(outletID, cartID, []string{models.CartHeld}, models.CartOpen)
+}
+
+func (s *CartService) Abandon(outletID int, cartID int) (*models.Cart, error) {
+	return s.setStatus(outletID, cartID, []string{models.CartOpen, models.CartHeld}, models.CartAbandoned)
+}
+
+func (s *CartService) Checkout(outletID int, cartID int, opts models.CartCheckoutRequest, audit models.AuditMeta) (*models.Transaction, error) {
+	if opts.RedeemPoints < 0 {
+		return nil, fmt.Errorf("%w: redeem_points must not be negative", ErrInvalidInput)
+	}
+	if opts.CustomerPhone != "" {
+		opts.CustomerPhone = NormalizePhone(opts.CustomerPhone)
+	}
+	opts.VoucherCode = NormalizeCode(opts.VoucherCode)
+	opts.GiftCardCode = NormalizeCode(opts.GiftCardCode)
+
+	transaction, err := s.repo.Checkout(outletID, cartID, opts, s.loyalty, audit)
+	if err != nil {
+		return nil, err
+	}
+	transaction.CreatedAt = transaction.CreatedAt.In(s.location)
+	return transaction, nil
+}
+
+func (s *CartService) setStatus(outletID int, cartID int, from []string, to string) (*models.Cart, error) {
+	if err := s.repo.SetStatus(outletID, cartID, from, to, s.reservationTTL); err != nil {
+		return nil, err
+	}
+	return s.GetByID(outletID, cartID)
+}
+
+func (s *CartService) localize(cart *models.Cart) {
+	cart.CreatedAt = cart.CreatedAt.In(s.location)
+	cart.UpdatedAt = cart.UpdatedAt.In(s.location)
+	if cart.ReservedUntil != nil {
+		reservedUntil := cart.ReservedUntil.In(s.location)
+		cart.ReservedUntil = &reservedUntil
+	}
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"fmt"
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"strings"
+	"time"
+)
+
+type CustomerService struct {
+	repo     *repositories.CustomerRepository
+	location *time.Location
+	loyalty  models.LoyaltyRules
+}
+
+func NewCustomerService(repo *repositories.CustomerRepository, location *time.Location, loyalty models.LoyaltyRules) *CustomerService {
+	return &CustomerService{repo: repo, location: location, loyalty: loyalty}
+}
+
+func (s *CustomerService) GetAll(search string) ([]models.Customer, error) {
+	return s.repo.GetAll(search)
+}
+
+func (s *CustomerService) GetByID(id int) (*models.Customer, error) {
+	return s.repo.GetByID(id)
+}
+
+func (s *CustomerService) Create(customer *models.Customer, audit models.AuditMeta) error {
+	if err := s.normalize(customer); err != nil {
+		return err
+	}
+	return s.repo.Create(customer, audit)
+}
+
+func (s *CustomerService) Update(customer *models.Customer, audit models.AuditMeta) error {
+	if err := s.normalize(customer); err != nil {
+		return err
+	}
+	return s.repo.Update(customer, audit)
+}
+
+// GetTransactions mengambil riwayat belanja pelanggan.
+func (s *CustomerService) GetTransactions(id int) ([]models.Transaction, error) {
+	if _, err := s.repo.GetByID(id); err != nil {
+		return nil, err
+	}
+
+	transactions, err := s.repo.GetTransactions(id)
+	if err != nil {
+		return nil, err
+	}
+	for i := range transactions {
+		transactions[i].CreatedAt = transactions[i].CreatedAt.In(s.location)
+	}
+	return transactions, nil
+}
+
+// GetPoints mengambil saldo poin beserta buku poin (earn, redeem, expire).
+func (s *CustomerService) GetPoints(id int) (*models.LoyaltyBalance, error) {
+	balance, ledger, err := s.repo.GetLoyalty(id)
+	if err != nil {
+		return nil, err
+	}
+	return &models.LoyaltyBalance{
+		CustomerID: id,
+		Balance:    balance,
+		PointValue: s.loyalty.PointValue,
+		Ledger:     ledger,
+	}, nil
+}
+
+func (s *CustomerService) normalize(customer *models.Customer) error {
+	customer.Name = strings.TrimSpace(customer.Name)
+	customer.Email = strings.TrimSpace(customer.Email)
+
+	if customer.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidInput)
+	}
+	if customer.Email != "" && !strings.Contains(customer.Email, "@") {
+		return fmt.Errorf("%w: invalid email %q", ErrInvalidInput, customer.Email)
+	}
+	if customer.Phone != "" {
+		customer.Phone = NormalizePhone(customer.Phone)
+		if len(customer.Phone) < 8 {
+			return fmt.Errorf("%w: invalid phone number", ErrInvalidInput)
+		}
+	}
+	return nil
+}
+
+// NormalizePhone menyeragamkan nomor telepon ke format 62xxxxxxxx
+// agar 0812-3456-789, +62 812 3456 789 dan 628123456789 dianggap sama.
+func NormalizePhone(phone string) string {
+	var b strings.Builder
+	for _, r := range phone {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	digits := b.String()
+	if strings.HasPrefix(digits, "0") {
+		digits = "62" + digits[1:]
+	}
+	return digits
+}
@@ -0,0 +1,35 @@
+package services
+
+import (
+	"fmt"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+// parseDateRange mengubah start_date dan end_date (YYYY-MM-DD) menjadi rentang [start, end)
+// pada zona waktu toko. end_date bersifat inklusif. Jika salah satu kosong, rentang
+// yang dipakai adalah hari ini.
+func parseDateRange(start_date string, end_date string, location *time.Location) (time.Time, time.Time, error) {
+	if start_date == "" || end_date == "" {
+		now := time.Now().In(location)
+		start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, location)
+		return start, start.AddDate(0, 0, 1), nil
+	}
+
+	start, err := time.ParseInLocation(dateLayout, start_date, location)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: invalid start_date %q, expected YYYY-MM-DD", ErrInvalidQuery, start_date)
+	}
+
+	end, err := time.ParseInLocation(dateLayout, end_date, location)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: invalid end_date %q, expected YYYY-MM-DD", ErrInvalidQuery, end_date)
+	}
+
+	if end.Before(start) {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: end_date must not be before start_date", ErrInvalidQuery)
+	}
+
+	return start, end.AddDate(0, 0, 1), nil
+}
@@ -0,0 +1,14 @@
+package services
+
+import "kasir-api/apperror"
+
+var (
+	// ErrInvalidQuery menandai parameter query yang tidak valid (tanggal, interval, rentang)
+	ErrInvalidQuery = apperror.BadRequest("invalid query")
+
+	// ErrInvalidInput menandai data request yang tidak valid
+	ErrInvalidInput = apperror.BadRequest("invalid input")
+
+	// ErrForbidden menandai pengguna yang tidak berhak mengakses outlet atau fitur tersebut
+	ErrForbidden = apperror.Forbidden("forbidden")
+)
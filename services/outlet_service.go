@@ -0,0 +1,105 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"strconv"
+	"strings"
+)
+
+type OutletService struct {
+	repo *repositories.OutletRepository
+}
+
+func NewOutletService(repo *repositories.OutletRepository) *OutletService {
+	return &OutletService{repo: repo}
+}
+
+func (s *OutletService) GetAll() ([]models.Outlet, error) {
+	return s.repo.GetAll()
+}
+
+func (s *OutletService) GetByID(id int) (*models.Outlet, error) {
+	return s.repo.GetByID(id)
+}
+
+func (s *OutletService) Create(o *models.Outlet, audit models.AuditMeta) error {
+	if err := validateOutlet(o); err != nil {
+		return err
+	}
+	return s.repo.Create(o, audit)
+}
+
+func (s *OutletService) Update(o *models.Outlet, audit models.AuditMeta) error {
+	if err := validateOutlet(o); err != nil {
+		return err
+	}
+	return s.repo.Update(o, audit)
+}
+
+func validateOutlet(o *models.Outlet) error {
+	o.Code = NormalizeCode(o.Code)
+	o.Name = strings.TrimSpace(o.Name)
+	if o.Code == "" || o.Name == "" {
+		return fmt.Errorf("%w: code and name are required", ErrInvalidInput)
+	}
+	return nil
+}
+
+func (s *OutletService) GetProducts(outletID int) ([]models.OutletProduct, error) {
+	if _, err := s.repo.GetByID(outletID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetProducts(outletID)
+}
+
+func (s *OutletService) SetProduct(outletID int, productID int, update models.OutletProductUpdate, audit models.AuditMeta) error {
+	if update.Stock != nil && *update.Stock < 0 {
+		return fmt.Errorf("%w: stock must not be negative", ErrInvalidInput)
+	}
+	if update.PriceOverride != nil && *update.PriceOverride < 0 {
+		return fmt.Errorf("%w: price_override must not be negative", ErrInvalidInput)
+	}
+	return s.repo.SetProduct(outletID, productID, update, audit)
+}
+
+// Resolve menentukan outlet untuk request. requested berasal dari header X-Outlet-ID atau
+// query outlet_id. Tanpa pilihan, kasir dengan satu outlet memakai outlet tersebut, selain itu
+// outlet bawaan dipakai. user nil berarti endpoint publik, yang hanya boleh membaca outlet bawaan.
+func (s *OutletService) Resolve(user *models.User, requested string) (int, error) {
+	outletID := models.DefaultOutletID
+	if requested != "" {
+		id, err := strconv.Atoi(requested)
+		if err != nil || id <= 0 {
+			return 0, fmt.Errorf("%w: invalid outlet id %q", ErrInvalidInput, requested)
+		}
+		outletID = id
+	} else if user != nil && !user.IsOwner() {
+		switch len(user.OutletIDs) {
+		case 0:
+			return 0, fmt.Errorf("%w: user is not assigned to any outlet", ErrForbidden)
+		case 1:
+			outletID = user.OutletIDs[0]
+		default:
+			return 0, fmt.Errorf("%w: user is assigned to several outlets, choose one with X-Outlet-ID", ErrInvalidInput)
+		}
+	}
+
+	if user == nil && outletID != models.DefaultOutletID {
+		return 0, fmt.Errorf("%w: an API key is required to choose outlet %d", ErrForbidden, outletID)
+	}
+	if user != nil && !user.CanAccess(outletID) {
+		return 0, fmt.Errorf("%w: user is not assigned to outlet %d", ErrForbidden, outletID)
+	}
+
+	_, err := s.repo.GetByID(outletID)
+	if errors.Is(err, repositories.ErrOutletNotFound) {
+		return 0, fmt.Errorf("%w: outlet %d not found", ErrInvalidInput, outletID)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return outletID, nil
+}
@@ -0,0 +1,36 @@
+package services
+
+import (
+	"errors"
+	"kasir-api/models"
+	"testing"
+)
+
+// Kasus di bawah ditolak sebelum outlet dicari di database, jadi repository tidak diperlukan
+func TestOutletResolveRejects(t *testing.T) {
+	service := NewOutletService(nil)
+	cashier := &models.User{Name: "kasir", Role: models.RoleCashier, OutletIDs: []int{1}}
+
+	cases := []struct {
+		name      string
+		user      *models.User
+		requested string
+		want      error
+	}{
+		{"public request for another outlet", nil, "2", ErrForbidden},
+		{"cashier outside assigned outlets", cashier, "2", ErrForbidden},
+		{"cashier without outlets", &models.User{Role: models.RoleCashier}, "", ErrForbidden},
+		{"cashier with several outlets", &models.User{Role: models.RoleCashier, OutletIDs: []int{1, 2}}, "", ErrInvalidInput},
+		{"not a number", nil, "abc", ErrInvalidInput},
+		{"zero", cashier, "0", ErrInvalidInput},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := service.Resolve(tc.user, tc.requested)
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("got %v, want %v", err, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"fmt"
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"log"
+	"strings"
+	"time"
+)
+
+type PriceService struct {
+	repo     *repositories.PriceRepository
+	location *time.Location
+}
+
+func NewPriceService(repo *repositories.PriceRepository, location *time.Location) *PriceService {
+	return &PriceService{repo: repo, location: location}
+}
+
+// GetHistory mengambil riwayat harga dan harga terjadwal produk.
+func (s *PriceService) GetHistory(productID int) ([]models.ProductPrice, error) {
+	prices, err := s.repo.GetByProduct(productID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range prices {
+		prices[i].EffectiveFrom = prices[i].EffectiveFrom.In(s.location)
+		prices[i].CreatedAt = prices[i].CreatedAt.In(s.location)
+		if prices[i].AppliedAt != nil {
+			applied := prices[i].AppliedAt.In(s.location)
+			prices[i].AppliedAt = &applied
+		}
+	}
+	return prices, nil
+}
+
+// Schedule menjadwalkan harga katalog baru. Waktu berlaku harus di masa depan;
+// perubahan yang langsung berlaku dilakukan lewat PUT /api/produk/{id}.
+func (s *PriceService) Schedule(productID int, req models.SchedulePriceRequest, audit models.AuditMeta) (*models.ProductPrice, error) {
+	if req.Price < 0 {
+		return nil, fmt.Errorf("%w: price must not be negative", ErrInvalidInput)
+	}
+
+	effectiveFrom, err := s.parseEffectiveFrom(req.EffectiveFrom)
+	if err != nil {
+		return nil, err
+	}
+	if !effectiveFrom.After(time.Now()) {
+		return nil, fmt.Errorf("%w: effective_from must be in the future", ErrInvalidInput)
+	}
+
+	price := &models.ProductPrice{
+		ProductID:     productID,
+		Price:         req.Price,
+		EffectiveFrom: effectiveFrom,
+		Note:          strings.TrimSpace(req.Note),
+	}
+	if err := s.repo.Schedule(price, audit); err != nil {
+		return nil, err
+	}
+	price.CreatedAt = price.CreatedAt.In(s.location)
+	return price, nil
+}
+
+// parseEffectiveFrom menerima YYYY-MM-DD (awal hari di zona waktu toko) atau RFC3339.
+func (s *PriceService) parseEffectiveFrom(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if t, err := time.ParseInLocation(dateLayout, value, s.location); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.In(s.location), nil
+	}
+	return time.Time{}, fmt.Errorf("%w: effective_from must be YYYY-MM-DD or RFC3339", ErrInvalidInput)
+}
+
+// Cancel membatalkan harga terjadwal yang belum berlaku.
+func (s *PriceService) Cancel(productID int, priceID int, audit models.AuditMeta) error {
+	return s.repo.Cancel(productID, priceID, audit)
+}
+
+// RunScheduler memberlakukan harga terjadwal yang jatuh tempo setiap interval sampai stop ditutup.
+func (s *PriceService) RunScheduler(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		changed, err := s.repo.ApplyDue(time.Now(), models.AuditMeta{Actor: "scheduler"})
+		if err != nil {
+			log.Println("Gagal memberlakukan harga terjadwal:", err.Error())
+		} else if changed > 0 {
+			log.Println("Harga terjadwal diberlakukan untuk", changed, "produk")
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"fmt"
+	"kasir-api/models"
+	"kasir-api/validation"
+	"math"
+	"strings"
+)
+
+// BulkUpdate menerapkan satu operasi (harga, stok atau kategori) ke banyak produk sekaligus dalam
+// satu transaksi. Pada preview perubahan dihitung lalu dibatalkan, sehingga hasilnya sama persis
+// dengan yang akan disimpan.
+func (s *ProductService) BulkUpdate(outletID int, req models.BulkProductRequest, preview bool, audit models.AuditMeta) (*models.BulkProductResult, error) {
+	if err := s.validateBulk(&req); err != nil {
+		return nil, err
+	}
+
+	note := "ubah massal"
+	if req.Note = strings.TrimSpace(req.Note); req.Note != "" {
+		note += ": " + req.Note
+	}
+
+	op := req.Operation
+	changes := make([]models.BulkProductChange, 0)
+	matched, err := s.repo.Bulk(outletID, req.Target, note, preview, audit, func(p *models.Product) error {
+		change := models.BulkProductChange{ProductID: p.ID, Name: p.Name}
+		switch op.Type {
+		case models.BulkSetPrice, models.BulkAdjustPricePercent, models.BulkAdjustPriceAmount:
+			change.Field = "price"
+			change.Before = p.Price
+			switch op.Type {
+			case models.BulkSetPrice:
+				p.Price = op.Value
+			case models.BulkAdjustPricePercent:
+				p.Price = math.Round(p.Price * (100 + op.Value) / 100)
+			default:
+				p.Price += op.Value
+			}
+			change.After = p.Price
+		case models.BulkSetStock, models.BulkAdjustStock:
+			// Stok paket dan resep dihitung dari komponennya
+			if p.IsComposite() {
+				return nil
+			}
+			change.Field = "stock"
+			change.Before = p.Stock
+			if op.Type == models.BulkSetStock {
+				p.Stock = op.Value
+			} else {
+				p.Stock = models.RoundQuantity(p.Stock + op.Value)
+			}
+			change.After = p.Stock
+			if !models.ValidQuantity(p.Unit, p.Stock) {
+				v := validation.New()
+				v.Add("operation.value", validation.CodeInvalid, fmt.Sprintf("stock of product id %d (%s) must be a whole number of %s", p.ID, p.Name, p.Unit))
+				return v.Err()
+			}
+		case models.BulkSetCategory:
+			change.Field = "category_id"
+			change.Before = float64(p.CategoryID)
+			p.CategoryID = int(op.Value)
+			change.After = float64(p.CategoryID)
+		}
+
+		if change.After < 0 {
+			v := validation.New()
+			v.Add("operation.value", validation.CodeMin, fmt.Sprintf("%s of product id %d (%s) would become %v", change.Field, p.ID, p.Name, change.After))
+			return v.Err()
+		}
+		if change.After != change.Before {
+			changes = append(changes, change)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.BulkProductResult{Preview: preview, Matched: matched, Changed: len(changes), Changes: changes}, nil
+}
+
+func (s *ProductService) validateBulk(req *models.BulkProductRequest) error {
+	v := validation.New()
+
+	target := &req.Target
+	target.Name = strings.TrimSpace(target.Name)
+	if len(target.IDs) == 0 && target.CategoryID == nil && target.Name == "" && !target.All {
+		v.Add("target", validation.CodeRequired, "target needs ids, category_id or name, or all: true for every product")
+	}
+	for i, id := range target.IDs {
+		v.Check(id > 0, fmt.Sprintf("target.ids[%d]", i), validation.CodeInvalid, "product id must be greater than 0")
+	}
+	if target.CategoryID != nil {
+		v.NotNegative("target.category_id", float64(*target.CategoryID))
+	}
+
+	op := req.Operation
+	whole := op.Value == math.Trunc(op.Value)
+	switch op.Type {
+	case models.BulkSetPrice:
+		v.NotNegative("operation.value", op.Value)
+		v.Check(whole, "operation.value", validation.CodeInvalid, "price must be a whole rupiah amount")
+	case models.BulkAdjustPricePercent:
+		v.Check(op.Value >= -100, "operation.value", validation.CodeMin, "percent must not be less than -100")
+		v.Check(op.Value != 0, "operation.value", validation.CodeInvalid, "percent must not be 0")
+	case models.BulkAdjustPriceAmount:
+		v.Check(whole, "operation.value", validation.CodeInvalid, "value must be a whole number")
+		v.Check(op.Value != 0, "operation.value", validation.CodeInvalid, "value must not be 0")
+	case models.BulkAdjustStock:
+		v.Check(models.ValidQuantity("", op.Value), "operation.value", validation.CodeInvalid, fmt.Sprintf("value must have at most %d decimals", models.QuantityDecimals))
+		v.Check(op.Value != 0, "operation.value", validation.CodeInvalid, "value must not be 0")
+	case models.BulkSetStock:
+		v.NotNegative("operation.value", op.Value)
+		v.Check(models.ValidQuantity("", op.Value), "operation.value", validation.CodeInvalid, fmt.Sprintf("stock must have at most %d decimals", models.QuantityDecimals))
+	case models.BulkSetCategory:
+		v.NotNegative("operation.value", op.Value)
+		v.Check(whole, "operation.value", validation.CodeInvalid, "category id must be a whole number")
+		if whole && op.Value > 0 {
+			exists, err := s.repo.CategoryExists(int(op.Value))
+			if err != nil {
+				return err
+			}
+			v.Check(exists, "operation.value", validation.CodeNotFound, fmt.Sprintf("category %d not found", int(op.Value)))
+		}
+	default:
+		v.Add("operation.type", validation.CodeInvalid, "type must be one of set_price, adjust_price_percent, adjust_price_amount, set_stock, adjust_stock, set_category")
+	}
+	return v.Err()
+}
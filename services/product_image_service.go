@@ -0,0 +1,91 @@
+package services
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"kasir-api/images"
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"kasir-api/storage"
+	"log"
+)
+
+type ProductImageService struct {
+	repo  *repositories.ProductRepository
+	store storage.Storage
+}
+
+func NewProductImageService(repo *repositories.ProductRepository, store storage.Storage) *ProductImageService {
+	return &ProductImageService{repo: repo, store: store}
+}
+
+// Upload memeriksa foto, membuat thumbnail, menyimpan semuanya di storage lalu memasang foto ke produk.
+// Setiap unggahan mendapat key acak, sehingga URL lama tetap bisa di-cache selamanya dan
+// foto lama baru dihapus setelah produk menunjuk ke foto baru.
+func (s *ProductImageService) Upload(outletID int, id int, r io.Reader, audit models.AuditMeta) (*models.Product, error) {
+	data, err := io.ReadAll(io.LimitReader(r, images.MaxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	set, err := images.Process(data)
+	if err != nil {
+		return nil, err
+	}
+
+	key := fmt.Sprintf("produk/%d/%s%s", id, randomImageName(), set.Ext)
+	if err := s.store.Put(key, bytes.NewReader(set.Original.Data), set.Original.ContentType); err != nil {
+		return nil, err
+	}
+	for _, size := range images.Sizes {
+		thumb := set.Thumbnails[size.Name]
+		if err := s.store.Put(images.ThumbnailKey(key, size.Name), bytes.NewReader(thumb.Data), thumb.ContentType); err != nil {
+			s.deleteFiles(key)
+			return nil, err
+		}
+	}
+
+	oldKey, product, err := s.repo.SetImage(outletID, id, key, audit)
+	if err != nil {
+		s.deleteFiles(key)
+		return nil, err
+	}
+	s.deleteFiles(oldKey)
+	return product, nil
+}
+
+// Delete melepas foto dari produk lalu menghapus file dan thumbnail-nya.
+func (s *ProductImageService) Delete(outletID int, id int, audit models.AuditMeta) (*models.Product, error) {
+	oldKey, product, err := s.repo.SetImage(outletID, id, "", audit)
+	if err != nil {
+		return nil, err
+	}
+	s.deleteFiles(oldKey)
+	return product, nil
+}
+
+// Open membuka file gambar (asli atau thumbnail) berdasarkan key dari URL.
+func (s *ProductImageService) Open(key string) (*storage.Object, error) {
+	return s.store.Get(key)
+}
+
+// deleteFiles menghapus file asli dan thumbnail. Kegagalan hanya dicatat di log karena
+// produk sudah tidak menunjuk ke file tersebut.
+func (s *ProductImageService) deleteFiles(key string) {
+	if key == "" {
+		return
+	}
+	for _, k := range images.Keys(key) {
+		if err := s.store.Delete(k); err != nil {
+			log.Println("Gagal menghapus gambar", k+":", err)
+		}
+	}
+}
+
+func randomImageName() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
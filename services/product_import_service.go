@@ -0,0 +1,257 @@
+package services
+
+import (
+	"fmt"
+	"io"
+	"kasir-api/apperror"
+	"kasir-api/imports"
+	"kasir-api/models"
+	"kasir-api/validation"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// importChunkSize adalah jumlah baris per transaksi saat impor. Kegagalan satu baris hanya
+// membatalkan chunk-nya, chunk sebelumnya tetap tersimpan.
+const importChunkSize = 100
+
+// Field impor produk
+const (
+	importSKU      = "sku"
+	importName     = "name"
+	importPrice    = "price"
+	importStock    = "stock"
+	importCategory = "category"
+)
+
+// productImportAliases memetakan judul kolom yang umum (Indonesia dan Inggris) ke field impor.
+var productImportAliases = map[string]string{
+	"sku":           importSKU,
+	"kode":          importSKU,
+	"kode barang":   importSKU,
+	"kode produk":   importSKU,
+	"name":          importName,
+	"nama":          importName,
+	"nama barang":   importName,
+	"nama produk":   importName,
+	"product name":  importName,
+	"price":         importPrice,
+	"harga":         importPrice,
+	"harga jual":    importPrice,
+	"stock":         importStock,
+	"stok":          importStock,
+	"category":      importCategory,
+	"kategori":      importCategory,
+	"category name": importCategory,
+	"nama kategori": importCategory,
+}
+
+// thousandsPattern mengenali harga dengan pemisah ribuan seperti 12.000 atau 1,250,000
+var thousandsPattern = regexp.MustCompile(`^\d{1,3}([.,]\d{3})+$`)
+
+// quantityPattern mengenali stok berupa angka desimal biasa dengan titik atau koma sebagai pemisah desimal
+var quantityPattern = regexp.MustCompile(`^-?\d+([.,]\d+)?$`)
+
+// ImportProducts membaca file CSV/XLSX lalu membuat atau memperbarui produk berdasarkan SKU.
+// columns menambah pemetaan judul kolom ke field (sku, name, price, stock, category) selain judul
+// bawaan. Pada dryRun tidak ada yang disimpan, hanya laporan validasi per baris.
+func (s *ProductService) ImportProducts(outletID int, file io.Reader, format string, columns map[string]string, dryRun bool, audit models.AuditMeta) (*models.ProductImportReport, error) {
+	aliases := make(map[string]string, len(productImportAliases)+len(columns))
+	for title, field := range productImportAliases {
+		aliases[title] = field
+	}
+	for title, field := range columns {
+		switch field {
+		case importSKU, importName, importPrice, importStock, importCategory:
+		default:
+			return nil, fmt.Errorf("%w: unknown import field %q, expected sku, name, price, stock or category", ErrInvalidInput, field)
+		}
+		aliases[strings.Join(strings.Fields(strings.ToLower(title)), " ")] = field
+	}
+
+	sheet, err := imports.Read(file, format, aliases)
+	if err != nil {
+		return nil, err
+	}
+	missing := make([]string, 0)
+	for _, field := range []string{importSKU, importName, importPrice} {
+		if !sheet.Has(field) {
+			missing = append(missing, field)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: missing column for %s", imports.ErrInvalidFile, strings.Join(missing, ", "))
+	}
+
+	report := &models.ProductImportReport{DryRun: dryRun, Total: len(sheet.Records), Rows: make([]models.ProductImportResult, 0, len(sheet.Records))}
+	valid := make([]models.ProductImportRow, 0, len(sheet.Records))
+	seen := make(map[string]int)
+	skus := make([]string, 0, len(sheet.Records))
+	for _, record := range sheet.Records {
+		row, v := parseImportRecord(record)
+		if first, ok := seen[row.SKU]; ok && row.SKU != "" {
+			v.Add(importSKU, validation.CodeDuplicate, fmt.Sprintf("sku %s already used in row %d", row.SKU, first))
+		}
+		if !v.Valid() {
+			report.Rows = append(report.Rows, models.ProductImportResult{Row: record.Row, SKU: row.SKU, Action: models.ImportFailed, Errors: v.Errors()})
+			continue
+		}
+		seen[row.SKU] = record.Row
+		valid = append(valid, row)
+		skus = append(skus, row.SKU)
+	}
+
+	existing, err := s.repo.ExistingSKUs(skus)
+	if err != nil {
+		return nil, err
+	}
+	// Stok ditulis dalam satuan dasar produk; produk baru memakai pcs. Paket dan resep tidak
+	// punya stok sendiri.
+	checked := valid[:0]
+	for _, row := range valid {
+		p, ok := existing[row.SKU]
+		unit := models.UnitPcs
+		if ok {
+			unit = p.Unit
+		}
+		if row.Stock != nil && ok && p.IsComposite() {
+			report.Rows = append(report.Rows, models.ProductImportResult{Row: row.Row, SKU: row.SKU, Action: models.ImportFailed, Errors: []models.FieldError{{
+				Field:   importStock,
+				Code:    validation.CodeInvalid,
+				Message: fmt.Sprintf("stock of a %s follows its components and cannot be imported", p.Type),
+			}}})
+			continue
+		}
+		if row.Stock != nil && !models.ValidQuantity(unit, *row.Stock) {
+			report.Rows = append(report.Rows, models.ProductImportResult{Row: row.Row, SKU: row.SKU, Action: models.ImportFailed, Errors: []models.FieldError{{
+				Field:   importStock,
+				Code:    validation.CodeInvalid,
+				Message: fmt.Sprintf("stock %s must be a whole number of %s", models.FormatQuantity(*row.Stock), unit),
+			}}})
+			continue
+		}
+		checked = append(checked, row)
+	}
+	valid = checked
+
+	if dryRun {
+		for _, row := range valid {
+			result := models.ProductImportResult{Row: row.Row, SKU: row.SKU, Action: models.ImportCreate}
+			if p, ok := existing[row.SKU]; ok {
+				result.Action = models.ImportUpdate
+				result.ProductID = p.ID
+			}
+			report.Rows = append(report.Rows, result)
+		}
+	} else {
+		for start := 0; start < len(valid); start += importChunkSize {
+			chunk := valid[start:min(start+importChunkSize, len(valid))]
+			results, err := s.repo.Import(outletID, chunk, audit)
+			if err != nil {
+				report.Rows = append(report.Rows, failedChunk(chunk, err)...)
+				continue
+			}
+			report.Rows = append(report.Rows, results...)
+		}
+	}
+
+	sort.Slice(report.Rows, func(i, j int) bool { return report.Rows[i].Row < report.Rows[j].Row })
+	for _, result := range report.Rows {
+		switch result.Action {
+		case models.ImportCreate:
+			report.Created++
+		case models.ImportUpdate:
+			report.Updated++
+		default:
+			report.Failed++
+		}
+	}
+	return report, nil
+}
+
+// parseImportRecord mengubah satu baris file menjadi ProductImportRow dan memeriksanya
+// dengan aturan yang sama seperti membuat produk.
+func parseImportRecord(record imports.Record) (models.ProductImportRow, *validation.Validator) {
+	row := models.ProductImportRow{
+		Row:      record.Row,
+		SKU:      record.Values[importSKU],
+		Name:     record.Values[importName],
+		Category: record.Values[importCategory],
+	}
+
+	v := validation.New()
+	v.Required(importSKU, row.SKU)
+
+	price, priceErr := parseImportPrice(record.Values[importPrice])
+	if record.Values[importPrice] == "" {
+		v.Add(importPrice, validation.CodeRequired, "price is required")
+	} else if priceErr != nil || price != float64(int(price)) {
+		v.Add(importPrice, validation.CodeInvalid, fmt.Sprintf("price %q is not a whole rupiah amount", record.Values[importPrice]))
+	}
+	row.Price = price
+
+	// Kecocokan stok dengan satuan produk diperiksa setelah produk lama diketahui
+	product := models.Product{SKU: row.SKU, Name: row.Name, Price: row.Price}
+	if value := record.Values[importStock]; value != "" {
+		stock, err := parseImportQuantity(value)
+		if err != nil || !models.ValidQuantity("", stock) {
+			v.Add(importStock, validation.CodeInvalid, fmt.Sprintf("stock %q is not a number with at most %d decimals and no thousands separator", value, models.QuantityDecimals))
+		} else {
+			product.Stock = stock
+			row.Stock = &product.Stock
+		}
+	}
+
+	for _, fe := range validation.Product(&product).Errors() {
+		v.Add(fe.Field, fe.Code, fe.Message)
+	}
+	return row, v
+}
+
+// parseImportPrice menerima harga berupa angka biasa (12000, 12000.50) dan angka dengan pemisah ribuan
+// (12.000, 1,250,000), dengan atau tanpa awalan "Rp".
+func parseImportPrice(value string) (float64, error) {
+	value = strings.TrimSpace(value)
+	value = strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(value, "Rp"), "rp"))
+	value = strings.ReplaceAll(value, " ", "")
+	if thousandsPattern.MatchString(value) {
+		value = strings.NewReplacer(".", "", ",", "").Replace(value)
+	}
+	return strconv.ParseFloat(value, 64)
+}
+
+// parseImportQuantity menerima stok berupa angka desimal biasa, dengan titik atau koma sebagai pemisah
+// desimal. Pemisah ribuan tidak dikenali karena 2.500 untuk produk kg berarti 2,5 kg, bukan 2500 kg.
+func parseImportQuantity(value string) (float64, error) {
+	value = strings.TrimSpace(value)
+	if !quantityPattern.MatchString(value) {
+		return 0, fmt.Errorf("invalid quantity %q", value)
+	}
+	return strconv.ParseFloat(strings.Replace(value, ",", ".", 1), 64)
+}
+
+// failedChunk menandai semua baris chunk yang batal disimpan.
+func failedChunk(chunk []models.ProductImportRow, err error) []models.ProductImportResult {
+	message := err.Error()
+	if apperror.CodeOf(err) == apperror.CodeInternal {
+		log.Println("Gagal mengimpor produk:", err.Error())
+		message = "internal error"
+	}
+
+	results := make([]models.ProductImportResult, len(chunk))
+	for i, row := range chunk {
+		results[i] = models.ProductImportResult{
+			Row:    row.Row,
+			SKU:    row.SKU,
+			Action: models.ImportFailed,
+			Errors: []models.FieldError{{
+				Code:    string(apperror.CodeOf(err)),
+				Message: fmt.Sprintf("rows %d-%d were not saved: %s", chunk[0].Row, chunk[len(chunk)-1].Row, message),
+			}},
+		}
+	}
+	return results
+}
@@ -0,0 +1,64 @@
+package services
+
+import "testing"
+
+func TestParseImportPrice(t *testing.T) {
+	cases := []struct {
+		in   string
+		want float64
+		ok   bool
+	}{
+		{"12000", 12000, true},
+		{"12.000", 12000, true},
+		{"1,250,000", 1250000, true},
+		{"Rp 15.500", 15500, true},
+		{"rp12000", 12000, true},
+		{"12000.50", 12000.5, true},
+		{"dua ribu", 0, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.in, func(t *testing.T) {
+			got, err := parseImportPrice(tc.in)
+			if (err == nil) != tc.ok {
+				t.Fatalf("parseImportPrice(%q) error = %v, want ok=%v", tc.in, err, tc.ok)
+			}
+			if tc.ok && got != tc.want {
+				t.Fatalf("parseImportPrice(%q) = %v, want %v", tc.in, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseImportQuantity(t *testing.T) {
+	cases := []struct {
+		in   string
+		want float64
+		ok   bool
+	}{
+		{"12", 12, true},
+		{" 7 ", 7, true},
+		{"0.500", 0.5, true},
+		{"2.500", 2.5, true},
+		{"2,500", 2.5, true},
+		{"0,25", 0.25, true},
+		{"-3", -3, true},
+		{"1.250.000", 0, false},
+		{"1.250,5", 0, false},
+		{"1e3", 0, false},
+		{"Rp 5", 0, false},
+		{"", 0, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.in, func(t *testing.T) {
+			got, err := parseImportQuantity(tc.in)
+			if (err == nil) != tc.ok {
+				t.Fatalf("parseImportQuantity(%q) error = %v, want ok=%v", tc.in, err, tc.ok)
+			}
+			if tc.ok && got != tc.want {
+				t.Fatalf("parseImportQuantity(%q) = %v, want %v", tc.in, got, tc.want)
+			}
+		})
+	}
+}
@@ -1,8 +1,16 @@
 package services
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
 	"kasir-api/models"
+	"kasir-api/patch"
 	"kasir-api/repositories"
+	"kasir-api/validation"
+	"sort"
+	"strings"
+	"time"
 )
 
 type ProductService struct {
@@ -13,34 +21,289 @@ func NewProductService(repo *repositories.ProductRepository) *ProductService {
 	return &ProductService{repo: repo}
 }
 
-func (s *ProductService) GetAll(name string) ([]models.Product, error) {
-	return s.repo.GetAll(name)
+func (s *ProductService) GetAll(outletID int, name string) ([]models.Product, error) {
+	return s.repo.GetAll(outletID, name)
 }
 
-func (s *ProductService) GetAllDetails(name string) ([]models.Product, error) {
-	return s.repo.GetAllDetails(name)
+func (s *ProductService) GetAllDetails(outletID int, name string) ([]models.Product, error) {
+	return s.repo.GetAllDetails(outletID, name)
 }
 
-func (s *ProductService) Create(data *models.Product) error {
-	return s.repo.Create(data)
+func (s *ProductService) Create(outletID int, data *models.Product, audit models.AuditMeta) error {
+	if err := s.validate(data); err != nil {
+		return err
+	}
+	return s.repo.Create(outletID, data, audit)
 }
 
-func (s *ProductService) GetByID(id int) (*models.Product, error) {
-	return s.repo.GetByID(id)
+func (s *ProductService) GetByID(outletID int, id int) (*models.Product, error) {
+	return s.repo.GetByID(outletID, id)
 }
 
-func (s *ProductService) GetDetailsByID(id int) (*models.Product, error) {
-	return s.repo.GetDetailsByID(id)
+func (s *ProductService) GetDetailsByID(outletID int, id int) (*models.Product, error) {
+	return s.repo.GetDetailsByID(outletID, id)
 }
 
-func (s *ProductService) Update(product *models.Product) error {
-	return s.repo.Update(product)
+// Update dan Delete hanya berjalan jika ifMatch masih sama dengan ETag produk saat ini.
+// Satuan kosong dan units yang tidak dikirim mempertahankan satuan produk saat ini.
+func (s *ProductService) Update(outletID int, product *models.Product, ifMatch string, audit models.AuditMeta) error {
+	updated, err := s.repo.Patch(outletID, product.ID, ifMatch, audit, func(current *models.Product) error {
+		current.SKU = product.SKU
+		current.Name = product.Name
+		current.Price = product.Price
+		current.Stock = product.Stock
+		current.CategoryID = product.CategoryID
+		if product.Unit != "" {
+			current.Unit = product.Unit
+		}
+		if product.Units != nil {
+			current.Units = product.Units
+		}
+		if product.Type != "" {
+			current.Type = product.Type
+		}
+		if product.Components != nil || !current.IsComposite() {
+			current.Components = product.Components
+		}
+		if product.ModifierGroups != nil {
+			current.ModifierGroups = product.ModifierGroups
+		}
+		return s.validate(current)
+	})
+	if err != nil {
+		return err
+	}
+	product.SKU = updated.SKU
+	product.Name = updated.Name
+	product.Unit = updated.Unit
+	product.Units = updated.Units
+	product.Type = updated.Type
+	product.Components = updated.Components
+	product.ModifierGroups = updated.ModifierGroups
+	product.Version = updated.Version
+	product.StockVersion = updated.StockVersion
+	return nil
 }
 
-func (s *ProductService) Delete(id int) error {
-	return s.repo.Delete(id)
+// productPatchDocument adalah bentuk JSON produk yang bisa diubah lewat PATCH. Field yang tidak
+// ada di sini (id, version, category_name) ditolak agar patch tidak diam-diam diabaikan.
+type productPatchDocument struct {
+	SKU            *string                   `json:"sku,omitempty"`
+	Name           *string                   `json:"name"`
+	Price          *float64                  `json:"price"`
+	Stock          *float64                  `json:"stock"`
+	Unit           *string                   `json:"unit"`
+	Units          []models.ProductUnit      `json:"units,omitempty"`
+	Type           *string                   `json:"type"`
+	Components     []models.ProductComponent `json:"components,omitempty"`
+	ModifierGroups []models.ModifierGroup    `json:"modifier_groups,omitempty"`
+	CategoryID     *int                      `json:"category_id"`
+}
+
+// Patch menerapkan JSON Merge Patch (RFC 7396) atau JSON Patch (RFC 6902) sesuai contentType
+// pada produk, sehingga hanya field yang dikirim yang berubah. Hasilnya divalidasi sama seperti
+// saat membuat produk.
+func (s *ProductService) Patch(outletID int, id int, contentType string, body []byte, ifMatch string, audit models.AuditMeta) (*models.Product, error) {
+	var applyPatch func(doc []byte, patch []byte) ([]byte, error)
+	switch contentType {
+	case patch.ContentTypeMergePatch:
+		applyPatch = patch.MergePatch
+	case patch.ContentTypeJSONPatch:
+		applyPatch = patch.Apply
+	default:
+		return nil, fmt.Errorf("%w: unsupported patch type %q", patch.ErrInvalidPatch, contentType)
+	}
+
+	return s.repo.Patch(outletID, id, ifMatch, audit, func(current *models.Product) error {
+		doc := productPatchDocument{
+			Name:           &current.Name,
+			Price:          &current.Price,
+			Stock:          &current.Stock,
+			Unit:           &current.Unit,
+			Units:          current.Units,
+			Type:           &current.Type,
+			Components:     patchComponents(current.Components),
+			ModifierGroups: current.ModifierGroups,
+		}
+		if current.SKU != "" {
+			doc.SKU = &current.SKU
+		}
+		if current.CategoryID != 0 {
+			doc.CategoryID = &current.CategoryID
+		}
+		original, err := json.Marshal(doc)
+		if err != nil {
+			return err
+		}
+
+		patched, err := applyPatch(original, body)
+		if err != nil {
+			return err
+		}
+
+		var result productPatchDocument
+		decoder := json.NewDecoder(bytes.NewReader(patched))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&result); err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidInput, err.Error())
+		}
+		v := validation.New()
+		v.Check(result.Name != nil, "name", validation.CodeRequired, "name cannot be removed")
+		v.Check(result.Price != nil, "price", validation.CodeRequired, "price cannot be removed")
+		v.Check(result.Stock != nil, "stock", validation.CodeRequired, "stock cannot be removed")
+		v.Check(result.Unit != nil, "unit", validation.CodeRequired, "unit cannot be removed")
+		if !v.Valid() {
+			return v.Err()
+		}
+
+		current.SKU = ""
+		if result.SKU != nil {
+			current.SKU = *result.SKU
+		}
+		current.Name = *result.Name
+		current.Price = *result.Price
+		current.Stock = *result.Stock
+		current.Unit = *result.Unit
+		current.Units = result.Units
+		current.Type = models.ProductStandard
+		if result.Type != nil {
+			current.Type = *result.Type
+		}
+		current.Components = result.Components
+		current.ModifierGroups = result.ModifierGroups
+		current.CategoryID = 0
+		if result.CategoryID != nil {
+			current.CategoryID = *result.CategoryID
+		}
+		return s.validate(current)
+	})
+}
+
+// patchComponents hanya menyisakan product_id dan quantity, field yang bisa diubah lewat PATCH.
+func patchComponents(components []models.ProductComponent) []models.ProductComponent {
+	if components == nil {
+		return nil
+	}
+	result := make([]models.ProductComponent, len(components))
+	for i, c := range components {
+		result[i] = models.ProductComponent{ProductID: c.ProductID, Quantity: c.Quantity}
+	}
+	return result
+}
+
+func (s *ProductService) Delete(outletID int, id int, ifMatch string, audit models.AuditMeta) error {
+	return s.repo.Delete(outletID, id, ifMatch, audit)
+}
+
+// GetDeleted mengambil produk yang sudah dihapus (soft delete).
+func (s *ProductService) GetDeleted(outletID int) ([]models.Product, error) {
+	return s.repo.GetDeleted(outletID)
+}
+
+// Restore memulihkan produk yang dihapus dan mengembalikan datanya di outlet tersebut.
+func (s *ProductService) Restore(outletID int, id int, audit models.AuditMeta) (*models.Product, error) {
+	if err := s.repo.Restore(outletID, id, audit); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(outletID, id)
 }
 
 func (s *ProductService) GetCategories() ([]models.Categories, error) {
 	return s.repo.GetCategories()
 }
+
+// CreateCategory menambahkan kategori baru.
+func (s *ProductService) CreateCategory(category *models.Categories, audit models.AuditMeta) error {
+	category.Name = strings.TrimSpace(category.Name)
+	if err := validation.Category(category).Err(); err != nil {
+		return err
+	}
+	return s.repo.CreateCategory(category, audit)
+}
+
+// ExportCatalog mengambil seluruh kategori dan produk aktif dengan harga dan stok outlet,
+// diurutkan berdasarkan ID agar hasil ekspor stabil.
+func (s *ProductService) ExportCatalog(outletID int) (*models.Catalog, error) {
+	categories, err := s.repo.GetCategories()
+	if err != nil {
+		return nil, err
+	}
+	products, err := s.repo.GetAllDetails(outletID, "")
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(categories, func(i, j int) bool { return categories[i].ID < categories[j].ID })
+	sort.Slice(products, func(i, j int) bool { return products[i].ID < products[j].ID })
+
+	return &models.Catalog{
+		ExportedAt: time.Now(),
+		OutletID:   outletID,
+		Categories: categories,
+		Products:   products,
+	}, nil
+}
+
+// validate memeriksa data produk sebelum disimpan: nama wajib diisi, harga dan stok tidak
+// negatif, dan kategori (jika diisi) harus ada. Komponen paket dan resep harus produk biasa yang
+// aktif dengan jumlah sesuai satuannya. Kesalahan dikembalikan sebagai validation.Errors.
+func (s *ProductService) validate(product *models.Product) error {
+	product.Name = strings.TrimSpace(product.Name)
+	product.SKU = strings.TrimSpace(product.SKU)
+	if product.Unit == "" {
+		product.Unit = models.UnitPcs
+	}
+	if product.Type == "" {
+		product.Type = models.ProductStandard
+	}
+	for i := range product.ModifierGroups {
+		g := &product.ModifierGroups[i]
+		g.Name = strings.TrimSpace(g.Name)
+		for j := range g.Modifiers {
+			g.Modifiers[j].Name = strings.TrimSpace(g.Modifiers[j].Name)
+		}
+	}
+	v := validation.Product(product)
+	if product.CategoryID > 0 {
+		exists, err := s.repo.CategoryExists(product.CategoryID)
+		if err != nil {
+			return err
+		}
+		v.Check(exists, "category_id", validation.CodeNotFound, fmt.Sprintf("category %d not found", product.CategoryID))
+	}
+
+	if len(product.Components) > 0 {
+		ids := make([]int, len(product.Components))
+		for i, c := range product.Components {
+			ids[i] = c.ProductID
+		}
+		components, err := s.repo.GetComponents(ids)
+		if err != nil {
+			return err
+		}
+		for i, c := range product.Components {
+			field := validation.Index("components", i, "product_id")
+			component, ok := components[c.ProductID]
+			switch {
+			case c.ProductID <= 0 || c.ProductID == product.ID:
+				// sudah dilaporkan validation.Product
+			case !ok:
+				v.Add(field, validation.CodeNotFound, fmt.Sprintf("product %d not found", c.ProductID))
+			case component.IsComposite():
+				v.Add(field, validation.CodeInvalid, fmt.Sprintf("product %d is a %s and cannot be a component", c.ProductID, component.Type))
+			case !models.ValidQuantity(component.Unit, c.Quantity):
+				v.Add(validation.Index("components", i, "quantity"), validation.CodeInvalid,
+					fmt.Sprintf("quantity of %s must be a whole number of %s", component.Name, component.Unit))
+			}
+		}
+	}
+
+	if product.ID > 0 && product.IsComposite() {
+		used, err := s.repo.UsedAsComponent(product.ID)
+		if err != nil {
+			return err
+		}
+		v.Check(!used, "type", validation.CodeInvalid, "product is a component of a bundle or recipe and cannot become one")
+	}
+	return v.Err()
+}
@@ -1,18 +1,312 @@
 package services
 
 import (
+	"fmt"
 	"kasir-api/models"
 	"kasir-api/repositories"
+	"sort"
+	"time"
 )
 
+// Batas jumlah bucket agar satu request tidak menghasilkan deret yang terlalu besar
+const maxTimeSeriesBuckets = 10000
+
 type ReportService struct {
-	repo *repositories.ReportRepository
+	repo     *repositories.ReportRepository
+	location *time.Location
+}
+
+func NewReportService(repo *repositories.ReportRepository, location *time.Location) *ReportService {
+	return &ReportService{repo: repo, location: location}
+}
+
+func (s *ReportService) GetReport(outletID int, start_date string, end_date string) ([]models.Report, error) {
+	start, end, err := parseDateRange(start_date, end_date, s.location)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetReport(outletID, start, end)
+}
+
+// GetTimeSeries mengembalikan deret waktu yang siap dipakai untuk grafik:
+// setiap bucket dalam rentang selalu ada, bucket tanpa transaksi bernilai nol.
+func (s *ReportService) GetTimeSeries(outletID int, start_date string, end_date string, interval string) (*models.TimeSeriesReport, error) {
+	start, end, err := parseDateRange(start_date, end_date, s.location)
+	if err != nil {
+		return nil, err
+	}
+
+	if !models.IsValidInterval(interval) {
+		return nil, fmt.Errorf("%w: invalid interval %q, expected hour, day, week or month", ErrInvalidQuery, interval)
+	}
+
+	buckets := make([]time.Time, 0)
+	for b := s.truncate(start, interval); b.Before(end); b = s.next(b, interval) {
+		buckets = append(buckets, b)
+		if len(buckets) > maxTimeSeriesBuckets {
+			return nil, fmt.Errorf("%w: date range too large for interval %q (max %d buckets)", ErrInvalidQuery, interval, maxTimeSeriesBuckets)
+		}
+	}
+
+	rows, err := s.repo.GetTimeSeries(outletID, start, end, interval, s.location.String())
+	if err != nil {
+		return nil, err
+	}
+
+	// Nilai bucket dari database adalah jam dinding lokal, tempelkan zona toko
+	byBucket := make(map[int64]models.TimeSeriesPoint, len(rows))
+	for _, row := range rows {
+		b := row.Bucket
+		row.Bucket = time.Date(b.Year(), b.Month(), b.Day(), b.Hour(), 0, 0, 0, s.location)
+		byBucket[row.Bucket.Unix()] = row
+	}
+
+	data := make([]models.TimeSeriesPoint, 0, len(buckets))
+	for _, b := range buckets {
+		point, ok := byBucket[b.Unix()]
+		if !ok {
+			point = models.TimeSeriesPoint{Bucket: b}
+		}
+		data = append(data, point)
+	}
+
+	return &models.TimeSeriesReport{
+		Interval:  interval,
+		Timezone:  s.location.String(),
+		StartDate: start.Format(dateLayout),
+		EndDate:   end.AddDate(0, 0, -1).Format(dateLayout),
+		Data:      data,
+	}, nil
+}
+
+// truncate mengikuti aturan date_trunc PostgreSQL: minggu dimulai hari Senin.
+func (s *ReportService) truncate(t time.Time, interval string) time.Time {
+	t = t.In(s.location)
+	switch interval {
+	case models.IntervalHour:
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, s.location)
+	case models.IntervalWeek:
+		offset := (int(t.Weekday()) + 6) % 7
+		return time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, s.location)
+	case models.IntervalMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, s.location)
+	default:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, s.location)
+	}
+}
+
+func (s *ReportService) next(t time.Time, interval string) time.Time {
+	switch interval {
+	case models.IntervalHour:
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, s.location)
+	case models.IntervalWeek:
+		return t.AddDate(0, 0, 7)
+	case models.IntervalMonth:
+		return t.AddDate(0, 1, 0)
+	default:
+		return t.AddDate(0, 0, 1)
+	}
+}
+
+// Compare membandingkan rentang tanggal dengan periode pembanding:
+// previous (periode sebelumnya dengan panjang sama), last_week, last_month atau last_year.
+func (s *ReportService) Compare(outletID int, start_date string, end_date string, compare string) (*models.ReportComparison, error) {
+	if !models.IsValidCompare(compare) {
+		return nil, fmt.Errorf("%w: invalid compare %q, expected previous, last_week, last_month or last_year", ErrInvalidQuery, compare)
+	}
+
+	start, end, err := parseDateRange(start_date, end_date, s.location)
+	if err != nil {
+		return nil, err
+	}
+	prevStart, prevEnd := s.previousPeriod(start, end, compare)
+
+	current, err := s.summary(outletID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	previous, err := s.summary(outletID, prevStart, prevEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	currentSales, err := s.repo.GetProductSales(outletID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	previousSales, err := s.repo.GetProductSales(outletID, prevStart, prevEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ReportComparison{
+		CompareTo:      compare,
+		Timezone:       s.location.String(),
+		Current:        *current,
+		Previous:       *previous,
+		TotalRevenue:   delta(current.TotalRevenue, previous.TotalRevenue),
+		TotalTransaksi: delta(float64(current.TotalTransaksi), float64(previous.TotalTransaksi)),
+		AverageBasket:  delta(current.AverageBasket, previous.AverageBasket),
+		Produk:         compareProducts(currentSales, previousSales),
+	}, nil
+}
+
+// GetConsolidated membuat laporan gabungan semua outlet beserta rincian per outlet.
+func (s *ReportService) GetConsolidated(start_date string, end_date string) (*models.ConsolidatedReport, error) {
+	start, end, err := parseDateRange(start_date, end_date, s.location)
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := s.repo.GetConsolidated(start, end, 10)
+	if err != nil {
+		return nil, err
+	}
+	report.StartDate = start.Format(dateLayout)
+	report.EndDate = end.AddDate(0, 0, -1).Format(dateLayout)
+	return report, nil
+}
+
+// GetProductReport mengembalikan jumlah terjual per produk beserta komponen yang terpakai oleh
+// paket dan resep, sehingga stok bahan yang keluar terlihat di samping produk yang dijual.
+func (s *ReportService) GetProductReport(outletID int, start_date string, end_date string) (*models.ProductReport, error) {
+	start, end, err := parseDateRange(start_date, end_date, s.location)
+	if err != nil {
+		return nil, err
+	}
+
+	sales, err := s.repo.GetProductSales(outletID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	usage, err := s.repo.GetComponentUsage(outletID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ProductReport{
+		StartDate:        start.Format(dateLayout),
+		EndDate:          end.AddDate(0, 0, -1).Format(dateLayout),
+		Terjual:          sales,
+		KomponenTerpakai: usage,
+	}, nil
+}
+
+// GetModifierReport mengembalikan popularitas modifier dalam rentang tanggal, paling sering dipilih lebih dulu.
+func (s *ReportService) GetModifierReport(outletID int, start_date string, end_date string) (*models.ModifierReport, error) {
+	start, end, err := parseDateRange(start_date, end_date, s.location)
+	if err != nil {
+		return nil, err
+	}
+
+	sales, err := s.repo.GetModifierSales(outletID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ModifierReport{
+		StartDate: start.Format(dateLayout),
+		EndDate:   end.AddDate(0, 0, -1).Format(dateLayout),
+		Modifier:  sales,
+	}, nil
+}
+
+func (s *ReportService) summary(outletID int, start time.Time, end time.Time) (*models.PeriodSummary, error) {
+	summary, err := s.repo.GetSummary(outletID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	summary.StartDate = start.Format(dateLayout)
+	summary.EndDate = end.AddDate(0, 0, -1).Format(dateLayout)
+	if summary.TotalTransaksi > 0 {
+		summary.AverageBasket = summary.TotalRevenue / float64(summary.TotalTransaksi)
+	}
+	return summary, nil
 }
 
-func NewReportService(repo *repositories.ReportRepository) *ReportService {
-	return &ReportService{repo: repo}
+func (s *ReportService) previousPeriod(start time.Time, end time.Time, compare string) (time.Time, time.Time) {
+	switch compare {
+	case models.CompareLastWeek:
+		return start.AddDate(0, 0, -7), end.AddDate(0, 0, -7)
+	case models.CompareLastMonth:
+		return shiftPeriod(start, end, -1)
+	case models.CompareLastYear:
+		return shiftPeriod(start, end, -12)
+	default:
+		days := 0
+		for d := start; d.Before(end); d = d.AddDate(0, 0, 1) {
+			days++
+		}
+		return start.AddDate(0, 0, -days), start
+	}
 }
 
-func (s *ReportService) GetReport(start_date string, end_date string) ([]models.Report, error) {
-	return s.repo.GetReport(start_date, end_date)
+// shiftMonths menggeser tanggal sebanyak n bulan tanpa melewati akhir bulan tujuan,
+// misalnya 31 Maret - 1 bulan = 28/29 Februari.
+func shiftMonths(t time.Time, n int) time.Time {
+	first := time.Date(t.Year(), t.Month()+time.Month(n), 1, 0, 0, 0, 0, t.Location())
+	lastDay := first.AddDate(0, 1, -1).Day()
+	day := t.Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return time.Date(first.Year(), first.Month(), day, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+}
+
+// shiftPeriod menggeser periode [start, end) sebanyak n bulan. Periode berisi bulan penuh digeser
+// per bulan, sehingga April dibandingkan dengan seluruh Maret dan Februari dengan seluruh Januari.
+// Untuk periode lain yang digeser adalah hari terakhir periode, bukan end yang eksklusif, agar
+// 29-30 Maret menjadi 28-28 Februari dan bukan rentang kosong.
+func shiftPeriod(start time.Time, end time.Time, n int) (time.Time, time.Time) {
+	if isMonthStart(start) && isMonthStart(end) {
+		return shiftMonths(start, n), shiftMonths(end, n)
+	}
+	return shiftMonths(start, n), shiftMonths(end.AddDate(0, 0, -1), n).AddDate(0, 0, 1)
+}
+
+// isMonthStart memeriksa apakah t tepat pukul 00:00 tanggal 1.
+func isMonthStart(t time.Time) bool {
+	return t.Equal(time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location()))
+}
+
+func delta(current float64, previous float64) models.Delta {
+	d := models.Delta{Absolute: current - previous}
+	if previous != 0 {
+		percent := (current - previous) / previous * 100
+		d.Percent = &percent
+	}
+	return d
+}
+
+func compareProducts(current []models.ProductSales, previous []models.ProductSales) []models.ProductComparison {
+	byID := make(map[int]*models.ProductComparison)
+	result := make([]*models.ProductComparison, 0)
+
+	get := func(sale models.ProductSales) *models.ProductComparison {
+		c, ok := byID[sale.ProductID]
+		if !ok {
+			c = &models.ProductComparison{ProductID: sale.ProductID, Nama: sale.Nama}
+			byID[sale.ProductID] = c
+			result = append(result, c)
+		}
+		return c
+	}
+
+	for _, sale := range current {
+		get(sale).QtyCurrent = sale.QtyTerjual
+	}
+	for _, sale := range previous {
+		get(sale).QtyPrevious = sale.QtyTerjual
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].QtyCurrent > result[j].QtyCurrent
+	})
+
+	products := make([]models.ProductComparison, 0, len(result))
+	for _, c := range result {
+		c.Delta = delta(float64(c.QtyCurrent), float64(c.QtyPrevious))
+		products = append(products, *c)
+	}
+	return products
 }
@@ -0,0 +1,83 @@
+package services
+
+import (
+	"kasir-api/models"
+	"testing"
+	"time"
+)
+
+func date(s string) time.Time {
+	t, err := time.ParseInLocation(dateLayout, s, time.UTC)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestShiftMonths(t *testing.T) {
+	cases := []struct {
+		in   string
+		n    int
+		want string
+	}{
+		{"2026-03-15", -1, "2026-02-15"},
+		{"2026-03-31", -1, "2026-02-28"},
+		{"2024-03-31", -1, "2024-02-29"},
+		{"2026-05-31", -1, "2026-04-30"},
+		{"2026-01-31", -1, "2025-12-31"},
+		{"2024-02-29", -12, "2023-02-28"},
+		{"2026-01-31", 1, "2026-02-28"},
+		{"2026-03-01", -1, "2026-02-01"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.in, func(t *testing.T) {
+			if got := shiftMonths(date(tc.in), tc.n).Format(dateLayout); got != tc.want {
+				t.Fatalf("shiftMonths(%s, %d) = %s, want %s", tc.in, tc.n, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPreviousPeriod(t *testing.T) {
+	s := &ReportService{}
+	// end eksklusif, jadi periode 29-30 Maret ditulis start 29 Maret dan end 31 Maret
+	cases := []struct {
+		name      string
+		start     string
+		end       string
+		compare   string
+		wantStart string
+		wantEnd   string
+	}{
+		{"previous days", "2026-03-10", "2026-03-13", models.ComparePrevious, "2026-03-07", "2026-03-10"},
+		{"last week", "2026-03-10", "2026-03-13", models.CompareLastWeek, "2026-03-03", "2026-03-06"},
+		{"last month mid month", "2026-03-10", "2026-03-13", models.CompareLastMonth, "2026-02-10", "2026-02-13"},
+		{"last month full month", "2026-03-01", "2026-04-01", models.CompareLastMonth, "2026-02-01", "2026-03-01"},
+		{"april against march", "2026-04-01", "2026-05-01", models.CompareLastMonth, "2026-03-01", "2026-04-01"},
+		{"february against january", "2026-02-01", "2026-03-01", models.CompareLastMonth, "2026-01-01", "2026-02-01"},
+		{"two full months", "2026-03-01", "2026-05-01", models.CompareLastMonth, "2026-02-01", "2026-04-01"},
+		{"partial month from the first", "2026-04-01", "2026-04-16", models.CompareLastMonth, "2026-03-01", "2026-03-16"},
+		{"last month clamped to february", "2026-03-29", "2026-03-31", models.CompareLastMonth, "2026-02-28", "2026-03-01"},
+		{"last month ending on the 31st", "2026-05-31", "2026-06-01", models.CompareLastMonth, "2026-04-30", "2026-05-01"},
+		{"last month leap year", "2024-03-30", "2024-04-01", models.CompareLastMonth, "2024-02-29", "2024-03-01"},
+		{"last year leap day", "2024-02-29", "2024-03-01", models.CompareLastYear, "2023-02-28", "2023-03-01"},
+		{"last year full year", "2025-01-01", "2026-01-01", models.CompareLastYear, "2024-01-01", "2025-01-01"},
+		{"last year february", "2025-02-01", "2025-03-01", models.CompareLastYear, "2024-02-01", "2024-03-01"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			start, end := s.previousPeriod(date(tc.start), date(tc.end), tc.compare)
+			if got := start.Format(dateLayout); got != tc.wantStart {
+				t.Errorf("start = %s, want %s", got, tc.wantStart)
+			}
+			if got := end.Format(dateLayout); got != tc.wantEnd {
+				t.Errorf("end = %s, want %s", got, tc.wantEnd)
+			}
+			if !start.Before(end) {
+				t.Errorf("empty period %s - %s", start.Format(dateLayout), end.Format(dateLayout))
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package services
+
+import (
+	"fmt"
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"strconv"
+	"time"
+)
+
+type StockService struct {
+	repo     *repositories.StockRepository
+	location *time.Location
+}
+
+func NewStockService(repo *repositories.StockRepository, location *time.Location) *StockService {
+	return &StockService{repo: repo, location: location}
+}
+
+// GetMovements mengambil buku stok outlet. product_id kosong berarti semua produk.
+func (s *StockService) GetMovements(outletID int, product_id string, start_date string, end_date string) ([]models.StockMovement, error) {
+	productID := 0
+	if product_id != "" {
+		id, err := strconv.Atoi(product_id)
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("%w: invalid product_id %q", ErrInvalidQuery, product_id)
+		}
+		productID = id
+	}
+
+	start, end, err := parseDateRange(start_date, end_date, s.location)
+	if err != nil {
+		return nil, err
+	}
+
+	movements, err := s.repo.GetMovements(outletID, productID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	for i := range movements {
+		movements[i].CreatedAt = movements[i].CreatedAt.In(s.location)
+	}
+	return movements, nil
+}
@@ -0,0 +1,136 @@
+package services
+
+import (
+	"fmt"
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"kasir-api/validation"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	maxSyncBatch       = 500
+	defaultSyncLimit   = 500
+	maxSyncLimit       = 2000
+	syncClockTolerance = 5 * time.Minute
+	// maxSyncAge membatasi umur transaksi offline karena created_at menentukan harga serta masa
+	// berlaku voucher dan kartu hadiah
+	maxSyncAge = 30 * 24 * time.Hour
+)
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+type SyncService struct {
+	transactionRepo *repositories.TransactionRepository
+	syncRepo        *repositories.SyncRepository
+	location        *time.Location
+	loyalty         models.LoyaltyRules
+}
+
+func NewSyncService(transactionRepo *repositories.TransactionRepository, syncRepo *repositories.SyncRepository, location *time.Location, loyalty models.LoyaltyRules) *SyncService {
+	return &SyncService{transactionRepo: transactionRepo, syncRepo: syncRepo, location: location, loyalty: loyalty}
+}
+
+// ApplyBatch menyimpan transaksi offline satu per satu sesuai urutan di batch.
+// Kegagalan satu transaksi tidak membatalkan transaksi lain; hasilnya dilaporkan per item.
+func (s *SyncService) ApplyBatch(outletID int, req models.SyncBatchRequest, audit models.AuditMeta) (*models.SyncBatchResponse, error) {
+	if len(req.Transactions) == 0 {
+		return nil, fmt.Errorf("%w: transactions must not be empty", ErrInvalidInput)
+	}
+	if len(req.Transactions) > maxSyncBatch {
+		return nil, fmt.Errorf("%w: at most %d transactions per batch", ErrInvalidInput, maxSyncBatch)
+	}
+
+	response := &models.SyncBatchResponse{Results: make([]models.SyncResult, 0, len(req.Transactions))}
+	for _, item := range req.Transactions {
+		result := s.apply(outletID, item, audit)
+		switch result.Status {
+		case models.SyncApplied:
+			response.Applied++
+		case models.SyncDuplicate:
+			response.Duplicate++
+		default:
+			response.Rejected++
+		}
+		response.Results = append(response.Results, result)
+	}
+	return response, nil
+}
+
+func (s *SyncService) apply(outletID int, item models.SyncTransaction, audit models.AuditMeta) models.SyncResult {
+	item.ClientUUID = strings.ToLower(strings.TrimSpace(item.ClientUUID))
+	result := models.SyncResult{ClientUUID: item.ClientUUID, Status: models.SyncRejected}
+
+	if !uuidPattern.MatchString(item.ClientUUID) {
+		result.Reason = "invalid client_uuid"
+		return result
+	}
+	if item.CreatedAt.IsZero() {
+		item.CreatedAt = time.Now()
+	}
+	if item.CreatedAt.After(time.Now().Add(syncClockTolerance)) {
+		result.Reason = "created_at is in the future"
+		return result
+	}
+	if item.CreatedAt.Before(time.Now().Add(-maxSyncAge)) {
+		result.Reason = fmt.Sprintf("created_at is older than %d days", int(maxSyncAge.Hours()/24))
+		return result
+	}
+	if err := validation.Checkout(&item.CheckoutRequest).Err(); err != nil {
+		result.Reason = err.Error()
+		return result
+	}
+	if item.CustomerPhone != "" {
+		item.CustomerPhone = NormalizePhone(item.CustomerPhone)
+	}
+	item.VoucherCode = NormalizeCode(item.VoucherCode)
+	item.GiftCardCode = NormalizeCode(item.GiftCardCode)
+
+	transaction, duplicate, err := s.transactionRepo.ApplySynced(outletID, item, s.loyalty, audit)
+	if err != nil {
+		// Pesan ErrInsufficientStock sudah diawali "insufficient stock" sehingga terminal bisa membedakannya
+		result.Reason = err.Error()
+		return result
+	}
+
+	result.TransactionID = transaction.ID
+	if duplicate {
+		result.Status = models.SyncDuplicate
+	} else {
+		result.Status = models.SyncApplied
+	}
+	return result
+}
+
+// GetChanges mengembalikan perubahan katalog sejak cursor. Cursor kosong berarti sinkronisasi penuh.
+func (s *SyncService) GetChanges(outletID int, cursor string, limit string) (*models.SyncChanges, error) {
+	var since int64
+	if cursor != "" {
+		parsed, err := strconv.ParseInt(cursor, 10, 64)
+		if err != nil || parsed < 0 {
+			return nil, fmt.Errorf("%w: invalid cursor %q", ErrInvalidQuery, cursor)
+		}
+		since = parsed
+	}
+
+	size := defaultSyncLimit
+	if limit != "" {
+		parsed, err := strconv.Atoi(limit)
+		if err != nil || parsed <= 0 || parsed > maxSyncLimit {
+			return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidQuery, maxSyncLimit)
+		}
+		size = parsed
+	}
+
+	changes, err := s.syncRepo.GetChanges(outletID, since, size)
+	if err != nil {
+		return nil, err
+	}
+	for i := range changes.Deleted {
+		changes.Deleted[i].DeletedAt = changes.Deleted[i].DeletedAt.In(s.location)
+	}
+	return changes, nil
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// syncedDriver menjawab setiap query dengan satu baris id = 42 milik outlet 1, seolah client_uuid
+// sudah pernah disimpan, dan mencatat argumen query. Begin gagal karena transaksi duplikat tidak boleh dibuat ulang.
+type syncedDriver struct {
+	mu   sync.Mutex
+	args []driver.Value
+}
+
+func (d *syncedDriver) Open(string) (driver.Conn, error) { return syncedConn{d}, nil }
+
+type syncedConn struct{ d *syncedDriver }
+
+func (c syncedConn) Prepare(string) (driver.Stmt, error) { return syncedStmt{c.d}, nil }
+func (syncedConn) Close() error                          { return nil }
+func (syncedConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("duplicate transaction must not open a database transaction")
+}
+
+type syncedStmt struct{ d *syncedDriver }
+
+func (syncedStmt) Close() error  { return nil }
+func (syncedStmt) NumInput() int { return -1 }
+func (syncedStmt) Exec([]driver.Value) (driver.Result, error) {
+	return nil, errors.New("duplicate transaction must not write")
+}
+func (s syncedStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.d.mu.Lock()
+	s.d.args = append(s.d.args, args...)
+	s.d.mu.Unlock()
+	return &syncedRows{}, nil
+}
+
+type syncedRows struct{ done bool }
+
+func (*syncedRows) Columns() []string { return []string{"id", "outlet_id"} }
+func (*syncedRows) Close() error      { return nil }
+func (r *syncedRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = int64(42)
+	dest[1] = int64(1)
+	return nil
+}
+
+var synced = &syncedDriver{}
+
+func init() {
+	sql.Register("synced", synced)
+}
+
+func TestApplyBatch(t *testing.T) {
+	synced.args = nil
+	db, err := sql.Open("synced", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	service := NewSyncService(repositories.NewTransactionRepository(db), nil, time.UTC, models.LoyaltyRules{})
+
+	items := []models.CheckoutItem{{ProductID: 1, Quantity: 1}}
+	cases := []struct {
+		name   string
+		item   models.SyncTransaction
+		status string
+		reason string
+	}{
+		{"already synced", models.SyncTransaction{ClientUUID: " 6F9619FF-8B86-D011-B42D-00C04FC964FF ", CheckoutRequest: models.CheckoutRequest{Items: items}}, models.SyncDuplicate, ""},
+		{"invalid uuid", models.SyncTransaction{ClientUUID: "trx-1", CheckoutRequest: models.CheckoutRequest{Items: items}}, models.SyncRejected, "invalid client_uuid"},
+		{"created in the future", models.SyncTransaction{ClientUUID: "6f9619ff-8b86-d011-b42d-00c04fc964f0", CreatedAt: time.Now().Add(time.Hour), CheckoutRequest: models.CheckoutRequest{Items: items}}, models.SyncRejected, "created_at is in the future"},
+		{"older than the sync window", models.SyncTransaction{ClientUUID: "6f9619ff-8b86-d011-b42d-00c04fc964f2", CreatedAt: time.Now().Add(-maxSyncAge - time.Hour), CheckoutRequest: models.CheckoutRequest{Items: items}}, models.SyncRejected, "created_at is older than 30 days"},
+		{"invalid checkout", models.SyncTransaction{ClientUUID: "6f9619ff-8b86-d011-b42d-00c04fc964f1"}, models.SyncRejected, "validation failed"},
+	}
+	req := models.SyncBatchRequest{}
+	for _, tc := range cases {
+		req.Transactions = append(req.Transactions, tc.item)
+	}
+
+	response, err := service.ApplyBatch(1, req, models.AuditMeta{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.Applied != 0 || response.Duplicate != 1 || response.Rejected != 4 {
+		t.Fatalf("applied/duplicate/rejected = %d/%d/%d, want 0/1/4", response.Applied, response.Duplicate, response.Rejected)
+	}
+	for i, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := response.Results[i]
+			if result.Status != tc.status || !strings.HasPrefix(result.Reason, tc.reason) {
+				t.Fatalf("got %s %q, want %s %q", result.Status, result.Reason, tc.status, tc.reason)
+			}
+		})
+	}
+
+	// Duplikat mengembalikan id transaksi yang sudah ada, dicari dengan client_uuid yang dinormalisasi
+	if got := response.Results[0]; got.TransactionID != 42 || got.ClientUUID != "6f9619ff-8b86-d011-b42d-00c04fc964ff" {
+		t.Fatalf("duplicate result %+v", got)
+	}
+	if len(synced.args) != 1 || synced.args[0] != "6f9619ff-8b86-d011-b42d-00c04fc964ff" {
+		t.Fatalf("client_uuid lookups %v, want only the normalized duplicate", synced.args)
+	}
+}
+
+func TestApplyBatchOtherOutlet(t *testing.T) {
+	db, err := sql.Open("synced", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	service := NewSyncService(repositories.NewTransactionRepository(db), nil, time.UTC, models.LoyaltyRules{})
+
+	req := models.SyncBatchRequest{Transactions: []models.SyncTransaction{{
+		ClientUUID:      "6f9619ff-8b86-d011-b42d-00c04fc964ff",
+		CheckoutRequest: models.CheckoutRequest{Items: []models.CheckoutItem{{ProductID: 1, Quantity: 1}}},
+	}}}
+	response, err := service.ApplyBatch(2, req, models.AuditMeta{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := response.Results[0]
+	if result.Status != models.SyncRejected || result.TransactionID != 0 || !strings.Contains(result.Reason, "another outlet") {
+		t.Fatalf("got %+v, want a rejection without the other outlet's transaction", result)
+	}
+}
+
+func TestApplyBatchLimits(t *testing.T) {
+	service := NewSyncService(nil, nil, time.UTC, models.LoyaltyRules{})
+	cases := []struct {
+		name  string
+		count int
+	}{
+		{"empty", 0},
+		{"too many", maxSyncBatch + 1},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := service.ApplyBatch(1, models.SyncBatchRequest{Transactions: make([]models.SyncTransaction, tc.count)}, models.AuditMeta{})
+			if !errors.Is(err, ErrInvalidInput) {
+				t.Fatalf("got %v, want ErrInvalidInput", err)
+			}
+		})
+	}
+}
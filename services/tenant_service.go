@@ -0,0 +1,181 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"kasir-api/database"
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"net"
+	"regexp"
+	"strings"
+)
+
+// slug dipakai sebagai subdomain dan nama schema, jadi hanya huruf kecil, angka dan tanda hubung
+var tenantSlugPattern = regexp.MustCompile(`^[a-z][a-z0-9-]{2,31}$`)
+
+type TenantService struct {
+	repo       *repositories.TenantRepository
+	dbConn     string
+	baseDomain string
+}
+
+// baseDomain (mis. kasir.example.com) dipakai untuk membaca tenant dari subdomain; kosong berarti tidak dipakai.
+func NewTenantService(repo *repositories.TenantRepository, dbConn string, baseDomain string) *TenantService {
+	return &TenantService{repo: repo, dbConn: dbConn, baseDomain: strings.ToLower(baseDomain)}
+}
+
+func (s *TenantService) GetAll() ([]models.Tenant, error) {
+	return s.repo.GetAll()
+}
+
+func (s *TenantService) GetByID(id int) (*models.Tenant, error) {
+	return s.repo.GetByID(id)
+}
+
+// Provision membuat schema tenant, menjalankan semua migrasi di dalamnya lalu mendaftarkan tenant.
+// API key pemilik tenant diisi ke t.APIKey dan hanya ditampilkan sekali.
+func (s *TenantService) Provision(t *models.Tenant) error {
+	t.Slug = strings.ToLower(strings.TrimSpace(t.Slug))
+	t.Name = strings.TrimSpace(t.Name)
+	t.Address = strings.TrimSpace(t.Address)
+	t.Phone = strings.TrimSpace(t.Phone)
+	t.NPWP = strings.TrimSpace(t.NPWP)
+	t.ReceiptFooter = strings.TrimSpace(t.ReceiptFooter)
+	if !tenantSlugPattern.MatchString(t.Slug) {
+		return fmt.Errorf("%w: slug must be 3-32 characters of lowercase letters, digits or '-', starting with a letter", ErrInvalidInput)
+	}
+	if t.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidInput)
+	}
+
+	_, err := s.repo.GetBySlug(t.Slug)
+	if err == nil {
+		return fmt.Errorf("%w: tenant slug already exists", ErrInvalidInput)
+	}
+	if !errors.Is(err, repositories.ErrTenantNotFound) {
+		return err
+	}
+
+	t.Schema = "tenant_" + strings.ReplaceAll(t.Slug, "-", "_")
+	if err := s.repo.CreateSchema(t.Schema); err != nil {
+		return err
+	}
+
+	if err := s.migrateSchema(t.Schema); err != nil {
+		s.repo.DropSchema(t.Schema)
+		return err
+	}
+
+	apiKey := generateAPIKey()
+	t.APIKeyHash = HashAPIKey(apiKey)
+	if err := s.repo.Create(t); err != nil {
+		s.repo.DropSchema(t.Schema)
+		return err
+	}
+	t.APIKey = apiKey
+	return nil
+}
+
+func (s *TenantService) migrateSchema(schema string) error {
+	db, err := database.OpenSchema(s.dbConn, schema)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	return database.Migrate(db)
+}
+
+// Update mengubah nama, status aktif dan identitas struk; slug dan schema tetap.
+func (s *TenantService) Update(id int, upd models.TenantUpdate) (*models.Tenant, error) {
+	t, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if name := strings.TrimSpace(upd.Name); name != "" {
+		t.Name = name
+	}
+	if upd.Active != nil {
+		t.Active = *upd.Active
+	}
+	if upd.Address != nil {
+		t.Address = strings.TrimSpace(*upd.Address)
+	}
+	if upd.Phone != nil {
+		t.Phone = strings.TrimSpace(*upd.Phone)
+	}
+	if upd.NPWP != nil {
+		t.NPWP = strings.TrimSpace(*upd.NPWP)
+	}
+	if upd.ReceiptFooter != nil {
+		t.ReceiptFooter = strings.TrimSpace(*upd.ReceiptFooter)
+	}
+
+	if err := s.repo.Update(t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// RotateAPIKey mengganti API key pemilik tenant; key lama langsung tidak berlaku.
+func (s *TenantService) RotateAPIKey(id int) (*models.Tenant, error) {
+	apiKey := generateAPIKey()
+	if err := s.repo.SetAPIKeyHash(id, HashAPIKey(apiKey)); err != nil {
+		return nil, err
+	}
+
+	t, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	t.APIKey = apiKey
+	return t, nil
+}
+
+// Resolve menentukan tenant sebuah request, berurutan dari subdomain host, header X-Tenant-ID (slug),
+// lalu API key pemilik tenant. Pengguna kasir harus memakai subdomain atau X-Tenant-ID karena
+// API key mereka tersimpan di schema tenant.
+func (s *TenantService) Resolve(host string, requested string, apiKey string) (*models.Tenant, error) {
+	slug := s.subdomain(host)
+	if slug == "" {
+		slug = strings.ToLower(strings.TrimSpace(requested))
+	}
+
+	var tenant *models.Tenant
+	var err error
+	switch {
+	case slug != "":
+		tenant, err = s.repo.GetBySlug(slug)
+	case apiKey != "":
+		tenant, err = s.repo.GetByAPIKeyHash(HashAPIKey(apiKey))
+	default:
+		return nil, fmt.Errorf("%w: tenant is required (subdomain, X-Tenant-ID or tenant API key)", ErrInvalidInput)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !tenant.Active {
+		return nil, fmt.Errorf("%w: tenant is suspended", ErrForbidden)
+	}
+	return tenant, nil
+}
+
+// subdomain mengambil slug dari host seperti toko-a.kasir.example.com
+func (s *TenantService) subdomain(host string) string {
+	if s.baseDomain == "" {
+		return ""
+	}
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.ToLower(host)
+
+	slug, found := strings.CutSuffix(host, "."+s.baseDomain)
+	if !found || strings.Contains(slug, ".") {
+		return ""
+	}
+	return slug
+}
@@ -3,16 +3,105 @@ package services
 import (
 	"kasir-api/models"
 	"kasir-api/repositories"
+	"kasir-api/validation"
+	"time"
 )
 
 type TransactionService struct {
-	repo *repositories.TransactionRepository
+	repo     *repositories.TransactionRepository
+	location *time.Location
+	loyalty  models.LoyaltyRules
 }
 
-func NewTransactionService(repo *repositories.TransactionRepository) *TransactionService {
-	return &TransactionService{repo: repo}
+func NewTransactionService(repo *repositories.TransactionRepository, location *time.Location, loyalty models.LoyaltyRules) *TransactionService {
+	return &TransactionService{repo: repo, location: location, loyalty: loyalty}
 }
 
-func (s *TransactionService) Checkout(items []models.CheckoutItem) (*models.Transaction, error) {
-	return s.repo.CreateTransaction(items)
+func (s *TransactionService) Checkout(outletID int, req models.CheckoutRequest, audit models.AuditMeta) (*models.Transaction, error) {
+	if err := validation.Checkout(&req).Err(); err != nil {
+		return nil, err
+	}
+	if req.CustomerPhone != "" {
+		req.CustomerPhone = NormalizePhone(req.CustomerPhone)
+	}
+	req.VoucherCode = NormalizeCode(req.VoucherCode)
+	req.GiftCardCode = NormalizeCode(req.GiftCardCode)
+
+	transaction, err := s.repo.CreateTransaction(outletID, req, s.loyalty, audit)
+	if err != nil {
+		return nil, err
+	}
+	transaction.CreatedAt = transaction.CreatedAt.In(s.location)
+	return transaction, nil
+}
+
+// StreamTransactions meneruskan setiap detail transaksi dalam rentang tanggal ke fn,
+// dengan created_at sudah dikonversi ke zona waktu toko.
+func (s *TransactionService) StreamTransactions(outletID int, start_date string, end_date string, fn func(models.Transaction, models.TransactionDetail) error) error {
+	start, end, err := parseDateRange(start_date, end_date, s.location)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.StreamTransactions(outletID, start, end, func(t models.Transaction, d models.TransactionDetail) error {
+		t.CreatedAt = t.CreatedAt.In(s.location)
+		return fn(t, d)
+	})
+}
+
+func (s *TransactionService) GetTransactions(outletID int, start_date string, end_date string) ([]models.Transaction, error) {
+	transactions := make([]models.Transaction, 0)
+	err := s.StreamTransactions(outletID, start_date, end_date, func(t models.Transaction, d models.TransactionDetail) error {
+		last := len(transactions) - 1
+		if last < 0 || transactions[last].ID != t.ID {
+			t.Details = make([]models.TransactionDetail, 0)
+			transactions = append(transactions, t)
+			last++
+		}
+		transactions[last].Details = append(transactions[last].Details, d)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return transactions, nil
+}
+
+func (s *TransactionService) GetByID(outletID int, id int) (*models.Transaction, error) {
+	t, err := s.repo.GetByID(outletID, id)
+	if err != nil {
+		return nil, err
+	}
+	t.CreatedAt = t.CreatedAt.In(s.location)
+	return t, nil
+}
+
+// PrintReceipt menyusun struk transaksi dengan render. Setiap cetak menambah print_count dan
+// cetakan kedua dan seterusnya ditandai sebagai salinan. print_count baru bertambah setelah render
+// berhasil, sehingga struk yang gagal dibuat tidak membuat cetakan berikutnya menjadi salinan.
+// Preview tidak dihitung sebagai cetak.
+func (s *TransactionService) PrintReceipt(outletID int, id int, preview bool, render func(t *models.Transaction, isCopy bool) ([]byte, error)) ([]byte, error) {
+	t, err := s.GetByID(outletID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if preview {
+		printed, err := s.repo.GetPrintCount(outletID, id)
+		if err != nil {
+			return nil, err
+		}
+		return render(t, printed > 0)
+	}
+
+	var body []byte
+	err = s.repo.RecordPrint(outletID, id, func(printed int) error {
+		var err error
+		body, err = render(t, printed > 0)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return body, nil
 }
@@ -0,0 +1,160 @@
+package services
+
+import (
+	"fmt"
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"time"
+)
+
+type TransferService struct {
+	repo     *repositories.TransferRepository
+	location *time.Location
+}
+
+func NewTransferService(repo *repositories.TransferRepository, location *time.Location) *TransferService {
+	return &TransferService{repo: repo, location: location}
+}
+
+// Create membuat dokumen transfer berstatus requested. Pengguna harus terdaftar di outlet asal atau tujuan.
+func (s *TransferService) Create(user *models.User, t *models.StockTransfer) error {
+	if t.FromOutletID == t.ToOutletID {
+		return fmt.Errorf("%w: from_outlet_id and to_outlet_id must differ", ErrInvalidInput)
+	}
+	if len(t.Lines) == 0 {
+		return fmt.Errorf("%w: lines must not be empty", ErrInvalidInput)
+	}
+
+	seen := make(map[int]bool)
+	for _, line := range t.Lines {
+		if line.Quantity <= 0 {
+			return fmt.Errorf("%w: quantity for product id %d must be positive", ErrInvalidInput, line.ProductID)
+		}
+		if seen[line.ProductID] {
+			return fmt.Errorf("%w: product id %d appears more than once", ErrInvalidInput, line.ProductID)
+		}
+		seen[line.ProductID] = true
+	}
+
+	if !user.CanAccess(t.FromOutletID) && !user.CanAccess(t.ToOutletID) {
+		return fmt.Errorf("%w: user is not assigned to outlet %d or %d", ErrForbidden, t.FromOutletID, t.ToOutletID)
+	}
+
+	t.Status = models.TransferRequested
+	if err := s.repo.Create(t); err != nil {
+		return err
+	}
+	s.localize(t)
+	return nil
+}
+
+func (s *TransferService) GetAll(outletID int, status string) ([]models.StockTransfer, error) {
+	switch status {
+	case "", models.TransferRequested, models.TransferShipped, models.TransferReceived, models.TransferDiscrepancy, models.TransferCancelled:
+	default:
+		return nil, fmt.Errorf("%w: invalid transfer status %q", ErrInvalidQuery, status)
+	}
+
+	transfers, err := s.repo.GetAll(outletID, status)
+	if err != nil {
+		return nil, err
+	}
+	for i := range transfers {
+		s.localize(&transfers[i])
+	}
+	return transfers, nil
+}
+
+func (s *TransferService) GetByID(user *models.User, id int) (*models.StockTransfer, error) {
+	t, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if !user.CanAccess(t.FromOutletID) && !user.CanAccess(t.ToOutletID) {
+		return nil, fmt.Errorf("%w: transfer %d belongs to other outlets", ErrForbidden, id)
+	}
+	s.localize(t)
+	return t, nil
+}
+
+// Ship dijalankan oleh outlet asal.
+func (s *TransferService) Ship(user *models.User, id int, req models.TransferQuantities) (*models.StockTransfer, error) {
+	t, err := s.GetByID(user, id)
+	if err != nil {
+		return nil, err
+	}
+	if !user.CanAccess(t.FromOutletID) {
+		return nil, fmt.Errorf("%w: only outlet %d can ship transfer %d", ErrForbidden, t.FromOutletID, id)
+	}
+
+	quantities, err := transferQuantities(t, req)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.Ship(id, quantities); err != nil {
+		return nil, err
+	}
+	return s.GetByID(user, id)
+}
+
+// Receive dijalankan oleh outlet tujuan.
+func (s *TransferService) Receive(user *models.User, id int, req models.TransferQuantities) (*models.StockTransfer, error) {
+	t, err := s.GetByID(user, id)
+	if err != nil {
+		return nil, err
+	}
+	if !user.CanAccess(t.ToOutletID) {
+		return nil, fmt.Errorf("%w: only outlet %d can receive transfer %d", ErrForbidden, t.ToOutletID, id)
+	}
+
+	quantities, err := transferQuantities(t, req)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.Receive(id, quantities); err != nil {
+		return nil, err
+	}
+	return s.GetByID(user, id)
+}
+
+func (s *TransferService) Cancel(user *models.User, id int) (*models.StockTransfer, error) {
+	if _, err := s.GetByID(user, id); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Cancel(id); err != nil {
+		return nil, err
+	}
+	return s.GetByID(user, id)
+}
+
+// transferQuantities memvalidasi jumlah kirim/terima terhadap baris transfer.
+func transferQuantities(t *models.StockTransfer, req models.TransferQuantities) (map[int]float64, error) {
+	lines := make(map[int]bool)
+	for _, line := range t.Lines {
+		lines[line.ProductID] = true
+	}
+
+	quantities := make(map[int]float64)
+	for _, item := range req.Lines {
+		if !lines[item.ProductID] {
+			return nil, fmt.Errorf("%w: product id %d is not part of transfer %d", ErrInvalidInput, item.ProductID, t.ID)
+		}
+		if item.Quantity < 0 {
+			return nil, fmt.Errorf("%w: quantity for product id %d must not be negative", ErrInvalidInput, item.ProductID)
+		}
+		quantities[item.ProductID] = item.Quantity
+	}
+	return quantities, nil
+}
+
+func (s *TransferService) localize(t *models.StockTransfer) {
+	t.CreatedAt = t.CreatedAt.In(s.location)
+	if t.ShippedAt != nil {
+		shippedAt := t.ShippedAt.In(s.location)
+		t.ShippedAt = &shippedAt
+	}
+	if t.ReceivedAt != nil {
+		receivedAt := t.ReceivedAt.In(s.location)
+		t.ReceivedAt = &receivedAt
+	}
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"sort"
+	"strings"
+)
+
+type UserService struct {
+	repo         *repositories.UserRepository
+	ownerKeyHash string
+}
+
+// ownerKeyHash adalah hash API key pemilik (API_KEY pada mode tunggal, key tenant pada mode multi-tenant).
+func NewUserService(repo *repositories.UserRepository, ownerKeyHash string) *UserService {
+	return &UserService{repo: repo, ownerKeyHash: ownerKeyHash}
+}
+
+// HashAPIKey menghasilkan hash yang disimpan di database; API key asli tidak pernah disimpan.
+func HashAPIKey(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(sum[:])
+}
+
+func generateAPIKey() string {
+	b := make([]byte, 24)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Authenticate mencari pengguna pemilik API key. Key pemilik menghasilkan pengguna owner,
+// key lain dicari di tabel users. Mengembalikan nil jika key tidak dikenal.
+func (s *UserService) Authenticate(apiKey string) (*models.User, error) {
+	hash := HashAPIKey(apiKey)
+	if s.ownerKeyHash != "" && subtle.ConstantTimeCompare([]byte(hash), []byte(s.ownerKeyHash)) == 1 {
+		return &models.User{Name: "owner", Role: models.RoleOwner}, nil
+	}
+	return s.repo.GetByAPIKeyHash(hash)
+}
+
+func (s *UserService) GetAll() ([]models.User, error) {
+	return s.repo.GetAll()
+}
+
+func (s *UserService) GetByID(id int) (*models.User, error) {
+	return s.repo.GetByID(id)
+}
+
+// Create menyimpan pengguna baru dan mengisi u.APIKey dengan key yang baru dibuat.
+func (s *UserService) Create(u *models.User, audit models.AuditMeta) error {
+	if err := validateUser(u); err != nil {
+		return err
+	}
+
+	apiKey := generateAPIKey()
+	if err := s.repo.Create(u, HashAPIKey(apiKey), audit); err != nil {
+		return err
+	}
+	u.APIKey = apiKey
+	return nil
+}
+
+func (s *UserService) Update(u *models.User, audit models.AuditMeta) error {
+	if err := validateUser(u); err != nil {
+		return err
+	}
+	return s.repo.Update(u, audit)
+}
+
+// RotateAPIKey mengganti API key pengguna; key lama langsung tidak berlaku.
+func (s *UserService) RotateAPIKey(id int, audit models.AuditMeta) (*models.User, error) {
+	apiKey := generateAPIKey()
+	if err := s.repo.SetAPIKeyHash(id, HashAPIKey(apiKey), audit); err != nil {
+		return nil, err
+	}
+
+	u, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	u.APIKey = apiKey
+	return u, nil
+}
+
+func (s *UserService) Delete(id int, audit models.AuditMeta) error {
+	return s.repo.Delete(id, audit)
+}
+
+func validateUser(u *models.User) error {
+	u.Name = strings.TrimSpace(u.Name)
+	if u.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidInput)
+	}
+	if u.Role == "" {
+		u.Role = models.RoleCashier
+	}
+	if u.Role != models.RoleOwner && u.Role != models.RoleCashier {
+		return fmt.Errorf("%w: role must be owner or cashier", ErrInvalidInput)
+	}
+	if u.Role == models.RoleCashier && len(u.OutletIDs) == 0 {
+		return fmt.Errorf("%w: cashier must be assigned to at least one outlet", ErrInvalidInput)
+	}
+
+	// Buang outlet ganda supaya penugasan tetap unik
+	sort.Ints(u.OutletIDs)
+	unique := make([]int, 0, len(u.OutletIDs))
+	for i, id := range u.OutletIDs {
+		if i == 0 || id != u.OutletIDs[i-1] {
+			unique = append(unique, id)
+		}
+	}
+	u.OutletIDs = unique
+	return nil
+}
@@ -0,0 +1,117 @@
+package services
+
+import (
+	"crypto/rand"
+	"fmt"
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"strings"
+)
+
+type VoucherService struct {
+	repo *repositories.VoucherRepository
+}
+
+func NewVoucherService(repo *repositories.VoucherRepository) *VoucherService {
+	return &VoucherService{repo: repo}
+}
+
+func (s *VoucherService) GetVouchers() ([]models.Voucher, error) {
+	return s.repo.GetVouchers()
+}
+
+func (s *VoucherService) GetVoucherByCode(code string) (*models.Voucher, error) {
+	return s.repo.GetVoucherByCode(NormalizeCode(code))
+}
+
+func (s *VoucherService) GetVoucherRedemptions(code string) ([]models.Redemption, error) {
+	code = NormalizeCode(code)
+	if _, err := s.repo.GetVoucherByCode(code); err != nil {
+		return nil, err
+	}
+	return s.repo.GetVoucherRedemptions(code)
+}
+
+// CreateVoucher memvalidasi dan menyimpan voucher baru. Kode dibuat otomatis jika kosong.
+func (s *VoucherService) CreateVoucher(v *models.Voucher, audit models.AuditMeta) error {
+	v.Code = NormalizeCode(v.Code)
+	if v.Code == "" {
+		v.Code = generateCode(8)
+	}
+
+	switch v.Type {
+	case models.VoucherFixed:
+	case models.VoucherPercent:
+		if v.Value > 100 {
+			return fmt.Errorf("%w: percent voucher value must be between 1 and 100", ErrInvalidInput)
+		}
+	default:
+		return fmt.Errorf("%w: voucher type must be fixed or percent", ErrInvalidInput)
+	}
+
+	switch {
+	case v.Value <= 0:
+		return fmt.Errorf("%w: voucher value must be positive", ErrInvalidInput)
+	case v.MinSpend < 0:
+		return fmt.Errorf("%w: min_spend must not be negative", ErrInvalidInput)
+	case v.UsageLimit != nil && *v.UsageLimit <= 0:
+		return fmt.Errorf("%w: usage_limit must be positive", ErrInvalidInput)
+	case v.MaxDiscount != nil && *v.MaxDiscount <= 0:
+		return fmt.Errorf("%w: max_discount must be positive", ErrInvalidInput)
+	case v.StartsAt != nil && v.ExpiresAt != nil && !v.ExpiresAt.After(*v.StartsAt):
+		return fmt.Errorf("%w: expires_at must be after starts_at", ErrInvalidInput)
+	}
+
+	if v.ProductIDs == nil {
+		v.ProductIDs = []int{}
+	}
+	if v.CategoryIDs == nil {
+		v.CategoryIDs = []int{}
+	}
+	return s.repo.CreateVoucher(v, audit)
+}
+
+func (s *VoucherService) GetGiftCards() ([]models.GiftCard, error) {
+	return s.repo.GetGiftCards()
+}
+
+func (s *VoucherService) GetGiftCardByCode(code string) (*models.GiftCard, error) {
+	return s.repo.GetGiftCardByCode(NormalizeCode(code))
+}
+
+func (s *VoucherService) GetGiftCardRedemptions(code string) ([]models.Redemption, error) {
+	code = NormalizeCode(code)
+	if _, err := s.repo.GetGiftCardByCode(code); err != nil {
+		return nil, err
+	}
+	return s.repo.GetGiftCardRedemptions(code)
+}
+
+// CreateGiftCard menerbitkan kartu hadiah dengan saldo awal. Kode dibuat otomatis jika kosong.
+func (s *VoucherService) CreateGiftCard(g *models.GiftCard, audit models.AuditMeta) error {
+	g.Code = NormalizeCode(g.Code)
+	if g.Code == "" {
+		g.Code = generateCode(16)
+	}
+	if g.InitialBalance <= 0 {
+		return fmt.Errorf("%w: initial_balance must be positive", ErrInvalidInput)
+	}
+	return s.repo.CreateGiftCard(g, audit)
+}
+
+// NormalizeCode menyeragamkan kode voucher dan kartu hadiah menjadi huruf besar tanpa spasi.
+func NormalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// Tanpa karakter yang mirip (0/O, 1/I) supaya mudah diketik kasir
+const codeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+func generateCode(length int) string {
+	b := make([]byte, length)
+	rand.Read(b)
+	for i := range b {
+		b[i] = codeAlphabet[int(b[i])%len(codeAlphabet)]
+	}
+	return string(b)
+}
@@ -0,0 +1,72 @@
+// Package storage menyimpan file (misalnya foto produk) berdasarkan key. Local menyimpan di disk;
+// penyimpanan lain seperti S3 cukup mengimplementasikan Storage.
+package storage
+
+import (
+	"io"
+	"kasir-api/apperror"
+	"path"
+	"strings"
+	"time"
+)
+
+var ErrNotFound = apperror.NotFound("file not found")
+
+// Storage adalah tempat penyimpanan file. Key memakai pemisah "/" seperti path,
+// contoh "produk/12/3f9a1c2b.jpg".
+type Storage interface {
+	// Put menyimpan isi r di key, menimpa file lama dengan key yang sama.
+	Put(key string, r io.Reader, contentType string) error
+	// Get membuka file di key. Pemanggil wajib menutup Object.Body.
+	Get(key string) (*Object, error)
+	// Delete menghapus file di key. File yang tidak ada tidak dianggap error.
+	Delete(key string) error
+}
+
+// Object adalah file yang dibaca dari Storage.
+type Object struct {
+	Body        io.ReadCloser
+	ContentType string
+	Size        int64
+	ModTime     time.Time
+}
+
+// ValidKey memastikan key relatif, tanpa ".." dan tanpa bagian kosong,
+// sehingga tidak bisa keluar dari folder atau prefix storage.
+func ValidKey(key string) bool {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return false
+	}
+	return path.Clean(key) == key && key != "." && !strings.HasPrefix(key, "../") && key != ".."
+}
+
+// WithPrefix membungkus s sehingga semua key berada di bawah prefix, contoh satu folder per tenant.
+func WithPrefix(s Storage, prefix string) Storage {
+	return &prefixed{store: s, prefix: strings.Trim(prefix, "/") + "/"}
+}
+
+type prefixed struct {
+	store  Storage
+	prefix string
+}
+
+func (p *prefixed) Put(key string, r io.Reader, contentType string) error {
+	if !ValidKey(key) {
+		return ErrNotFound
+	}
+	return p.store.Put(p.prefix+key, r, contentType)
+}
+
+func (p *prefixed) Get(key string) (*Object, error) {
+	if !ValidKey(key) {
+		return nil, ErrNotFound
+	}
+	return p.store.Get(p.prefix + key)
+}
+
+func (p *prefixed) Delete(key string) error {
+	if !ValidKey(key) {
+		return ErrNotFound
+	}
+	return p.store.Delete(p.prefix + key)
+}
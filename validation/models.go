@@ -0,0 +1,191 @@
+package validation
+
+import (
+	"fmt"
+	"kasir-api/models"
+	"sort"
+	"strings"
+)
+
+// Product memeriksa field produk yang bisa dicek tanpa database. Keberadaan kategori
+// diperiksa oleh service. Unit kosong berarti satuan belum diketahui (misalnya baris impor untuk
+// produk lama), sehingga kecocokan stok dengan satuan tidak diperiksa.
+func Product(p *models.Product) *Validator {
+	v := New()
+	v.Required("name", p.Name)
+	v.Check(len(p.SKU) <= 64, "sku", CodeInvalid, "sku must be at most 64 characters")
+	v.NotNegative("price", p.Price)
+	v.NotNegative("stock", p.Stock)
+	v.Check(p.CategoryID >= 0, "category_id", CodeInvalid, "category_id must not be negative")
+
+	if _, ok := models.Units[p.Unit]; !ok && p.Unit != "" {
+		v.Add("unit", CodeInvalid, fmt.Sprintf("unit must be one of %s", unitCodes()))
+	} else if p.Unit != "" {
+		v.Check(models.ValidQuantity(p.Unit, p.Stock), "stock", CodeInvalid, quantityMessage("stock", p.Unit))
+	}
+
+	seen := map[string]bool{p.Unit: true}
+	for i, u := range p.Units {
+		if _, ok := models.Units[u.Unit]; !ok {
+			v.Add(Index("units", i, "unit"), CodeInvalid, fmt.Sprintf("unit must be one of %s", unitCodes()))
+		} else if seen[u.Unit] {
+			v.Add(Index("units", i, "unit"), CodeDuplicate, fmt.Sprintf("unit %s is already the base unit or listed", u.Unit))
+		}
+		seen[u.Unit] = true
+		v.Positive(Index("units", i, "factor"), u.Factor)
+		v.Check(models.ValidQuantity("", u.Factor), Index("units", i, "factor"), CodeInvalid,
+			fmt.Sprintf("factor must have at most %d decimals", models.QuantityDecimals))
+		if u.Price != nil {
+			v.NotNegative(Index("units", i, "price"), float64(*u.Price))
+		}
+	}
+
+	switch p.Type {
+	case "", models.ProductStandard:
+		v.Check(len(p.Components) == 0, "components", CodeInvalid, "components are only allowed for bundle or recipe products")
+	case models.ProductBundle, models.ProductRecipe:
+		v.Check(len(p.Components) > 0, "components", CodeRequired, fmt.Sprintf("a %s needs at least one component", p.Type))
+	default:
+		v.Add("type", CodeInvalid, "type must be one of standard, bundle, recipe")
+	}
+	components := make(map[int]int)
+	for i, c := range p.Components {
+		if c.ProductID <= 0 {
+			v.Add(Index("components", i, "product_id"), CodeRequired, "product_id is required")
+		} else if first, ok := components[c.ProductID]; ok {
+			v.Add(Index("components", i, "product_id"), CodeDuplicate, fmt.Sprintf("product id %d already listed in components[%d]", c.ProductID, first))
+		} else if c.ProductID == p.ID {
+			v.Add(Index("components", i, "product_id"), CodeInvalid, "a product cannot be its own component")
+		} else {
+			components[c.ProductID] = i
+		}
+		v.Positive(Index("components", i, "quantity"), c.Quantity)
+		v.Check(models.ValidQuantity("", c.Quantity), Index("components", i, "quantity"), CodeInvalid,
+			fmt.Sprintf("quantity must have at most %d decimals", models.QuantityDecimals))
+	}
+
+	modifierGroups(v, p.ModifierGroups)
+	return v
+}
+
+// modifierGroups memeriksa kelompok modifier produk: nama kelompok unik di produk, nama modifier unik
+// di kelompok, batas pilihan 0 <= min <= max dengan max minimal 1, dan harga tidak negatif.
+func modifierGroups(v *Validator, groups []models.ModifierGroup) {
+	groupNames := make(map[string]int)
+	groupIDs := make(map[int]bool)
+	modifierIDs := make(map[int]bool)
+	for i, g := range groups {
+		field := func(sub string) string { return Index("modifier_groups", i, sub) }
+		v.Required(field("name"), g.Name)
+		if first, ok := groupNames[strings.ToLower(g.Name)]; ok && g.Name != "" {
+			v.Add(field("name"), CodeDuplicate, fmt.Sprintf("modifier group %s already listed in modifier_groups[%d]", g.Name, first))
+		} else {
+			groupNames[strings.ToLower(g.Name)] = i
+		}
+		if g.ID < 0 || (g.ID > 0 && groupIDs[g.ID]) {
+			v.Add(field("id"), CodeInvalid, fmt.Sprintf("modifier group id %d is invalid or listed twice", g.ID))
+		}
+		groupIDs[g.ID] = true
+
+		v.NotNegative(field("min"), float64(g.Min))
+		if g.Max < 1 {
+			v.Add(field("max"), CodeMin, "max must be at least 1")
+		} else {
+			v.Check(g.Max >= g.Min, field("max"), CodeInvalid, "max must not be less than min")
+		}
+		if len(g.Modifiers) == 0 {
+			v.Add(field("modifiers"), CodeRequired, "a modifier group needs at least one modifier")
+		} else {
+			v.Check(g.Min <= len(g.Modifiers), field("min"), CodeInvalid, "min must not exceed the number of modifiers")
+		}
+
+		names := make(map[string]int)
+		for j, m := range g.Modifiers {
+			mField := func(sub string) string { return Index(field("modifiers"), j, sub) }
+			v.Required(mField("name"), m.Name)
+			if first, ok := names[strings.ToLower(m.Name)]; ok && m.Name != "" {
+				v.Add(mField("name"), CodeDuplicate, fmt.Sprintf("modifier %s already listed in modifiers[%d]", m.Name, first))
+			} else {
+				names[strings.ToLower(m.Name)] = j
+			}
+			if m.ID < 0 || (m.ID > 0 && modifierIDs[m.ID]) {
+				v.Add(mField("id"), CodeInvalid, fmt.Sprintf("modifier id %d is invalid or listed twice", m.ID))
+			}
+			modifierIDs[m.ID] = true
+			v.NotNegative(mField("price"), float64(m.Price))
+		}
+	}
+}
+
+// lineKey membedakan item checkout berdasarkan produk, satuan dan modifier (tanpa melihat urutan).
+func lineKey(item models.CheckoutItem) string {
+	modifiers := append([]int(nil), item.Modifiers...)
+	sort.Ints(modifiers)
+	return fmt.Sprint(item.ProductID, item.Unit, modifiers)
+}
+
+// quantityMessage menjelaskan aturan kuantitas untuk satuan unit.
+func quantityMessage(field string, unit string) string {
+	if !models.Units[unit].AllowDecimal {
+		return fmt.Sprintf("%s must be a whole number for unit %s", field, unit)
+	}
+	return fmt.Sprintf("%s must have at most %d decimals", field, models.QuantityDecimals)
+}
+
+func unitCodes() string {
+	codes := make([]string, 0, len(models.Units))
+	for code := range models.Units {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return strings.Join(codes, ", ")
+}
+
+// Category memeriksa data kategori.
+func Category(c *models.Categories) *Validator {
+	v := New()
+	v.Required("name", c.Name)
+	return v
+}
+
+// Checkout memeriksa request checkout: minimal satu item dengan jumlah lebih dari nol. Produk yang
+// sama boleh muncul lebih dari sekali hanya dengan satuan atau modifier berbeda. Kecocokan satuan dan
+// modifier dengan produk diperiksa saat checkout.
+func Checkout(req *models.CheckoutRequest) *Validator {
+	v := New()
+	v.Check(len(req.Items) > 0, "items", CodeRequired, "items must not be empty")
+
+	seen := make(map[string]int)
+	for i, item := range req.Items {
+		key := lineKey(item)
+		if item.ProductID <= 0 {
+			v.Add(Index("items", i, "product_id"), CodeRequired, "product_id is required")
+		} else if first, ok := seen[key]; ok {
+			v.Add(Index("items", i, "product_id"), CodeDuplicate,
+				fmt.Sprintf("product id %d with the same unit and modifiers already listed in items[%d], combine the quantity", item.ProductID, first))
+		} else {
+			seen[key] = i
+		}
+		modifiers := make(map[int]bool, len(item.Modifiers))
+		for _, id := range item.Modifiers {
+			if id <= 0 || modifiers[id] {
+				v.Add(Index("items", i, "modifiers"), CodeInvalid, fmt.Sprintf("modifier id %d is invalid or listed twice", id))
+				break
+			}
+			modifiers[id] = true
+		}
+		v.Positive(Index("items", i, "quantity"), item.Quantity)
+		v.Check(models.ValidQuantity("", item.Quantity), Index("items", i, "quantity"), CodeInvalid,
+			fmt.Sprintf("quantity must have at most %d decimals", models.QuantityDecimals))
+		if item.Unit != "" {
+			_, ok := models.Units[item.Unit]
+			v.Check(ok, Index("items", i, "unit"), CodeInvalid, fmt.Sprintf("unit must be one of %s", unitCodes()))
+		}
+	}
+
+	if req.CustomerID != nil {
+		v.Check(*req.CustomerID > 0, "customer_id", CodeInvalid, "customer_id must be greater than 0")
+	}
+	v.NotNegative("redeem_points", float64(req.RedeemPoints))
+	return v
+}
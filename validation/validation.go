@@ -0,0 +1,90 @@
+// Package validation memeriksa data request dan mengumpulkan semua kesalahan per field,
+// sehingga frontend bisa menandai setiap input yang salah sekaligus.
+package validation
+
+import (
+	"fmt"
+	"kasir-api/apperror"
+	"kasir-api/models"
+	"strings"
+)
+
+// Kode kesalahan field
+const (
+	CodeRequired  = "required"
+	CodeMin       = "min"
+	CodeDuplicate = "duplicate"
+	CodeNotFound  = "not_found"
+	CodeInvalid   = "invalid"
+)
+
+// Errors adalah kumpulan kesalahan field, dikirim sebagai details pada respons 422.
+type Errors []models.FieldError
+
+func (e Errors) String() string {
+	messages := make([]string, len(e))
+	for i, fe := range e {
+		messages[i] = fe.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Validator mengumpulkan kesalahan field satu per satu.
+type Validator struct {
+	errors Errors
+}
+
+func New() *Validator {
+	return &Validator{}
+}
+
+// Add menambahkan kesalahan pada field.
+func (v *Validator) Add(field string, code string, message string) {
+	v.errors = append(v.errors, models.FieldError{Field: field, Code: code, Message: message})
+}
+
+// Check menambahkan kesalahan jika ok bernilai false.
+func (v *Validator) Check(ok bool, field string, code string, message string) {
+	if !ok {
+		v.Add(field, code, message)
+	}
+}
+
+// Required memeriksa string yang tidak boleh kosong.
+func (v *Validator) Required(field string, value string) {
+	v.Check(strings.TrimSpace(value) != "", field, CodeRequired, field+" is required")
+}
+
+// NotNegative memeriksa angka yang tidak boleh kurang dari nol.
+func (v *Validator) NotNegative(field string, value float64) {
+	v.Check(value >= 0, field, CodeMin, field+" must not be negative")
+}
+
+// Positive memeriksa angka yang harus lebih dari nol.
+func (v *Validator) Positive(field string, value float64) {
+	v.Check(value > 0, field, CodeMin, field+" must be greater than 0")
+}
+
+// Valid mengembalikan true jika belum ada kesalahan.
+func (v *Validator) Valid() bool {
+	return len(v.errors) == 0
+}
+
+// Errors mengembalikan kesalahan yang terkumpul.
+func (v *Validator) Errors() Errors {
+	return v.errors
+}
+
+// Err mengembalikan apperror dengan code validation_failed dan details berisi Errors jika ada
+// kesalahan, atau nil.
+func (v *Validator) Err() error {
+	if v.Valid() {
+		return nil
+	}
+	return apperror.Validation("validation failed: "+v.errors.String(), v.errors)
+}
+
+// Index membentuk nama field elemen slice, contoh Index("items", 1, "quantity") menjadi "items[1].quantity".
+func Index(field string, i int, sub string) string {
+	return fmt.Sprintf("%s[%d].%s", field, i, sub)
+}
@@ -0,0 +1,172 @@
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"kasir-api/apperror"
+	"kasir-api/models"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// fields menyingkat kesalahan menjadi "field:code" agar mudah dibandingkan
+func fields(errs Errors) []string {
+	out := make([]string, len(errs))
+	for i, fe := range errs {
+		out[i] = fe.Field + ":" + fe.Code
+	}
+	return out
+}
+
+func TestValidatorErr(t *testing.T) {
+	v := New()
+	if err := v.Err(); err != nil {
+		t.Fatalf("valid validator returned %v", err)
+	}
+
+	v.Required("name", "  ")
+	v.Positive(Index("items", 1, "quantity"), 0)
+	v.NotNegative("price", -1)
+	v.Check(true, "ignored", CodeInvalid, "never added")
+
+	err := v.Err()
+	var appErr *apperror.Error
+	if !errors.As(err, &appErr) {
+		t.Fatalf("got %T, want *apperror.Error", err)
+	}
+	if appErr.Code != apperror.CodeValidation {
+		t.Fatalf("code = %s, want %s", appErr.Code, apperror.CodeValidation)
+	}
+	want := "validation failed: name is required; items[1].quantity must be greater than 0; price must not be negative"
+	if appErr.Message != want {
+		t.Fatalf("message = %q, want %q", appErr.Message, want)
+	}
+	details, ok := appErr.Details.(Errors)
+	if !ok {
+		t.Fatalf("details is %T, want Errors", appErr.Details)
+	}
+	wantFields := []string{"name:required", "items[1].quantity:min", "price:min"}
+	if got := fields(details); !reflect.DeepEqual(got, wantFields) {
+		t.Fatalf("details = %v, want %v", got, wantFields)
+	}
+}
+
+// Bentuk respons 422 yang dibaca frontend
+func TestValidationResponse(t *testing.T) {
+	v := New()
+	v.Add(Index("items", 0, "unit"), CodeInvalid, "unit must be one of box, kg, liter, pcs")
+
+	rec := httptest.NewRecorder()
+	apperror.Write(rec, v.Err())
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusUnprocessableEntity)
+	}
+
+	var body struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+		Details []struct {
+			Field   string `json:"field"`
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"details"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Code != string(apperror.CodeValidation) || len(body.Details) != 1 {
+		t.Fatalf("unexpected body %s", rec.Body.String())
+	}
+	if d := body.Details[0]; d.Field != "items[0].unit" || d.Code != CodeInvalid || d.Message == "" {
+		t.Fatalf("unexpected detail %+v", d)
+	}
+}
+
+func TestProduct(t *testing.T) {
+	cases := []struct {
+		name    string
+		product models.Product
+		want    []string
+	}{
+		{"valid", models.Product{Name: "Teh", Price: 5000, Stock: 10, Unit: models.UnitPcs}, nil},
+		{"missing name and negative price", models.Product{Price: -1}, []string{"name:required", "price:min"}},
+		{"unknown unit", models.Product{Name: "Teh", Unit: "lusin"}, []string{"unit:invalid"}},
+		{"fractional stock for pcs", models.Product{Name: "Teh", Stock: 1.5, Unit: models.UnitPcs}, []string{"stock:invalid"}},
+		{"fractional stock for kg", models.Product{Name: "Gula", Stock: 1.25, Unit: models.UnitKg}, nil},
+		{"duplicate sale unit", models.Product{Name: "Teh", Unit: models.UnitPcs, Units: []models.ProductUnit{
+			{Unit: models.UnitPcs, Factor: 1},
+		}}, []string{"units[0].unit:duplicate"}},
+		{"zero factor", models.Product{Name: "Teh", Unit: models.UnitPcs, Units: []models.ProductUnit{
+			{Unit: models.UnitBox, Factor: 0},
+		}}, []string{"units[0].factor:min"}},
+		{"bundle without components", models.Product{Name: "Paket", Type: models.ProductBundle}, []string{"components:required"}},
+		{"components on standard product", models.Product{Name: "Teh", Components: []models.ProductComponent{
+			{ProductID: 2, Quantity: 1},
+		}}, []string{"components:invalid"}},
+		{"duplicate component", models.Product{Name: "Paket", Type: models.ProductBundle, Components: []models.ProductComponent{
+			{ProductID: 2, Quantity: 1}, {ProductID: 2, Quantity: 2},
+		}}, []string{"components[1].product_id:duplicate"}},
+		{"unknown type", models.Product{Name: "Teh", Type: "combo"}, []string{"type:invalid"}},
+		{"modifier group limits", models.Product{Name: "Kopi", ModifierGroups: []models.ModifierGroup{
+			{Name: "Gula", Min: 2, Max: 1, Modifiers: []models.Modifier{{Name: "Sedikit"}}},
+		}}, []string{"modifier_groups[0].max:invalid", "modifier_groups[0].min:invalid"}},
+		{"duplicate modifier", models.Product{Name: "Kopi", ModifierGroups: []models.ModifierGroup{
+			{Name: "Gula", Max: 1, Modifiers: []models.Modifier{{Name: "Sedikit"}, {Name: "sedikit"}}},
+		}}, []string{"modifier_groups[0].modifiers[1].name:duplicate"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := fields(Product(&tc.product).Errors())
+			if len(got) == 0 && len(tc.want) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCheckout(t *testing.T) {
+	customer := 0
+	cases := []struct {
+		name string
+		req  models.CheckoutRequest
+		want []string
+	}{
+		{"valid", models.CheckoutRequest{Items: []models.CheckoutItem{{ProductID: 1, Quantity: 2}}}, nil},
+		{"no items", models.CheckoutRequest{}, []string{"items:required"}},
+		{"missing product and quantity", models.CheckoutRequest{Items: []models.CheckoutItem{{}}},
+			[]string{"items[0].product_id:required", "items[0].quantity:min"}},
+		{"same line twice", models.CheckoutRequest{Items: []models.CheckoutItem{
+			{ProductID: 1, Quantity: 1, Modifiers: []int{2, 3}}, {ProductID: 1, Quantity: 1, Modifiers: []int{3, 2}},
+		}}, []string{"items[1].product_id:duplicate"}},
+		{"same product other unit", models.CheckoutRequest{Items: []models.CheckoutItem{
+			{ProductID: 1, Quantity: 1}, {ProductID: 1, Quantity: 1, Unit: models.UnitBox},
+		}}, nil},
+		{"modifier listed twice", models.CheckoutRequest{Items: []models.CheckoutItem{
+			{ProductID: 1, Quantity: 1, Modifiers: []int{4, 4}},
+		}}, []string{"items[0].modifiers:invalid"}},
+		{"too many decimals and unknown unit", models.CheckoutRequest{Items: []models.CheckoutItem{
+			{ProductID: 1, Quantity: 0.0001, Unit: "lusin"},
+		}}, []string{"items[0].quantity:invalid", "items[0].unit:invalid"}},
+		{"invalid customer and points", models.CheckoutRequest{
+			Items: []models.CheckoutItem{{ProductID: 1, Quantity: 1}}, CustomerID: &customer, RedeemPoints: -5,
+		}, []string{"customer_id:invalid", "redeem_points:min"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := fields(Checkout(&tc.req).Errors())
+			if len(got) == 0 && len(tc.want) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}